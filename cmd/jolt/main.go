@@ -2,16 +2,30 @@ package main
 
 import (
 	"bufio"
+	"bytes"
+	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/chandan-cmd-dev/jolt-go/jolt"
+	"github.com/chandan-cmd-dev/jolt-go/jolt/yaml"
+	"github.com/chandan-cmd-dev/jolt-go/joltschema"
 )
 
 func main() {
 	var mode string
-	flag.StringVar(&mode, "mode", "example", "example|encode|decode")
+	var id string
+	var format string
+	var stream bool
+	flag.StringVar(&mode, "mode", "example", "example|encode|decode|infer")
+	flag.StringVar(&id, "id", "", "schema id for -mode infer")
+	flag.StringVar(&format, "format", "json", "json|json5|text|ndjson|yaml: what -mode encode reads and -mode decode writes (encode falls back to json5 when the input is not JSON; ndjson is one frame per line)")
+	flag.BoolVar(&stream, "stream", false, "transcode JSON token by token instead of decoding it whole (implied by -format ndjson); with -format yaml, one JOLT-B frame per YAML document")
 	flag.Parse()
 
 	switch mode {
@@ -25,12 +39,48 @@ func main() {
 		bin, _ := jolt.EncodeBinary(env)
 		fmt.Println("JOLT-B size:", len(bin))
 	case "encode":
+		if stream && format != "yaml" || format == "ndjson" {
+			opts := jolt.TranscodeOptions{NDJSON: format == "ndjson"}
+			if err := jolt.TranscodeJSONToBinaryWith(os.Stdout, os.Stdin, opts); err != nil {
+				panic(err)
+			}
+			return
+		}
 		data, err := ioReadAll(os.Stdin)
-		if err != nil {
+		if err != nil && err != io.EOF {
 			panic(err)
 		}
 		var v any
-		if err := jolt.UnmarshalJSONWithComments(data, &v); err != nil {
+		switch format {
+		case "yaml":
+			if stream {
+				docs, err := yaml.UnmarshalAll(data)
+				if err != nil {
+					panic(err)
+				}
+				for _, doc := range docs {
+					b, err := jolt.EncodeBinary(doc)
+					if err != nil {
+						panic(err)
+					}
+					if err := jolt.WriteFrame(os.Stdout, b); err != nil {
+						panic(err)
+					}
+				}
+				return
+			}
+			v, err = yaml.Unmarshal(data)
+		case "text":
+			v, err = jolt.ParseText(data)
+		case "json5":
+			v, err = jolt.UnmarshalJSON5(data)
+		default:
+			if err = jolt.UnmarshalJSONWithComments(data, &v); err != nil {
+				// Not JSON: maybe JSON5, whose errors also say where.
+				v, err = jolt.UnmarshalJSON5(data)
+			}
+		}
+		if err != nil {
 			panic(err)
 		}
 		b, err := jolt.EncodeBinary(v)
@@ -39,16 +89,82 @@ func main() {
 		}
 		os.Stdout.Write(b)
 	case "decode":
+		if format == "yaml" {
+			out, err := decodeYAML(os.Stdin, stream)
+			if err != nil {
+				panic(err)
+			}
+			os.Stdout.Write(out)
+			return
+		}
+		if stream || format == "ndjson" {
+			opts := jolt.TranscodeOptions{NDJSON: format == "ndjson"}
+			if err := jolt.TranscodeBinaryToJSONWith(os.Stdout, os.Stdin, opts); err != nil {
+				panic(err)
+			}
+			return
+		}
 		b, err := ioReadAll(os.Stdin)
-		if err != nil {
+		if err != nil && err != io.EOF {
 			panic(err)
 		}
+		if format == "text" {
+			text, err := jolt.FormatTextBinary(b)
+			if err != nil {
+				panic(err)
+			}
+			os.Stdout.Write(text)
+			return
+		}
 		v, err := jolt.DecodeBinary(b)
 		if err != nil {
 			panic(err)
 		}
 		js, _ := jolt.MarshalJSONCompat(v, true)
 		os.Stdout.Write(js)
+	case "infer":
+		// Samples come from the files and directories named as arguments
+		// (.jb is JOLT-B, anything else JSON/JSONC or NDJSON), or stdin.
+		var samples []any
+		if flag.NArg() == 0 {
+			data, err := ioReadAll(os.Stdin)
+			if err != nil && err != io.EOF {
+				panic(err)
+			}
+			if samples, err = readSamples("stdin", data); err != nil {
+				panic(err)
+			}
+		}
+		for _, root := range flag.Args() {
+			err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+				if err != nil || d.IsDir() {
+					return err
+				}
+				data, err := os.ReadFile(path)
+				if err != nil {
+					return err
+				}
+				vs, err := readSamples(path, data)
+				samples = append(samples, vs...)
+				return err
+			})
+			if err != nil {
+				panic(err)
+			}
+		}
+		s := joltschema.Infer(samples...)
+		s.ID = id
+		if s.Doc == "" {
+			s.Doc = fmt.Sprintf("Inferred from %d samples.", len(samples))
+		}
+		js, err := s.MarshalJSON()
+		if err != nil {
+			panic(err)
+		}
+		var out bytes.Buffer
+		_ = json.Indent(&out, js, "", "  ")
+		out.WriteByte('\n')
+		os.Stdout.Write(out.Bytes())
 	default:
 		fmt.Println("unknown mode")
 	}
@@ -75,4 +191,67 @@ func ioReadAll(f *os.File) ([]byte, error) {
 	}
 }
 
+// decodeYAML writes one JOLT-B document as YAML or, with stream, a run of
+// frames as a multi-document stream.
+func decodeYAML(f *os.File, stream bool) ([]byte, error) {
+	if !stream {
+		b, err := ioReadAll(f)
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		v, err := jolt.DecodeBinary(b)
+		if err != nil {
+			return nil, err
+		}
+		return yaml.Marshal(v)
+	}
+	var docs []any
+	r := bufio.NewReader(f)
+	for {
+		b, err := jolt.ReadFrame(r)
+		if err == io.EOF {
+			return yaml.MarshalAll(docs)
+		}
+		if err != nil {
+			return nil, err
+		}
+		v, err := jolt.DecodeBinary(b)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, v)
+	}
+}
+
+// readSamples decodes one JOLT-B document, one JSON/JSONC document, or a
+// stream of JSON documents such as NDJSON.
+func readSamples(name string, data []byte) ([]any, error) {
+	if strings.HasSuffix(name, ".jb") {
+		v, err := jolt.DecodeBinary(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		return []any{v}, nil
+	}
+	if v, err := jolt.UnmarshalJSONTyped(data); err == nil {
+		return []any{v}, nil
+	}
+	var out []any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	for {
+		var raw any
+		if err := dec.Decode(&raw); err == io.EOF {
+			return out, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		v, err := jolt.LiftTyped(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		out = append(out, v)
+	}
+}
+
 func mustDec(s string) jolt.Decimal { d, _ := jolt.DecFromString(s); return d }
@@ -0,0 +1,52 @@
+// Command joltgen generates Go types from a JOLT schema:
+//
+//	go run ./cmd/joltgen -schema order.jsonc -pkg orders -o orders/order_gen.go
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/chandan-cmd-dev/jolt-go/joltschema"
+)
+
+func main() {
+	var schemaPath, pkg, typeName, out string
+	flag.StringVar(&schemaPath, "schema", "", "schema file (.jsonc, .json or .jb)")
+	flag.StringVar(&pkg, "pkg", "", "package name of the generated file")
+	flag.StringVar(&typeName, "type", "", "name of the root type (default: last segment of the schema id)")
+	flag.StringVar(&out, "o", "", "output file (default stdout)")
+	flag.Parse()
+	if schemaPath == "" || pkg == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	data, err := os.ReadFile(schemaPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var s *joltschema.Schema
+	if strings.HasSuffix(schemaPath, ".jb") {
+		s, err = joltschema.ParseBinary(data)
+	} else {
+		s, err = joltschema.Parse(data)
+	}
+	if err != nil {
+		log.Fatalf("%s: %v", schemaPath, err)
+	}
+	src, err := joltschema.GenerateGo(s, joltschema.GoOptions{Package: pkg, Root: typeName, Source: filepath.Base(schemaPath)})
+	if err != nil {
+		log.Fatal(err)
+	}
+	if out == "" {
+		os.Stdout.Write(src)
+		return
+	}
+	if err := os.WriteFile(out, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
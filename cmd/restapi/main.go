@@ -2,17 +2,22 @@ package main
 
 import (
 	"bufio"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/chandan-cmd-dev/jolt-go/jolt"
+	"github.com/chandan-cmd-dev/jolt-go/jolt/projection"
+	"github.com/chandan-cmd-dev/jolt-go/jolt/redact"
+	"github.com/chandan-cmd-dev/jolt-go/joltschema"
 	"github.com/chandan-cmd-dev/jolt-go/joltsec"
 )
 
@@ -42,20 +47,82 @@ func (s *store) get(id string) (meta jolt.Meta, jb []byte, ok bool) {
 	return s.met[id], jb, true
 }
 
+// update replaces a stored order with fn's result while holding the write
+// lock, so concurrent read-modify-write requests cannot lose each other's
+// changes. It reports false when id is unknown; an error from fn leaves the
+// order as it was.
+func (s *store) update(id string, fn func(jolt.Meta, []byte) (jolt.Meta, []byte, error)) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	jb, ok := s.m[id]
+	if !ok {
+		return false, nil
+	}
+	meta, jb, err := fn(s.met[id], jb)
+	if err != nil {
+		return true, err
+	}
+	s.m[id] = jb
+	s.met[id] = meta
+	return true, nil
+}
+
 var (
 	// Keyring for JOLT-SEC (optional). If nil, /joltsec is disabled.
 	kr  joltsec.Keyring
 	alg joltsec.Alg
 	kid string
+
+	// Redaction applied to every order served (optional, see -redact).
+	egress *redact.Policy
+
+	// Per-role views of orders (optional, see -views).
+	views *projection.Registry
 )
 
 func main() {
 	var keyfile string
 	var algFlag string
+	var migrations string
+	var policyFile, policyKey, policySchema string
+	var viewsFile string
+	var addr string
 	flag.StringVar(&keyfile, "keyfile", "", "path to 32-byte symmetric key to enable JOLT-SEC")
 	flag.StringVar(&algFlag, "alg", "xchacha", "xchacha | aesgcm (for JOLT-SEC)")
+	flag.StringVar(&migrations, "migrations", "", "directory of *.jsonc migration declarations")
+	flag.StringVar(&policyFile, "redact", "", "redaction policy (*.jsonc) applied to orders served")
+	flag.StringVar(&policyKey, "redact-key", "", "file holding the HMAC key for hash rules")
+	flag.StringVar(&policySchema, "redact-schema", "", "order schema for annotation rules")
+	flag.StringVar(&viewsFile, "views", "", "role views (*.jsonc) selected by the X-Role header")
+	flag.StringVar(&addr, "addr", ":8080", "listen address")
 	flag.Parse()
 
+	if migrations != "" {
+		n, err := loadMigrations(migrations)
+		if err != nil {
+			log.Fatalf("load migrations: %v", err)
+		}
+		log.Printf("loaded %d migrations from %s", n, migrations)
+	}
+
+	if policyFile != "" {
+		p, err := loadPolicy(policyFile, policyKey, policySchema)
+		if err != nil {
+			log.Fatalf("load redaction policy: %v", err)
+		}
+		egress = p
+		log.Printf("redacting responses with policy %s (%d rules)", p.Version, len(p.Rules))
+	}
+
+	if viewsFile != "" {
+		reg, n, err := loadViews(viewsFile, policyKey, policySchema)
+		if err != nil {
+			log.Fatalf("load views: %v", err)
+		}
+		views = reg
+		log.Printf("loaded %d role views from %s", n, viewsFile)
+	}
+
 	switch strings.ToLower(algFlag) {
 	case "xchacha", "xchacha20", "xchacha20poly1305":
 		alg = joltsec.AlgXChaCha20Poly1305
@@ -93,18 +160,21 @@ func main() {
 		handleCreate(s, w, r)
 	})
 	// GET /orders/{id} — return the order in a negotiated format
+	// PATCH /orders/{id} — apply a typed merge patch, then return as GET does
 	mux.HandleFunc("/orders/", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodGet {
+		id := strings.TrimPrefix(r.URL.Path, "/orders/")
+		switch r.Method {
+		case http.MethodGet:
+			handleGet(s, w, r, id)
+		case http.MethodPatch:
+			handlePatch(s, w, r, id)
+		default:
 			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
-			return
 		}
-		id := strings.TrimPrefix(r.URL.Path, "/orders/")
-		handleGet(s, w, r, id)
 	})
 
-	addr := ":8080"
 	log.Printf("REST API on %s", addr)
-	log.Fatal(http.ListenAndServe(addr, mux))
+	log.Fatal(http.ListenAndServe(addr, withRole(mux)))
 }
 
 func handleCreate(s *store, w http.ResponseWriter, r *http.Request) {
@@ -128,7 +198,10 @@ func handleCreate(s *store, w http.ResponseWriter, r *http.Request) {
 		// Try to extract envelope meta if present
 		if m, ok := v.(map[string]any); ok {
 			if mm, ok := m["$meta"].(map[string]any); ok {
-				meta = metaFromMap(mm)
+				if meta, err = jolt.MetaFromMap(mm); err != nil {
+					http.Error(w, "bad $meta: "+err.Error(), 400)
+					return
+				}
 			}
 		}
 
@@ -143,7 +216,10 @@ func handleCreate(s *store, w http.ResponseWriter, r *http.Request) {
 			meta = env.Meta
 		} else if m, ok := v.(map[string]any); ok {
 			if mm, ok := m["$meta"].(map[string]any); ok {
-				meta = metaFromMap(mm)
+				if meta, err = jolt.MetaFromMap(mm); err != nil {
+					http.Error(w, "bad $meta: "+err.Error(), 400)
+					return
+				}
 			}
 		}
 
@@ -167,7 +243,10 @@ func handleCreate(s *store, w http.ResponseWriter, r *http.Request) {
 			meta = env.Meta
 		} else if m, ok := v.(map[string]any); ok {
 			if mm, ok := m["$meta"].(map[string]any); ok {
-				meta = metaFromMap(mm)
+				if meta, err = jolt.MetaFromMap(mm); err != nil {
+					http.Error(w, "bad $meta: "+err.Error(), 400)
+					return
+				}
 			}
 		}
 
@@ -242,9 +321,35 @@ func handleGet(s *store, w http.ResponseWriter, r *http.Request, id string) {
 		http.NotFound(w, r)
 		return
 	}
-	_ = meta // could be used to gate versions
-
 	accept := strings.ToLower(r.Header.Get("Accept"))
+	if want := requestedVersion(r); want != "" && want != meta.Version {
+		migrated, err := migrateStored(jb, want)
+		if err != nil {
+			http.Error(w, "version "+want+": "+err.Error(), http.StatusNotAcceptable)
+			return
+		}
+		jb = migrated
+	}
+	if views != nil {
+		projected, err := views.ProjectRequest(r, jb)
+		if errors.Is(err, projection.ErrNoView) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		if err != nil {
+			http.Error(w, "project: "+err.Error(), 500)
+			return
+		}
+		jb = projected
+	}
+	if egress != nil {
+		redacted, err := egress.ApplyBinary(jb)
+		if err != nil {
+			http.Error(w, "redact: "+err.Error(), 500)
+			return
+		}
+		jb = redacted
+	}
 	mt := negotiate(accept, "application/jolt-sec", "application/jolt", "application/jolt-binary", "application/json")
 
 	switch mt {
@@ -281,6 +386,91 @@ func handleGet(s *store, w http.ResponseWriter, r *http.Request, id string) {
 	}
 }
 
+// handlePatch applies a merge patch (see jolt.MergePatch) to a stored order.
+// The patch is JOLT-B (application/jolt-merge-patch) or typed JSON
+// (application/merge-patch+json), where null deletes and @type wrappers keep
+// decimals and sets exact.
+func handlePatch(s *store, w http.ResponseWriter, r *http.Request, id string) {
+	if _, _, ok := s.get(id); !ok {
+		http.NotFound(w, r)
+		return
+	}
+	// A role may only write what its view shows; check before anything
+	// is stored.
+	var view *projection.View
+	if views != nil {
+		v, err := views.For(projection.RoleFrom(r.Context()))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		view = v
+	}
+	ct := strings.ToLower(strings.TrimSpace(r.Header.Get("Content-Type")))
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "read body: "+err.Error(), 400)
+		return
+	}
+	defer r.Body.Close()
+
+	var patch any
+	switch {
+	case strings.HasPrefix(ct, "application/jolt-merge-patch"):
+		patch, err = jolt.DecodeBinary(body)
+	case strings.HasPrefix(ct, "application/merge-patch+json"):
+		patch, err = jolt.UnmarshalJSONTyped(body)
+	default:
+		http.Error(w, "unsupported Content-Type", 415)
+		return
+	}
+	if err != nil {
+		http.Error(w, "patch decode: "+err.Error(), 400)
+		return
+	}
+	if view != nil {
+		if err := view.CheckMergePatch(patch); err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+	}
+
+	// The merge runs under the store lock: a concurrent PATCH sees this one's
+	// result rather than the order both started from.
+	code := 500
+	found, err := s.update(id, func(meta jolt.Meta, jb []byte) (jolt.Meta, []byte, error) {
+		cur, err := jolt.DecodeBinary(jb)
+		if err == nil {
+			// orders created from plain JSON still hold @type wrappers
+			cur, err = jolt.LiftTyped(cur)
+		}
+		if err != nil {
+			return meta, nil, fmt.Errorf("stored order: %w", err)
+		}
+		next, err := jolt.MergePatch(cur, patch)
+		if err != nil {
+			code = http.StatusUnprocessableEntity
+			return meta, nil, err
+		}
+		if env, err := jolt.AsEnvelope(next); err == nil {
+			meta = env.Meta
+		}
+		if jb, err = jolt.EncodeBinary(next); err != nil {
+			return meta, nil, fmt.Errorf("encode to jolt: %w", err)
+		}
+		return meta, jb, nil
+	})
+	if !found {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), code)
+		return
+	}
+	handleGet(s, w, r, id)
+}
+
 func negotiate(accept string, supported ...string) string {
 	if accept == "" {
 		return supported[len(supported)-1] // default to last (json)
@@ -293,38 +483,153 @@ func negotiate(accept string, supported ...string) string {
 	return supported[len(supported)-1]
 }
 
-func extractID(v any) string {
-	// Try $body.$id or top-level $id
-	if env, ok := v.(jolt.Envelope); ok {
-		if m, ok := env.Body.(map[string]any); ok {
-			if id, ok := m["$id"].(string); ok {
-				return id
-			}
+// requestedVersion reads the body version a client wants, from ?version= or
+// a version parameter on Accept (application/json; version=2.1.0).
+func requestedVersion(r *http.Request) string {
+	if v := r.URL.Query().Get("version"); v != "" {
+		return v
+	}
+	for _, part := range strings.Split(r.Header.Get("Accept"), ";") {
+		k, v, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if ok && strings.EqualFold(k, "version") {
+			return strings.Trim(v, `"`)
 		}
 	}
+	return ""
+}
+
+// migrateStored upcasts a stored order to version and re-encodes it in the
+// shape it was stored in (envelope or {"$meta","$body"} object).
+func migrateStored(jb []byte, version string) ([]byte, error) {
+	v, err := jolt.DecodeBinary(jb)
+	if err != nil {
+		return nil, err
+	}
+	env, err := jolt.AsEnvelope(v)
+	if err != nil {
+		return nil, err
+	}
+	env, err = jolt.Migrate(env, version)
+	if err != nil {
+		return nil, err
+	}
 	if m, ok := v.(map[string]any); ok {
-		if b, ok := m["$body"].(map[string]any); ok {
-			if id, ok := b["$id"].(string); ok {
-				return id
+		out := make(map[string]any, len(m))
+		for k, x := range m {
+			out[k] = x
+		}
+		out["$meta"] = env.Meta.ToMap()
+		out["$body"] = env.Body
+		return jolt.EncodeBinary(out)
+	}
+	return jolt.EncodeBinary(env)
+}
+
+func loadMigrations(dir string) (int, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.jsonc"))
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return n, err
+		}
+		ms, err := jolt.ParseMigrations(data)
+		if err != nil {
+			return n, fmt.Errorf("%s: %w", f, err)
+		}
+		for _, m := range ms {
+			if err := jolt.RegisterMigration(m); err != nil {
+				return n, fmt.Errorf("%s: %w", f, err)
 			}
+			n++
 		}
-		if id, ok := m["$id"].(string); ok {
-			return id
+	}
+	return n, nil
+}
+
+// loadPolicy reads a redaction policy with its optional HMAC key and schema.
+func loadPolicy(file, keyFile, schemaFile string) (*redact.Policy, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	p, err := redact.ParsePolicy(data)
+	if err != nil {
+		return nil, err
+	}
+	if keyFile != "" {
+		if p.Key, err = os.ReadFile(keyFile); err != nil {
+			return nil, err
 		}
 	}
-	return ""
+	if schemaFile != "" {
+		if data, err = os.ReadFile(schemaFile); err != nil {
+			return nil, err
+		}
+		if p.Schema, err = joltschema.Parse(data); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
 }
 
-func metaFromMap(mm map[string]any) jolt.Meta {
-	var meta jolt.Meta
-	if v, ok := mm["type"].(string); ok {
-		meta.Type = v
+// loadViews reads role views; hash and annotation rules use the redaction
+// key and schema files.
+func loadViews(file, keyFile, schemaFile string) (*projection.Registry, int, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, 0, err
 	}
-	if v, ok := mm["version"].(string); ok {
-		meta.Version = v
+	vs, err := projection.ParseViews(data)
+	if err != nil {
+		return nil, 0, err
+	}
+	reg, err := projection.NewRegistry(vs...)
+	if err != nil {
+		return nil, 0, err
 	}
-	// createdAt optional
-	return meta
+	if keyFile != "" {
+		key, err := os.ReadFile(keyFile)
+		if err != nil {
+			return nil, 0, err
+		}
+		reg.SetKey(key)
+	}
+	if schemaFile != "" {
+		if data, err = os.ReadFile(schemaFile); err != nil {
+			return nil, 0, err
+		}
+		sch, err := joltschema.Parse(data)
+		if err != nil {
+			return nil, 0, err
+		}
+		reg.SetSchema(sch)
+	}
+	return reg, len(vs), nil
+}
+
+// withRole records the caller's role for view selection. The demo trusts an
+// X-Role header, as set by an authenticating gateway in front of it.
+func withRole(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if role := r.Header.Get("X-Role"); role != "" {
+			r = r.WithContext(projection.WithRole(r.Context(), role))
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+func extractID(v any) string {
+	// Try $body.$id (envelope or {"$meta","$body"} object), then top-level $id
+	for _, path := range [][]any{{"$body", "$id"}, {"$id"}} {
+		if id, err := jolt.Get[string](v, path...); err == nil {
+			return id
+		}
+	}
+	return ""
 }
 
 func mustDecodeJOLT(jb []byte) any {
@@ -0,0 +1,348 @@
+package jolt
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// PathError reports where in a document Get, SetPath or Delete failed. Path
+// is written as a JOLTPath query, $.$body.lines[0].qty; Err is
+// ErrPathNotFound when nothing is there.
+type PathError struct {
+	Path string
+	Err  error
+}
+
+func (e *PathError) Error() string {
+	if e.Err == ErrPathNotFound {
+		return "jolt: " + e.Path + ": not found"
+	}
+	return "jolt: " + e.Path + ": " + e.Err.Error()
+}
+
+func (e *PathError) Unwrap() error { return e.Err }
+
+// Get follows path through v and converts what it finds to T. A path
+// element is a field name (or "$meta"/"$body" of an envelope, or a string
+// Map key), an int index into an array (negative counts from the end), or
+// any other Map key; the MapKey and SetMember elements of Walk paths work too.
+// Besides exact type matches, T may be any Go integer type
+// (from an Int or integral Decimal, checked for overflow), float64, Decimal
+// (from Decimal or Int), UUID (also from its string form), *big.Int, []any
+// (from an array or Set), map[string]any (from an object or string-keyed
+// Map), Map or Envelope.
+func Get[T any](v any, path ...any) (T, error) {
+	var zero T
+	cur, err := walk(v, path)
+	if err != nil {
+		return zero, err
+	}
+	out, err := coerce[T](cur)
+	if err != nil {
+		return zero, &PathError{Path: formatPath(path), Err: err}
+	}
+	return out, nil
+}
+
+// MustGet is like Get but panics on error.
+func MustGet[T any](v any, path ...any) T {
+	out, err := Get[T](v, path...)
+	if err != nil {
+		panic(err)
+	}
+	return out
+}
+
+// SetPath stores value at path in v and returns the updated root. Missing
+// objects along the way are created; an index one past the end of an array
+// appends. Objects and Maps are changed in place, but arrays that grow and
+// envelopes are new values, so always use the returned root.
+func SetPath(v any, path []any, value any) (any, error) {
+	return setAt(v, path, 0, value)
+}
+
+// Delete removes the field, Map entry or array element path names and
+// returns the updated root, as SetPath does.
+func Delete(v any, path ...any) (any, error) {
+	if len(path) == 0 {
+		return nil, nil
+	}
+	parent, err := walk(v, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+	last := path[len(path)-1]
+	notFound := &PathError{Path: formatPath(path), Err: ErrPathNotFound}
+	var updated any
+	switch x := parent.(type) {
+	case map[string]any:
+		k, ok := last.(string)
+		if _, found := x[k]; !ok || !found {
+			return nil, notFound
+		}
+		delete(x, k)
+		updated = x
+	case Map:
+		k, ok := mapFind(x, last)
+		if !ok {
+			return nil, notFound
+		}
+		delete(x, k)
+		updated = x
+	case []any:
+		i, ok := indexOf(last, len(x))
+		if !ok {
+			return nil, notFound
+		}
+		updated = append(x[:i:i], x[i+1:]...)
+	default:
+		return nil, &PathError{Path: formatPath(path), Err: fmt.Errorf("cannot delete from %T", parent)}
+	}
+	// Store the parent back: it may be a new slice or a copy of $meta.
+	return setAt(v, path[:len(path)-1], 0, updated)
+}
+
+// walk follows path from v.
+func walk(v any, path []any) (any, error) {
+	for i, seg := range path {
+		c, ok := step(v, seg)
+		if !ok {
+			return nil, &PathError{Path: formatPath(path[:i+1]), Err: ErrPathNotFound}
+		}
+		v = c
+	}
+	return v, nil
+}
+
+func step(v, seg any) (any, bool) {
+	switch x := v.(type) {
+	case map[string]any:
+		if k, ok := seg.(string); ok {
+			c, ok := x[k]
+			return c, ok
+		}
+	case Envelope:
+		switch seg {
+		case "$meta":
+			return x.Meta.ToMap(), true
+		case "$body":
+			return x.Body, true
+		}
+	case []any:
+		if i, ok := indexOf(seg, len(x)); ok {
+			return x[i], true
+		}
+	case Map:
+		if mk, ok := seg.(MapKey); ok {
+			k, found := mapFind(x, mk.Key)
+			return k, found
+		}
+		if k, ok := mapFind(x, seg); ok {
+			return x[k], true
+		}
+	case Set:
+		if m, ok := seg.(SetMember); ok {
+			for _, it := range x {
+				if Equal(it, m.Value) {
+					return it, true
+				}
+			}
+		}
+	}
+	return nil, false
+}
+
+// indexOf resolves an int path element against an array of length n.
+func indexOf(seg any, n int) (int, bool) {
+	i, ok := seg.(int)
+	if !ok {
+		return 0, false
+	}
+	if i < 0 {
+		i += n
+	}
+	return i, i >= 0 && i < n
+}
+
+func setAt(node any, path []any, depth int, value any) (any, error) {
+	if depth == len(path) {
+		return value, nil
+	}
+	seg := path[depth]
+	fail := func(err error) (any, error) {
+		return nil, &PathError{Path: formatPath(path[:depth+1]), Err: err}
+	}
+	if node == nil {
+		if _, ok := seg.(string); !ok {
+			return fail(ErrPathNotFound)
+		}
+		node = map[string]any{}
+	}
+	switch x := node.(type) {
+	case map[string]any:
+		k, ok := seg.(string)
+		if !ok {
+			return fail(fmt.Errorf("object field must be a string, got %T", seg))
+		}
+		c, err := setAt(x[k], path, depth+1, value)
+		if err != nil {
+			return nil, err
+		}
+		x[k] = c
+		return x, nil
+	case Envelope:
+		switch seg {
+		case "$body":
+			c, err := setAt(x.Body, path, depth+1, value)
+			if err != nil {
+				return nil, err
+			}
+			x.Body = c
+			return x, nil
+		case "$meta":
+			c, err := setAt(x.Meta.ToMap(), path, depth+1, value)
+			if err != nil {
+				return nil, err
+			}
+			m, ok := c.(map[string]any)
+			if !ok {
+				return fail(fmt.Errorf("$meta must be an object, got %T", c))
+			}
+			if x.Meta, err = MetaFromMap(m); err != nil {
+				return fail(err)
+			}
+			return x, nil
+		}
+		return fail(fmt.Errorf("envelopes have only $meta and $body"))
+	case []any:
+		if i, ok := seg.(int); ok && i == len(x) && depth == len(path)-1 {
+			return append(x, value), nil
+		}
+		i, ok := indexOf(seg, len(x))
+		if !ok {
+			return fail(ErrPathNotFound)
+		}
+		c, err := setAt(x[i], path, depth+1, value)
+		if err != nil {
+			return nil, err
+		}
+		x[i] = c
+		return x, nil
+	case Map:
+		k, ok := mapFind(x, seg)
+		if !ok {
+			k = seg
+		}
+		c, err := setAt(x[k], path, depth+1, value)
+		if err != nil {
+			return nil, err
+		}
+		x[k] = c
+		return x, nil
+	}
+	return fail(fmt.Errorf("%T has no children", node))
+}
+
+// formatPath renders a path as $.$body.lines[0].qty.
+func formatPath(path []any) string {
+	var b strings.Builder
+	b.WriteByte('$')
+	for _, seg := range path {
+		switch s := seg.(type) {
+		case string:
+			if s != "" && strings.IndexFunc(s, func(r rune) bool { return !isNameByte(byte(r)) || r >= 0x80 }) < 0 {
+				b.WriteString("." + s)
+			} else {
+				b.WriteString("[" + strconv.Quote(s) + "]")
+			}
+		case int:
+			b.WriteString("[" + strconv.Itoa(s) + "]")
+		case MapKey:
+			fmt.Fprintf(&b, "[key %v]", s.Key)
+		case SetMember:
+			fmt.Fprintf(&b, "[member %v]", s.Value)
+		default:
+			fmt.Fprintf(&b, "[%v]", seg)
+		}
+	}
+	return b.String()
+}
+
+var errNull = errors.New("value is null")
+
+func coerce[T any](v any) (T, error) {
+	var zero T
+	if t, ok := v.(T); ok {
+		return t, nil
+	}
+	rt := reflect.TypeFor[T]()
+	if v == nil {
+		if rt.Kind() == reflect.Interface {
+			return zero, nil
+		}
+		return zero, errNull
+	}
+	var out any
+	var err error
+	switch any(zero).(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		out, err = integerAs(rt, v)
+	case float64:
+		switch x := v.(type) {
+		case Int:
+			out, _ = new(big.Float).SetInt(x.V).Float64()
+		case Decimal:
+			out, err = x.D.Float64()
+		default:
+			err = wantGot("number", v)
+		}
+	case Decimal:
+		out, err = AsDecimal(v)
+	case UUID:
+		out, err = AsUUID(v)
+	case *big.Int:
+		if out, err = integerOf(v); err != nil {
+			err = wantGot("int", v)
+		}
+	case []any:
+		out, err = AsList(v)
+	case map[string]any:
+		out, err = AsStringMap(v)
+	case Map:
+		out, err = AsMap(v)
+	case Envelope:
+		out, err = AsEnvelope(v)
+	default:
+		err = fmt.Errorf("want %v, got %T", rt, v)
+	}
+	if err != nil {
+		return zero, err
+	}
+	return out.(T), nil
+}
+
+// integerAs converts an integral value to the Go integer type rt.
+func integerAs(rt reflect.Type, v any) (any, error) {
+	n, err := integerOf(v)
+	if err != nil {
+		return nil, wantGot("int", v)
+	}
+	rv := reflect.New(rt).Elem()
+	switch rt.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if !n.IsInt64() || rv.OverflowInt(n.Int64()) {
+			return nil, fmt.Errorf("%s overflows %v", n, rt)
+		}
+		rv.SetInt(n.Int64())
+	default:
+		if n.Sign() < 0 || !n.IsUint64() || rv.OverflowUint(n.Uint64()) {
+			return nil, fmt.Errorf("%s overflows %v", n, rt)
+		}
+		rv.SetUint(n.Uint64())
+	}
+	return rv.Interface(), nil
+}
@@ -0,0 +1,746 @@
+// Package cbor converts JOLT values to and from CBOR (RFC 8949), using the
+// standard tags where CBOR has them:
+//
+//	Int        major type 0/1, or bignum tag 2/3 beyond 64 bits
+//	Decimal    decimal fraction, tag 4 [exponent, mantissa]; Infinity and NaN as floats
+//	Timestamp  tag 0 (RFC 3339 text); tag 1 (epoch seconds) is read too
+//	Date       tag 1004 (RFC 3339 full-date); tag 100 (epoch days) is read too
+//	UUID       tag 37
+//	Set        tag 258 around an array
+//	Link       tag 32 (URI)
+//	Binary     byte string
+//	Map        CBOR map with arbitrary keys
+//
+// Objects are maps with text keys, and an Envelope is the object
+// {"$meta": ..., "$body": ...} as in JSON. Time, Annot and extension values
+// have no CBOR tag and travel in their "@type" wrapper form, which FromCBOR
+// lifts back like UnmarshalJSONTyped does. CBOR floats become Decimals;
+// infinities and NaN, which JOLT-B cannot hold, are unmappable.
+//
+// ToCBOR writes deterministic encoding (RFC 8949 §4.2.1): shortest heads and
+// floats, definite lengths, and map keys sorted by their encoded bytes; Set
+// members are sorted the same way. A Map whose keys are all strings reads
+// back as an object. FromCBOR rejects maps with duplicate keys.
+package cbor
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/cockroachdb/apd/v3"
+
+	"github.com/chandan-cmd-dev/jolt-go/jolt"
+)
+
+// ErrUnmappable is wrapped by the errors FromCBOR returns for well-formed
+// items that have no JOLT counterpart (unknown tags, undefined, other simple
+// values, non-comparable map keys) and by ToCBOR for values it cannot write.
+var ErrUnmappable = errors.New("jolt/cbor: no mapping")
+
+var errTruncated = errors.New("jolt/cbor: truncated input")
+
+// CBOR major types.
+const (
+	majorUint byte = iota
+	majorNint
+	majorBytes
+	majorText
+	majorArray
+	majorMap
+	majorTag
+	majorSimple
+)
+
+// Tags this package reads or writes.
+const (
+	tagDateTime     = 0
+	tagEpoch        = 1
+	tagPosBignum    = 2
+	tagNegBignum    = 3
+	tagDecimal      = 4
+	tagURI          = 32
+	tagUUID         = 37
+	tagEpochDays    = 100
+	tagSet          = 258
+	tagFullDate     = 1004
+	tagSelfDescribe = 55799
+)
+
+// ToCBOR encodes v deterministically.
+func ToCBOR(v any) ([]byte, error) {
+	var b bytes.Buffer
+	if err := encode(&b, v, 0); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// FromCBOR decodes a single CBOR data item.
+func FromCBOR(data []byte) (any, error) {
+	d := &decoder{b: data}
+	v, err := d.value(0)
+	if err != nil {
+		return nil, err
+	}
+	if d.i != len(d.b) {
+		return nil, fmt.Errorf("jolt/cbor: trailing data after item")
+	}
+	return v, nil
+}
+
+// ----- encoding -----
+
+func head(b *bytes.Buffer, major byte, arg uint64) {
+	m := major << 5
+	switch {
+	case arg < 24:
+		b.WriteByte(m | byte(arg))
+	case arg <= math.MaxUint8:
+		b.Write([]byte{m | 24, byte(arg)})
+	case arg <= math.MaxUint16:
+		b.WriteByte(m | 25)
+		b.Write(binary.BigEndian.AppendUint16(nil, uint16(arg)))
+	case arg <= math.MaxUint32:
+		b.WriteByte(m | 26)
+		b.Write(binary.BigEndian.AppendUint32(nil, uint32(arg)))
+	default:
+		b.WriteByte(m | 27)
+		b.Write(binary.BigEndian.AppendUint64(nil, arg))
+	}
+}
+
+func encodeText(b *bytes.Buffer, s string) {
+	head(b, majorText, uint64(len(s)))
+	b.WriteString(s)
+}
+
+func encodeBytes(b *bytes.Buffer, p []byte) {
+	head(b, majorBytes, uint64(len(p)))
+	b.Write(p)
+}
+
+// encodeInt writes z as a basic integer when it fits, else as a bignum.
+func encodeInt(b *bytes.Buffer, z *big.Int) {
+	if z.Sign() >= 0 {
+		if z.IsUint64() {
+			head(b, majorUint, z.Uint64())
+			return
+		}
+		head(b, majorTag, tagPosBignum)
+		encodeBytes(b, z.Bytes())
+		return
+	}
+	n := new(big.Int).Neg(z)
+	n.Sub(n, big.NewInt(1)) // -1 - z
+	if n.IsUint64() {
+		head(b, majorNint, n.Uint64())
+		return
+	}
+	head(b, majorTag, tagNegBignum)
+	encodeBytes(b, n.Bytes())
+}
+
+// encodeFloat writes f in the shortest of half, single and double precision
+// that keeps its value.
+func encodeFloat(b *bytes.Buffer, f float64) {
+	if math.IsNaN(f) {
+		b.Write([]byte{0xf9, 0x7e, 0x00})
+		return
+	}
+	if h, ok := toHalf(f); ok {
+		b.WriteByte(0xf9)
+		b.Write(binary.BigEndian.AppendUint16(nil, h))
+		return
+	}
+	if float64(float32(f)) == f {
+		b.WriteByte(0xfa)
+		b.Write(binary.BigEndian.AppendUint32(nil, math.Float32bits(float32(f))))
+		return
+	}
+	b.WriteByte(0xfb)
+	b.Write(binary.BigEndian.AppendUint64(nil, math.Float64bits(f)))
+}
+
+func toHalf(f float64) (uint16, bool) {
+	f32 := float32(f)
+	if float64(f32) != f {
+		return 0, false
+	}
+	bits := math.Float32bits(f32)
+	sign := uint16(bits>>16) & 0x8000
+	exp := int(bits>>23) & 0xff
+	mant := bits & 0x7fffff
+	switch {
+	case exp == 0xff:
+		return sign | 0x7c00, true
+	case exp == 0 && mant == 0:
+		return sign, true
+	case exp == 0:
+		return 0, false
+	}
+	e := exp - 127
+	switch {
+	case e >= -14 && e <= 15:
+		if mant&0x1fff != 0 {
+			return 0, false
+		}
+		return sign | uint16(e+15)<<10 | uint16(mant>>13), true
+	case e >= -24 && e < -14:
+		full := mant | 1<<23
+		shift := uint(-1 - e)
+		if full&(1<<shift-1) != 0 {
+			return 0, false
+		}
+		return sign | uint16(full>>shift), true
+	}
+	return 0, false
+}
+
+// sortedEncodings sorts encoded items bytewise, the deterministic order of
+// map keys (and here of Set members).
+func sortedEncodings(items [][]byte) {
+	sort.Slice(items, func(i, j int) bool { return bytes.Compare(items[i], items[j]) < 0 })
+}
+
+func encode(b *bytes.Buffer, v any, depth int) error {
+	if depth > jolt.DefaultLimits.MaxDepth {
+		return jolt.ErrTooDeep
+	}
+	switch x := v.(type) {
+	case nil:
+		b.WriteByte(0xf6)
+	case bool:
+		if x {
+			b.WriteByte(0xf5)
+		} else {
+			b.WriteByte(0xf4)
+		}
+	case string:
+		encodeText(b, x)
+	case jolt.Binary:
+		encodeBytes(b, x)
+	case jolt.Int:
+		if x.V == nil {
+			head(b, majorUint, 0)
+			return nil
+		}
+		encodeInt(b, x.V)
+	case jolt.Decimal:
+		return encodeDecimal(b, x)
+	case jolt.Timestamp:
+		head(b, majorTag, tagDateTime)
+		encodeText(b, x.RFC3339)
+	case jolt.Date:
+		head(b, majorTag, tagFullDate)
+		encodeText(b, x.YYYYMMDD)
+	case jolt.UUID:
+		head(b, majorTag, tagUUID)
+		encodeBytes(b, x[:])
+	case jolt.Link:
+		head(b, majorTag, tagURI)
+		encodeText(b, x.Ref)
+	case jolt.Time:
+		return encode(b, map[string]any{"@type": "time", "value": x.HHMMSS}, depth)
+	case jolt.Annot:
+		return encode(b, map[string]any{"@type": "annot", "note": x.Note}, depth)
+	case jolt.Extension:
+		return encode(b, map[string]any{"@type": "ext", "tag": x.Tag, "value": base64.StdEncoding.EncodeToString(x.Raw)}, depth)
+	case jolt.Envelope:
+		return encode(b, map[string]any{"$meta": x.Meta, "$body": x.Body}, depth)
+	case []any:
+		head(b, majorArray, uint64(len(x)))
+		for _, it := range x {
+			if err := encode(b, it, depth+1); err != nil {
+				return err
+			}
+		}
+	case jolt.Set:
+		items := make([][]byte, len(x))
+		for i, it := range x {
+			var ib bytes.Buffer
+			if err := encode(&ib, it, depth+1); err != nil {
+				return err
+			}
+			items[i] = ib.Bytes()
+		}
+		sortedEncodings(items)
+		head(b, majorTag, tagSet)
+		head(b, majorArray, uint64(len(items)))
+		for _, it := range items {
+			b.Write(it)
+		}
+	case map[string]any:
+		entries := make([][]byte, 0, len(x))
+		for k, it := range x {
+			var eb bytes.Buffer
+			encodeText(&eb, k)
+			if err := encode(&eb, it, depth+1); err != nil {
+				return err
+			}
+			entries = append(entries, eb.Bytes())
+		}
+		writeMap(b, entries)
+	case jolt.Map:
+		entries := make([][]byte, 0, len(x))
+		for k, it := range x {
+			var eb bytes.Buffer
+			if err := encode(&eb, k, depth+1); err != nil {
+				return err
+			}
+			if err := encode(&eb, it, depth+1); err != nil {
+				return err
+			}
+			entries = append(entries, eb.Bytes())
+		}
+		writeMap(b, entries)
+	case json.Number:
+		lv, err := jolt.LiftTyped(x)
+		if err != nil {
+			return err
+		}
+		return encode(b, lv, depth)
+	default:
+		return encodeOther(b, v, depth)
+	}
+	return nil
+}
+
+// writeMap writes encoded key/value pairs in key order. Keys are unique, so
+// sorting whole entries orders them by key.
+func writeMap(b *bytes.Buffer, entries [][]byte) {
+	sortedEncodings(entries)
+	head(b, majorMap, uint64(len(entries)))
+	for _, e := range entries {
+		b.Write(e)
+	}
+}
+
+func encodeDecimal(b *bytes.Buffer, d jolt.Decimal) error {
+	switch d.D.Form {
+	case apd.Infinite:
+		sign := 1
+		if d.D.Negative {
+			sign = -1
+		}
+		encodeFloat(b, math.Inf(sign))
+		return nil
+	case apd.NaN, apd.NaNSignaling:
+		encodeFloat(b, math.NaN())
+		return nil
+	}
+	m := new(big.Int).Set(d.D.Coeff.MathBigInt())
+	if d.D.Negative {
+		m.Neg(m)
+	}
+	head(b, majorTag, tagDecimal)
+	head(b, majorArray, 2)
+	encodeInt(b, big.NewInt(int64(d.D.Exponent)))
+	encodeInt(b, m)
+	return nil
+}
+
+// encodeOther handles Go numbers, and everything else (structs, Meta,
+// registered extension types) through its JSON form.
+func encodeOther(b *bytes.Buffer, v any, depth int) error {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		encodeInt(b, big.NewInt(rv.Int()))
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		head(b, majorUint, rv.Uint())
+		return nil
+	case reflect.Float32, reflect.Float64:
+		encodeFloat(b, rv.Float())
+		return nil
+	}
+	js, err := jolt.MarshalJSONCompat(v, false)
+	if err != nil {
+		return fmt.Errorf("%w for %T: %v", ErrUnmappable, v, err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(js))
+	dec.UseNumber()
+	var raw any
+	if err := dec.Decode(&raw); err != nil {
+		return err
+	}
+	lv, err := jolt.LiftTyped(raw)
+	if err != nil {
+		return err
+	}
+	if reflect.TypeOf(lv) != reflect.TypeOf(v) {
+		return encode(b, lv, depth)
+	}
+	// A registered extension lifts back to itself: write its wrapper.
+	m, ok := raw.(map[string]any)
+	if !ok {
+		return fmt.Errorf("%w for %T", ErrUnmappable, v)
+	}
+	repr, err := jolt.LiftTyped(m["value"])
+	if err != nil {
+		return err
+	}
+	return encode(b, map[string]any{"@type": m["@type"], "value": repr}, depth)
+}
+
+// ----- decoding -----
+
+type decoder struct {
+	b []byte
+	i int
+}
+
+// head reads an item head; indefinite is set for additional information 31.
+func (d *decoder) head() (major byte, arg uint64, indefinite bool, err error) {
+	if d.i >= len(d.b) {
+		return 0, 0, false, errTruncated
+	}
+	ib := d.b[d.i]
+	d.i++
+	major, info := ib>>5, ib&0x1f
+	switch {
+	case info < 24:
+		return major, uint64(info), false, nil
+	case info <= 27:
+		n := 1 << (info - 24)
+		if d.i+n > len(d.b) {
+			return 0, 0, false, errTruncated
+		}
+		for _, c := range d.b[d.i : d.i+n] {
+			arg = arg<<8 | uint64(c)
+		}
+		d.i += n
+		return major, arg, false, nil
+	case info == 31 && (major == majorBytes || major == majorText || major == majorArray || major == majorMap || major == majorSimple):
+		return major, 0, true, nil
+	}
+	return 0, 0, false, fmt.Errorf("jolt/cbor: malformed head 0x%02x at offset %d", ib, d.i-1)
+}
+
+func (d *decoder) isBreak() bool {
+	if d.i < len(d.b) && d.b[d.i] == 0xff {
+		d.i++
+		return true
+	}
+	return false
+}
+
+// str reads the contents of a byte or text string whose head was read.
+func (d *decoder) str(major byte, n uint64, indefinite bool) ([]byte, error) {
+	if !indefinite {
+		if n > uint64(len(d.b)-d.i) {
+			return nil, errTruncated
+		}
+		s := d.b[d.i : d.i+int(n)]
+		d.i += int(n)
+		return s, nil
+	}
+	var out []byte
+	for !d.isBreak() {
+		m, cn, ind, err := d.head()
+		if err != nil {
+			return nil, err
+		}
+		if m != major || ind {
+			return nil, fmt.Errorf("jolt/cbor: bad chunk in indefinite-length string")
+		}
+		chunk, err := d.str(m, cn, false)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, chunk...)
+	}
+	return out, nil
+}
+
+func (d *decoder) value(depth int) (any, error) {
+	if depth > jolt.DefaultLimits.MaxDepth {
+		return nil, jolt.ErrTooDeep
+	}
+	start := d.i
+	major, arg, indefinite, err := d.head()
+	if err != nil {
+		return nil, err
+	}
+	switch major {
+	case majorUint:
+		return jolt.Int{V: new(big.Int).SetUint64(arg)}, nil
+	case majorNint:
+		z := new(big.Int).SetUint64(arg)
+		return jolt.Int{V: z.Neg(z).Sub(z, big.NewInt(1))}, nil
+	case majorBytes:
+		s, err := d.str(major, arg, indefinite)
+		if err != nil {
+			return nil, err
+		}
+		return jolt.Binary(append([]byte(nil), s...)), nil
+	case majorText:
+		s, err := d.str(major, arg, indefinite)
+		if err != nil {
+			return nil, err
+		}
+		if !utf8.Valid(s) {
+			return nil, fmt.Errorf("jolt/cbor: invalid UTF-8 in text string at offset %d", start)
+		}
+		return string(s), nil
+	case majorArray:
+		// Every item takes at least one byte.
+		if !indefinite && arg > uint64(len(d.b)-d.i) {
+			return nil, errTruncated
+		}
+		var out []any
+		for i := uint64(0); indefinite || i < arg; i++ {
+			if indefinite && d.isBreak() {
+				break
+			}
+			it, err := d.value(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, it)
+		}
+		if out == nil {
+			out = []any{}
+		}
+		return out, nil
+	case majorMap:
+		return d.mapItem(arg, indefinite, depth)
+	case majorTag:
+		return d.tagged(arg, start, depth)
+	}
+	switch {
+	case indefinite:
+		return nil, fmt.Errorf("jolt/cbor: unexpected break at offset %d", start)
+	case d.b[start] == 0xf4:
+		return false, nil
+	case d.b[start] == 0xf5:
+		return true, nil
+	case d.b[start] == 0xf6:
+		return nil, nil
+	case d.b[start] == 0xf9:
+		return floatDecimal(halfToFloat(uint16(arg)))
+	case d.b[start] == 0xfa:
+		return floatDecimal(float64(math.Float32frombits(uint32(arg))))
+	case d.b[start] == 0xfb:
+		return floatDecimal(math.Float64frombits(arg))
+	case d.b[start] == 0xf7:
+		return nil, fmt.Errorf("%w: undefined at offset %d", ErrUnmappable, start)
+	}
+	return nil, fmt.Errorf("%w: simple value %d at offset %d", ErrUnmappable, arg, start)
+}
+
+func (d *decoder) mapItem(n uint64, indefinite bool, depth int) (any, error) {
+	if !indefinite && n > uint64(len(d.b)-d.i)/2 {
+		return nil, errTruncated
+	}
+	var keys, vals []any
+	texts := true
+	seen := map[string]bool{}
+	for i := uint64(0); indefinite || i < n; i++ {
+		if indefinite && d.isBreak() {
+			break
+		}
+		start := d.i
+		k, err := d.value(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		// Deterministic CBOR (RFC 8949 §4.2) has no duplicate keys; keys are
+		// told apart by their JOLT-B encoding, so Int 1 and Int 1 collide.
+		if kb, err := jolt.EncodeBinary(k); err == nil {
+			if seen[string(kb)] {
+				return nil, fmt.Errorf("jolt/cbor: duplicate map key at offset %d", start)
+			}
+			seen[string(kb)] = true
+		}
+		v, err := d.value(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := k.(string); !ok {
+			texts = false
+		}
+		keys, vals = append(keys, k), append(vals, v)
+	}
+	if !texts {
+		out := make(jolt.Map, len(keys))
+		for i, k := range keys {
+			if k != nil && !reflect.TypeOf(k).Comparable() {
+				return nil, fmt.Errorf("%w: map key of type %T is not usable as a key", ErrUnmappable, k)
+			}
+			out[k] = vals[i]
+		}
+		return out, nil
+	}
+	obj := make(map[string]any, len(keys))
+	for i, k := range keys {
+		obj[k.(string)] = vals[i]
+	}
+	return lift(obj)
+}
+
+// lift turns an "@type" wrapper into its value. Numbers go back to the
+// json.Number form LiftTyped reads, on a copy, so objects that are not
+// wrappers keep their Decimals as they were.
+func lift(obj map[string]any) (any, error) {
+	if _, ok := obj["@type"].(string); !ok {
+		return obj, nil
+	}
+	w := make(map[string]any, len(obj))
+	for k, v := range obj {
+		switch x := v.(type) {
+		case jolt.Int:
+			v = json.Number(x.V.String())
+		case jolt.Decimal:
+			v = json.Number(x.String())
+		}
+		w[k] = v
+	}
+	lv, err := jolt.LiftTyped(w)
+	if err != nil {
+		return nil, err
+	}
+	if _, still := lv.(map[string]any); still {
+		return obj, nil
+	}
+	return lv, nil
+}
+
+func (d *decoder) tagged(tag uint64, start, depth int) (any, error) {
+	inner, err := d.value(depth + 1)
+	if err != nil {
+		return nil, err
+	}
+	bad := func(want string) error {
+		return fmt.Errorf("jolt/cbor: tag %d at offset %d needs %s, got %T", tag, start, want, inner)
+	}
+	switch tag {
+	case tagSelfDescribe:
+		return inner, nil
+	case tagDateTime:
+		s, ok := inner.(string)
+		if !ok {
+			return nil, bad("a text string")
+		}
+		return jolt.Timestamp{RFC3339: s}, nil
+	case tagEpoch:
+		var t time.Time
+		switch x := inner.(type) {
+		case jolt.Int:
+			if !x.V.IsInt64() {
+				return nil, bad("seconds in range")
+			}
+			t = time.Unix(x.V.Int64(), 0)
+		case jolt.Decimal:
+			f, err := strconv.ParseFloat(x.String(), 64)
+			if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+				return nil, bad("a finite number")
+			}
+			sec, frac := math.Modf(f)
+			t = time.Unix(int64(sec), int64(math.Round(frac*1e9)))
+		default:
+			return nil, bad("a number")
+		}
+		return jolt.Timestamp{RFC3339: t.UTC().Format(time.RFC3339Nano)}, nil
+	case tagFullDate:
+		s, ok := inner.(string)
+		if !ok {
+			return nil, bad("a text string")
+		}
+		return jolt.Date{YYYYMMDD: s}, nil
+	case tagEpochDays:
+		n, ok := inner.(jolt.Int)
+		if !ok || !n.V.IsInt64() {
+			return nil, bad("an integer")
+		}
+		return jolt.Date{YYYYMMDD: time.Unix(n.V.Int64()*86400, 0).UTC().Format("2006-01-02")}, nil
+	case tagPosBignum, tagNegBignum:
+		p, ok := inner.(jolt.Binary)
+		if !ok {
+			return nil, bad("a byte string")
+		}
+		z := new(big.Int).SetBytes(p)
+		if tag == tagNegBignum {
+			z.Neg(z).Sub(z, big.NewInt(1))
+		}
+		return jolt.Int{V: z}, nil
+	case tagDecimal:
+		arr, ok := inner.([]any)
+		if !ok || len(arr) != 2 {
+			return nil, bad("[exponent, mantissa]")
+		}
+		e, ok1 := arr[0].(jolt.Int)
+		m, ok2 := arr[1].(jolt.Int)
+		if !ok1 || !ok2 || !e.V.IsInt64() || e.V.Int64() < math.MinInt32 || e.V.Int64() > math.MaxInt32 {
+			return nil, bad("an integer exponent and mantissa")
+		}
+		var dec jolt.Decimal
+		dec.D.Coeff.SetMathBigInt(new(big.Int).Abs(m.V))
+		dec.D.Negative = m.V.Sign() < 0
+		dec.D.Exponent = int32(e.V.Int64())
+		return dec, nil
+	case tagUUID:
+		p, ok := inner.(jolt.Binary)
+		if !ok || len(p) != 16 {
+			return nil, bad("16 bytes")
+		}
+		var u jolt.UUID
+		copy(u[:], p)
+		return u, nil
+	case tagURI:
+		s, ok := inner.(string)
+		if !ok {
+			return nil, bad("a text string")
+		}
+		return jolt.Link{Ref: s}, nil
+	case tagSet:
+		arr, ok := inner.([]any)
+		if !ok {
+			return nil, bad("an array")
+		}
+		return jolt.Set(arr), nil
+	}
+	return nil, fmt.Errorf("%w: tag %d at offset %d", ErrUnmappable, tag, start)
+}
+
+func halfToFloat(h uint16) float64 {
+	exp := int(h>>10) & 0x1f
+	mant := float64(h & 0x3ff)
+	var f float64
+	switch exp {
+	case 0:
+		f = math.Ldexp(mant, -24)
+	case 0x1f:
+		if mant == 0 {
+			f = math.Inf(1)
+		} else {
+			f = math.NaN()
+		}
+	default:
+		f = math.Ldexp(mant+1024, exp-25)
+	}
+	if h&0x8000 != 0 {
+		f = -f
+	}
+	return f
+}
+
+// floatDecimal keeps a CBOR float as the Decimal with its shortest digits.
+// Infinities and NaN have no JOLT-B form.
+func floatDecimal(f float64) (any, error) {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return nil, fmt.Errorf("%w: float %v", ErrUnmappable, f)
+	}
+	return jolt.DecFromString(strconv.FormatFloat(f, 'g', -1, 64))
+}
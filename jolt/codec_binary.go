@@ -3,7 +3,6 @@ package jolt
 import (
 	"bytes"
 	"encoding/binary"
-	"encoding/json"
 	"fmt"
 	"io"
 	"math"
@@ -11,6 +10,8 @@ import (
 	"reflect"
 	"sort"
 	"strconv"
+
+	"github.com/cockroachdb/apd/v3"
 )
 
 const (
@@ -32,6 +33,9 @@ const (
 	tagLink  byte = 0x0F
 	tagAnnot byte = 0x10
 	tagEnv   byte = 0x11
+	// tagExt is followed by a uvarint extension tag and the length-prefixed
+	// JOLT-B encoding of the value's representation.
+	tagExt byte = 0x12
 )
 
 type Limits struct{ MaxDepth, MaxBytes int }
@@ -88,6 +92,13 @@ func encodeAny(w io.Writer, v any, depth int) error {
 	if depth > DefaultLimits.MaxDepth {
 		return ErrTooDeep
 	}
+	if m, ok := v.(Marshaler); ok && !isNilValue(v) {
+		repr, err := m.MarshalJOLT()
+		if err != nil {
+			return fmt.Errorf("jolt: MarshalJOLT for %T: %w", v, err)
+		}
+		return encodeAny(w, repr, depth+1)
+	}
 	switch x := v.(type) {
 	case nil:
 		_, err := w.Write([]byte{tagNull})
@@ -161,6 +172,10 @@ func encodeAny(w io.Writer, v any, depth int) error {
 		_, err := w.Write(mag)
 		return err
 	case Decimal:
+		if x.D.Form != apd.Finite {
+			// JOLT-B decimals are sign, exponent and coefficient only.
+			return fmt.Errorf("jolt: cannot encode non-finite decimal %s", x.D.String())
+		}
 		if _, err := w.Write([]byte{tagDec}); err != nil {
 			return err
 		}
@@ -314,27 +329,18 @@ func encodeAny(w io.Writer, v any, depth int) error {
 		if _, err := w.Write([]byte{tagEnv}); err != nil {
 			return err
 		}
-		m := map[string]any{"type": x.Meta.Type, "schema": x.Meta.Schema, "version": x.Meta.Version, "features": x.Meta.Features}
-		if x.Meta.Created != nil {
-			m["createdAt"] = *x.Meta.Created
-		}
-		if x.Meta.Sig != nil {
-			m["sig"] = x.Meta.Sig
-		}
+		m := x.Meta.ToMap()
 		if err := encodeAny(w, m, depth+1); err != nil {
 			return err
 		}
 		return encodeAny(w, x.Body, depth+1)
+	case Extension:
+		return writeExtension(w, x.Tag, x.Raw)
 	default:
-		blob, err := json.Marshal(x)
-		if err != nil {
-			return fmt.Errorf("unsupported type %T", x)
+		if e := extensionForType(reflect.TypeOf(x)); e != nil {
+			return encodeExtension(w, e, x, depth)
 		}
-		var g any
-		if err := json.Unmarshal(blob, &g); err != nil {
-			return err
-		}
-		return encodeAny(w, g, depth+1)
+		return encodeReflect(w, x, depth)
 	}
 }
 
@@ -521,6 +527,9 @@ func decodeAny(br io.ByteReader, depth int) (any, error) {
 			if e != nil {
 				return nil, e
 			}
+			if k != nil && !reflect.TypeOf(k).Comparable() {
+				return nil, fmt.Errorf("jolt: map key of type %T is not usable as a key", k)
+			}
 			v, e := decodeAny(br, depth+1)
 			if e != nil {
 				return nil, e
@@ -537,38 +546,35 @@ func decodeAny(br io.ByteReader, depth int) (any, error) {
 		if !ok {
 			return nil, ErrBadEnvelope
 		}
-		env := Envelope{}
-		if v, ok := m["type"].(string); ok {
-			env.Meta.Type = v
-		}
-		if v, ok := m["schema"].(string); ok {
-			env.Meta.Schema = v
-		}
-		if v, ok := m["version"].(string); ok {
-			env.Meta.Version = v
-		}
-		if v, ok := m["features"].([]any); ok {
-			ff := make([]string, 0, len(v))
-			for _, it := range v {
-				if s, ok := it.(string); ok {
-					ff = append(ff, s)
-				}
-			}
-			env.Meta.Features = ff
-		}
-		if ts, ok := m["createdAt"].(Timestamp); ok {
-			env.Meta.Created = &Timestamp{RFC3339: ts.RFC3339}
-		} else if v, ok := m["createdAt"].(map[string]any); ok {
-			if s, ok := v["value"].(string); ok {
-				env.Meta.Created = &Timestamp{RFC3339: s}
-			}
+		meta, err := MetaFromMap(m)
+		if err != nil {
+			return nil, err
 		}
+		env := Envelope{Meta: meta}
 		body, err := decodeAny(br, depth+1)
 		if err != nil {
 			return nil, err
 		}
 		env.Body = body
 		return env, nil
+	case tagExt:
+		etag, err := readUvarint(br)
+		if err != nil {
+			return nil, err
+		}
+		ln, err := readUvarint(br)
+		if err != nil {
+			return nil, err
+		}
+		payload := make([]byte, ln)
+		for i := 0; i < int(ln); i++ {
+			bt, e := br.ReadByte()
+			if e != nil {
+				return nil, e
+			}
+			payload[i] = bt
+		}
+		return decodeExtension(etag, payload, depth)
 	default:
 		return nil, fmt.Errorf("%w: 0x%02x", ErrUnknownTag, tag)
 	}
@@ -0,0 +1,168 @@
+package jolt
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// The As* helpers convert one decoded JOLT value to a concrete Go type without
+// reflection. They back the UnmarshalJOLT methods emitted by cmd/joltgen;
+// errors carry no path, callers add it.
+
+func AsString(v any) (string, error) {
+	s, ok := v.(string)
+	if !ok {
+		return "", wantGot("string", v)
+	}
+	return s, nil
+}
+
+func AsBool(v any) (bool, error) {
+	b, ok := v.(bool)
+	if !ok {
+		return false, wantGot("bool", v)
+	}
+	return b, nil
+}
+
+// AsInt64 accepts an Int, an integral Decimal or an integral float64.
+func AsInt64(v any) (int64, error) {
+	n, err := integerOf(v)
+	if err != nil {
+		return 0, wantGot("int", v)
+	}
+	if !n.IsInt64() {
+		return 0, fmt.Errorf("%s overflows int64", n)
+	}
+	return n.Int64(), nil
+}
+
+// AsDecimal accepts a Decimal or an Int.
+func AsDecimal(v any) (Decimal, error) {
+	switch x := v.(type) {
+	case Decimal:
+		return x, nil
+	case Int:
+		var d Decimal
+		d.D.Coeff.SetMathBigInt(new(big.Int).Abs(x.V))
+		d.D.Negative = x.V.Sign() < 0
+		return d, nil
+	}
+	return Decimal{}, wantGot("dec", v)
+}
+
+func AsBinary(v any) (Binary, error) {
+	b, ok := v.(Binary)
+	if !ok {
+		return nil, wantGot("bin", v)
+	}
+	return b, nil
+}
+
+func AsTimestamp(v any) (Timestamp, error) {
+	t, ok := v.(Timestamp)
+	if !ok {
+		return Timestamp{}, wantGot("ts", v)
+	}
+	return t, nil
+}
+
+func AsDate(v any) (Date, error) {
+	d, ok := v.(Date)
+	if !ok {
+		return Date{}, wantGot("date", v)
+	}
+	return d, nil
+}
+
+func AsTime(v any) (Time, error) {
+	t, ok := v.(Time)
+	if !ok {
+		return Time{}, wantGot("time", v)
+	}
+	return t, nil
+}
+
+// AsUUID also accepts the canonical string form.
+func AsUUID(v any) (UUID, error) {
+	switch x := v.(type) {
+	case UUID:
+		return x, nil
+	case string:
+		return UUIDFromString(x)
+	}
+	return UUID{}, wantGot("uuid", v)
+}
+
+func AsLink(v any) (Link, error) {
+	l, ok := v.(Link)
+	if !ok {
+		return Link{}, wantGot("link", v)
+	}
+	return l, nil
+}
+
+func AsAnnot(v any) (Annot, error) {
+	a, ok := v.(Annot)
+	if !ok {
+		return Annot{}, wantGot("annot", v)
+	}
+	return a, nil
+}
+
+// AsList accepts an array or a Set.
+func AsList(v any) ([]any, error) {
+	switch x := v.(type) {
+	case []any:
+		return x, nil
+	case Set:
+		return x, nil
+	}
+	return nil, wantGot("array", v)
+}
+
+func AsObject(v any) (map[string]any, error) {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return nil, wantGot("object", v)
+	}
+	return m, nil
+}
+
+// AsStringMap accepts an object or a Map whose keys are all strings.
+func AsStringMap(v any) (map[string]any, error) {
+	switch x := v.(type) {
+	case map[string]any:
+		return x, nil
+	case Map:
+		out := make(map[string]any, len(x))
+		for k, val := range x {
+			s, ok := k.(string)
+			if !ok {
+				return nil, fmt.Errorf("map key %v is not a string", k)
+			}
+			out[s] = val
+		}
+		return out, nil
+	}
+	return nil, wantGot("map", v)
+}
+
+// AsMap accepts a Map or an object.
+func AsMap(v any) (Map, error) {
+	switch x := v.(type) {
+	case Map:
+		return x, nil
+	case map[string]any:
+		out := make(Map, len(x))
+		for k, val := range x {
+			out[k] = val
+		}
+		return out, nil
+	}
+	return nil, wantGot("map", v)
+}
+
+func wantGot(want string, v any) error {
+	return fmt.Errorf("want %s, got %T", want, v)
+}
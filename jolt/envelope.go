@@ -1,5 +1,13 @@
 package jolt
 
+import (
+    "bytes"
+    "encoding/json"
+    "fmt"
+    "sort"
+    "time"
+)
+
 type Meta struct {
     Type     string      `json:"type,omitempty"`
     Schema   string      `json:"schema,omitempty"`
@@ -7,9 +15,145 @@ type Meta struct {
     Created  *Timestamp  `json:"createdAt,omitempty"`
     Features []string    `json:"features,omitempty"`
     Sig      any         `json:"sig,omitempty"`
+    // Extra holds any other $meta keys (e.g. "tenant", "received") so they
+    // survive a decode/encode round trip.
+    Extra    map[string]any `json:"-"`
 }
 
 type Envelope struct {
     Meta Meta `json:"$meta"`
     Body any  `json:"$body"`
 }
+
+// metaKeys are the $meta keys that map onto Meta's named fields.
+var metaKeys = map[string]bool{"type": true, "schema": true, "version": true, "createdAt": true, "features": true, "sig": true}
+
+// ToMap returns the $meta object as written into JOLT-B. Extra keys never
+// override the named fields.
+func (m Meta) ToMap() map[string]any {
+    out := map[string]any{"type": m.Type, "schema": m.Schema, "version": m.Version, "features": m.Features}
+    if m.Created != nil {
+        out["createdAt"] = *m.Created
+    }
+    if m.Sig != nil {
+        out["sig"] = m.Sig
+    }
+    for k, v := range m.Extra {
+        if !metaKeys[k] {
+            out[k] = v
+        }
+    }
+    return out
+}
+
+// MetaFromMap parses a $meta object, either decoded from JOLT-B or from JSON
+// (where createdAt may still be a {"@type":"ts"} wrapper or an RFC 3339
+// string). Unknown keys are kept in Extra.
+func MetaFromMap(mm map[string]any) (Meta, error) {
+    var meta Meta
+    str := func(k string) (string, error) {
+        switch v := mm[k].(type) {
+        case nil:
+            return "", nil
+        case string:
+            return v, nil
+        default:
+            return "", fmt.Errorf("%w: %s must be a string, got %T", ErrBadEnvelope, k, v)
+        }
+    }
+    var err error
+    if meta.Type, err = str("type"); err != nil { return Meta{}, err }
+    if meta.Schema, err = str("schema"); err != nil { return Meta{}, err }
+    if meta.Version, err = str("version"); err != nil { return Meta{}, err }
+
+    switch v := mm["features"].(type) {
+    case nil:
+    case []any:
+        ff := make([]string, 0, len(v))
+        for _, it := range v {
+            s, ok := it.(string)
+            if !ok { return Meta{}, fmt.Errorf("%w: features must be strings, got %T", ErrBadEnvelope, it) }
+            ff = append(ff, s)
+        }
+        meta.Features = ff
+    case []string:
+        meta.Features = append([]string(nil), v...)
+    default:
+        return Meta{}, fmt.Errorf("%w: features must be an array, got %T", ErrBadEnvelope, v)
+    }
+
+    switch v := mm["createdAt"].(type) {
+    case nil:
+    case Timestamp:
+        meta.Created = &Timestamp{RFC3339: v.RFC3339}
+    case map[string]any:
+        s, ok := v["value"].(string)
+        if v["@type"] != "ts" || !ok {
+            return Meta{}, fmt.Errorf("%w: createdAt must be a ts", ErrBadEnvelope)
+        }
+        meta.Created = &Timestamp{RFC3339: s}
+    case string:
+        if _, err := time.Parse(time.RFC3339Nano, v); err != nil {
+            return Meta{}, fmt.Errorf("%w: createdAt %q is not RFC 3339", ErrBadEnvelope, v)
+        }
+        meta.Created = &Timestamp{RFC3339: v}
+    default:
+        return Meta{}, fmt.Errorf("%w: createdAt must be a ts, got %T", ErrBadEnvelope, v)
+    }
+
+    meta.Sig = mm["sig"]
+    for k, v := range mm {
+        if metaKeys[k] { continue }
+        if meta.Extra == nil { meta.Extra = map[string]any{} }
+        meta.Extra[k] = v
+    }
+    return meta, nil
+}
+
+// MarshalJSON writes the named fields as tagged, followed by Extra keys in
+// sorted order.
+func (m Meta) MarshalJSON() ([]byte, error) {
+    type plain Meta
+    if m.Sig != nil {
+        sig, err := toJSONCompat(m.Sig)
+        if err != nil { return nil, err }
+        m.Sig = sig
+    }
+    base, err := json.Marshal(plain(m))
+    if err != nil { return nil, err }
+    ks := make([]string, 0, len(m.Extra))
+    for k := range m.Extra {
+        if !metaKeys[k] { ks = append(ks, k) }
+    }
+    if len(ks) == 0 { return base, nil }
+    sort.Strings(ks)
+
+    var buf bytes.Buffer
+    buf.Write(base[:len(base)-1])
+    for i, k := range ks {
+        if i > 0 || len(base) > 2 { buf.WriteByte(',') }
+        kb, _ := json.Marshal(k)
+        v, err := toJSONCompat(m.Extra[k])
+        if err != nil { return nil, err }
+        vb, err := json.Marshal(v)
+        if err != nil { return nil, err }
+        buf.Write(kb)
+        buf.WriteByte(':')
+        buf.Write(vb)
+    }
+    buf.WriteByte('}')
+    return buf.Bytes(), nil
+}
+
+func (m *Meta) UnmarshalJSON(b []byte) error {
+    dec := json.NewDecoder(bytes.NewReader(b))
+    dec.UseNumber()
+    var raw map[string]any
+    if err := dec.Decode(&raw); err != nil { return err }
+    lifted, err := LiftTyped(raw)
+    if err != nil { return err }
+    meta, err := MetaFromMap(lifted.(map[string]any))
+    if err != nil { return err }
+    *m = meta
+    return nil
+}
@@ -0,0 +1,163 @@
+package jolt
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+)
+
+// Extension tags below ExtUserMin are reserved for types shipped with this
+// library; RegisterExtension refuses them.
+const ExtUserMin uint64 = 0x80
+
+var (
+	ErrReservedExtension = errors.New("jolt: extension tag is in the reserved range")
+	ErrExtensionConflict = errors.New("jolt: extension already registered")
+)
+
+// ExtEncodeFunc maps a registered Go value onto a representation built from
+// JOLT values (strings, Int, Decimal, []any, map[string]any, ...). The same
+// representation is used for the binary payload and the "@type" JSON form.
+type ExtEncodeFunc func(v any) (any, error)
+
+// ExtDecodeFunc rebuilds the Go value from the representation produced by
+// the matching ExtEncodeFunc.
+type ExtDecodeFunc func(repr any) (any, error)
+
+// Extension is an extension value whose tag is not registered in this
+// process. Raw holds the payload exactly as received so it re-encodes
+// byte-identically.
+type Extension struct {
+	Tag uint64
+	Raw []byte
+}
+
+type extension struct {
+	tag    uint64
+	goType reflect.Type
+	name   string
+	enc    ExtEncodeFunc
+	dec    ExtDecodeFunc
+}
+
+var extReg = struct {
+	sync.RWMutex
+	byTag  map[uint64]*extension
+	byType map[reflect.Type]*extension
+	byName map[string]*extension
+}{
+	byTag:  map[uint64]*extension{},
+	byType: map[reflect.Type]*extension{},
+	byName: map[string]*extension{},
+}
+
+// builtinTypeNames are the "@type" names understood by the typed JSON form.
+var builtinTypeNames = map[string]bool{
+	"int": true, "dec": true, "bin": true, "uuid": true, "link": true, "annot": true,
+	"ts": true, "date": true, "time": true, "set": true, "map": true, "ext": true,
+}
+
+// RegisterExtension binds tag and jsonTypeName to goType. Values of goType are
+// encoded through enc and decoded through dec. Tags, Go types and JSON names
+// must all be unique; tags below ExtUserMin are reserved.
+func RegisterExtension(tag uint64, goType reflect.Type, enc ExtEncodeFunc, dec ExtDecodeFunc, jsonTypeName string) error {
+	if tag < ExtUserMin {
+		return fmt.Errorf("%w: %d", ErrReservedExtension, tag)
+	}
+	return registerExtension(tag, goType, enc, dec, jsonTypeName)
+}
+
+func registerExtension(tag uint64, goType reflect.Type, enc ExtEncodeFunc, dec ExtDecodeFunc, name string) error {
+	if goType == nil || enc == nil || dec == nil || name == "" {
+		return errors.New("jolt: RegisterExtension needs a type, codec functions and a JSON type name")
+	}
+	if builtinTypeNames[name] {
+		return fmt.Errorf("%w: JSON type name %q is built in", ErrExtensionConflict, name)
+	}
+	extReg.Lock()
+	defer extReg.Unlock()
+	if e, ok := extReg.byTag[tag]; ok {
+		return fmt.Errorf("%w: tag %d is used by %s", ErrExtensionConflict, tag, e.goType)
+	}
+	if e, ok := extReg.byType[goType]; ok {
+		return fmt.Errorf("%w: %s already has tag %d", ErrExtensionConflict, goType, e.tag)
+	}
+	if e, ok := extReg.byName[name]; ok {
+		return fmt.Errorf("%w: JSON type name %q is used by tag %d", ErrExtensionConflict, name, e.tag)
+	}
+	e := &extension{tag: tag, goType: goType, name: name, enc: enc, dec: dec}
+	extReg.byTag[tag] = e
+	extReg.byType[goType] = e
+	extReg.byName[name] = e
+	return nil
+}
+
+// UnregisterExtension removes the registration for tag, if any.
+func UnregisterExtension(tag uint64) {
+	extReg.Lock()
+	defer extReg.Unlock()
+	if e, ok := extReg.byTag[tag]; ok {
+		delete(extReg.byTag, tag)
+		delete(extReg.byType, e.goType)
+		delete(extReg.byName, e.name)
+	}
+}
+
+func extensionForType(t reflect.Type) *extension {
+	extReg.RLock()
+	defer extReg.RUnlock()
+	return extReg.byType[t]
+}
+
+func extensionForTag(tag uint64) *extension {
+	extReg.RLock()
+	defer extReg.RUnlock()
+	return extReg.byTag[tag]
+}
+
+func extensionForName(name string) *extension {
+	extReg.RLock()
+	defer extReg.RUnlock()
+	return extReg.byName[name]
+}
+
+func encodeExtension(w io.Writer, e *extension, v any, depth int) error {
+	repr, err := e.enc(v)
+	if err != nil {
+		return fmt.Errorf("jolt: extension %q: %w", e.name, err)
+	}
+	var payload bytes.Buffer
+	if err := encodeAny(&payload, repr, depth+1); err != nil {
+		return err
+	}
+	return writeExtension(w, e.tag, payload.Bytes())
+}
+
+func writeExtension(w io.Writer, tag uint64, payload []byte) error {
+	if _, err := w.Write([]byte{tagExt}); err != nil {
+		return err
+	}
+	if err := putUvarint(w, tag); err != nil {
+		return err
+	}
+	return writeBytes(w, payload)
+}
+
+func decodeExtension(tag uint64, payload []byte, depth int) (any, error) {
+	e := extensionForTag(tag)
+	if e == nil {
+		return Extension{Tag: tag, Raw: payload}, nil
+	}
+	repr, err := decodeAny(bytes.NewBuffer(payload), depth+1)
+	if err != nil {
+		return nil, err
+	}
+	v, err := e.dec(repr)
+	if err != nil {
+		return nil, fmt.Errorf("jolt: extension %q: %w", e.name, err)
+	}
+	return v, nil
+}
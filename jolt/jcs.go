@@ -0,0 +1,191 @@
+package jolt
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// MarshalCanonicalJSON renders v as RFC 8785 (JCS) canonical JSON: no
+// whitespace, object keys sorted by UTF-16 code units, numbers in ES6 form
+// and strings with minimal escaping, so equal values give equal bytes to sign
+// or hash.
+//
+// Rich types are written in their "@type" wrapper form, as MarshalJSONCompat
+// writes them: Int and Decimal become {"@type":"int"|"dec","value":"…"} with
+// the digits as a string, so they stay exact instead of passing through an
+// IEEE double, and Set members and Map entries are in canonical JOLT-B order.
+// Plain Go numbers (int, float64, json.Number) are JCS numbers and must be
+// finite doubles.
+func MarshalCanonicalJSON(v any) ([]byte, error) {
+	js, err := MarshalJSONCompat(canonicalSets(v), false)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(js))
+	dec.UseNumber()
+	var tree any
+	if err := dec.Decode(&tree); err != nil {
+		return nil, err
+	}
+	var b bytes.Buffer
+	if err := writeJCS(&b, tree); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// canonicalSets puts Set members in canonical order; Map.MarshalJSON already
+// sorts its entries.
+func canonicalSets(v any) any {
+	switch x := v.(type) {
+	case Set:
+		out := make(Set, len(x))
+		for i, it := range sortedCanonical(x) {
+			out[i] = canonicalSets(it)
+		}
+		return out
+	case Map:
+		out := make(Map, len(x))
+		for k, it := range x {
+			out[k] = canonicalSets(it)
+		}
+		return out
+	case []any:
+		out := make([]any, len(x))
+		for i, it := range x {
+			out[i] = canonicalSets(it)
+		}
+		return out
+	case map[string]any:
+		out := make(map[string]any, len(x))
+		for k, it := range x {
+			out[k] = canonicalSets(it)
+		}
+		return out
+	case Envelope:
+		x.Body = canonicalSets(x.Body)
+		return x
+	}
+	return v
+}
+
+func writeJCS(b *bytes.Buffer, v any) error {
+	switch x := v.(type) {
+	case nil:
+		b.WriteString("null")
+	case bool:
+		b.WriteString(strconv.FormatBool(x))
+	case string:
+		writeJCSString(b, x)
+	case json.Number:
+		f, err := strconv.ParseFloat(x.String(), 64)
+		if err != nil {
+			return fmt.Errorf("jolt: number %s is not a double", x)
+		}
+		s, err := es6Number(f)
+		if err != nil {
+			return err
+		}
+		b.WriteString(s)
+	case []any:
+		b.WriteByte('[')
+		for i, it := range x {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			if err := writeJCS(b, it); err != nil {
+				return err
+			}
+		}
+		b.WriteByte(']')
+	case map[string]any:
+		ks := make([]string, 0, len(x))
+		for k := range x {
+			ks = append(ks, k)
+		}
+		sort.Slice(ks, func(i, j int) bool { return lessUTF16(ks[i], ks[j]) })
+		b.WriteByte('{')
+		for i, k := range ks {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			writeJCSString(b, k)
+			b.WriteByte(':')
+			if err := writeJCS(b, x[k]); err != nil {
+				return err
+			}
+		}
+		b.WriteByte('}')
+	default:
+		return fmt.Errorf("jolt: unexpected %T in canonical JSON", v)
+	}
+	return nil
+}
+
+// lessUTF16 orders strings by their UTF-16 code units, as JCS sorts keys.
+func lessUTF16(a, b string) bool {
+	ua, ub := utf16.Encode([]rune(a)), utf16.Encode([]rune(b))
+	for i := 0; i < len(ua) && i < len(ub); i++ {
+		if ua[i] != ub[i] {
+			return ua[i] < ub[i]
+		}
+	}
+	return len(ua) < len(ub)
+}
+
+// writeJCSString escapes only what JSON requires: quote, backslash and
+// control characters, the latter in short form where one exists.
+func writeJCSString(b *bytes.Buffer, s string) {
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(b, `\u%04x`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+}
+
+// es6Number formats f as ECMAScript's Number.prototype.toString does: the
+// shortest round-tripping digits, in exponent form below 1e-6 and from 1e21.
+func es6Number(f float64) (string, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return "", fmt.Errorf("jolt: %v has no canonical JSON form", f)
+	}
+	if f == 0 {
+		return "0", nil // also -0
+	}
+	if abs := math.Abs(f); abs < 1e-6 || abs >= 1e21 {
+		s := strconv.FormatFloat(f, 'e', -1, 64)
+		// Go pads the exponent to two digits: 1e-07 is 1e-7 in ES6.
+		mant, exp, _ := strings.Cut(s, "e")
+		sign := exp[:1]
+		exp = strings.TrimLeft(exp[1:], "0")
+		return mant + "e" + sign + exp, nil
+	}
+	return strconv.FormatFloat(f, 'f', -1, 64), nil
+}
@@ -0,0 +1,254 @@
+package jolt
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// UnmarshalJSON5 parses JSON5 (https://spec.json5.org) into JOLT values as
+// UnmarshalJSONTyped does JSON: besides comments it takes trailing commas,
+// unquoted keys, single-quoted strings with JSON5 escapes and line
+// continuations, hexadecimal integers, leading or trailing decimal points,
+// explicit plus signs, and Infinity and NaN. Integers become Int, other
+// numbers Decimal; Infinity and NaN are non-finite Decimals, which
+// EncodeBinary rejects since JOLT-B decimals are finite.
+// Syntax errors are *SyntaxError, with line and column.
+func UnmarshalJSON5(data []byte) (any, error) {
+	return (&jsoncParser{s: data, json5: true}).parse()
+}
+
+// space5 returns the length of the JSON5-only whitespace at p.i, or 0.
+func (p *jsoncParser) space5() int {
+	if !p.json5 || p.i >= len(p.s) {
+		return 0
+	}
+	if c := p.s[p.i]; c == '\v' || c == '\f' {
+		return 1
+	}
+	r, n := utf8.DecodeRune(p.s[p.i:])
+	if r == '\ufeff' || r >= 0x80 && unicode.IsSpace(r) {
+		return n
+	}
+	return 0
+}
+
+// value5 parses the JSON5 forms of strings and numbers; ok is false for
+// anything JSON parses the same way.
+func (p *jsoncParser) value5() (v any, ok bool, err error) {
+	switch c := p.s[p.i]; {
+	case c == '"' || c == '\'':
+		s, err := p.str5()
+		return s, true, err
+	case c == '+' || c == '-' || c == '.' || c >= '0' && c <= '9':
+		v, err := p.number5()
+		return v, true, err
+	}
+	id := p.ident()
+	p.i += len(id)
+	switch id {
+	case "Infinity", "NaN":
+		return json.Number(id), true, nil
+	case "true":
+		return true, true, nil
+	case "false":
+		return false, true, nil
+	case "null":
+		return nil, true, nil
+	}
+	p.i -= len(id)
+	return nil, false, nil
+}
+
+// ident returns the identifier at p.i without consuming it.
+func (p *jsoncParser) ident() string {
+	j := p.i
+	for j < len(p.s) {
+		r, n := utf8.DecodeRune(p.s[j:])
+		if r != '$' && r != '_' && !unicode.IsLetter(r) && (j == p.i || !unicode.IsDigit(r) && !unicode.In(r, unicode.Mn, unicode.Mc, unicode.Pc) && r != '\u200c' && r != '\u200d') {
+			break
+		}
+		j += n
+	}
+	return string(p.s[p.i:j])
+}
+
+func (p *jsoncParser) key5() (string, error) {
+	if p.i < len(p.s) && (p.s[p.i] == '"' || p.s[p.i] == '\'') {
+		return p.str5()
+	}
+	id := p.ident()
+	if id == "" {
+		return "", p.unexpected("a key")
+	}
+	p.i += len(id)
+	return id, nil
+}
+
+// number5 returns a json.Number in the form LiftTyped reads: decimal
+// digits, with an exponent or fraction for non-integers.
+func (p *jsoncParser) number5() (any, error) {
+	start := p.i
+	neg := false
+	if c := p.s[p.i]; c == '+' || c == '-' {
+		neg = c == '-'
+		p.i++
+	}
+	sign := ""
+	if neg {
+		sign = "-"
+	}
+	if id := p.ident(); id == "Infinity" || id == "NaN" {
+		p.i += len(id)
+		return json.Number(sign + id), nil
+	}
+	if p.i+1 < len(p.s) && p.s[p.i] == '0' && (p.s[p.i+1] == 'x' || p.s[p.i+1] == 'X') {
+		p.i += 2
+		j := p.i
+		for p.i < len(p.s) && strings.IndexByte("0123456789abcdefABCDEF", p.s[p.i]) >= 0 {
+			p.i++
+		}
+		z, ok := new(big.Int).SetString(string(p.s[j:p.i]), 16)
+		if !ok {
+			return nil, syntaxErrorAt(p.s, start, "bad hex number %s", p.s[start:p.i])
+		}
+		return json.Number(sign + z.String()), p.numberEnd(start)
+	}
+	j := p.i
+	for p.i < len(p.s) && strings.IndexByte(".eE+-0123456789", p.s[p.i]) >= 0 {
+		if (p.s[p.i] == '+' || p.s[p.i] == '-') && p.s[p.i-1] != 'e' && p.s[p.i-1] != 'E' {
+			break
+		}
+		p.i++
+	}
+	lit := string(p.s[j:p.i])
+	mant, exp, hasExp := strings.Cut(strings.ToLower(lit), "e")
+	switch {
+	case strings.HasPrefix(mant, "."):
+		mant = "0" + mant
+	case strings.HasSuffix(mant, "."):
+		mant = strings.TrimSuffix(mant, ".")
+		if !hasExp {
+			exp, hasExp = "0", true // 5. is a Decimal, not an Int
+		}
+	}
+	norm := mant
+	if hasExp {
+		norm += "e" + exp
+	}
+	if !json.Valid([]byte(norm)) {
+		return nil, syntaxErrorAt(p.s, start, "bad number %s", p.s[start:p.i])
+	}
+	norm = sign + norm
+	return json.Number(norm), p.numberEnd(start)
+}
+
+// numberEnd rejects numbers running into identifiers, like 12px.
+func (p *jsoncParser) numberEnd(start int) error {
+	if id := p.ident(); id != "" || p.i < len(p.s) && p.s[p.i] >= '0' && p.s[p.i] <= '9' {
+		return syntaxErrorAt(p.s, start, "bad number %s", p.s[start:p.i+len(id)])
+	}
+	return nil
+}
+
+// str5 parses a single- or double-quoted JSON5 string.
+func (p *jsoncParser) str5() (string, error) {
+	start := p.i
+	quote := p.s[p.i]
+	p.i++
+	var b strings.Builder
+	for {
+		if p.i >= len(p.s) {
+			return "", syntaxErrorAt(p.s, start, "unterminated string")
+		}
+		c := p.s[p.i]
+		switch {
+		case c == quote:
+			p.i++
+			return b.String(), nil
+		case c == '\n' || c == '\r':
+			return "", p.errf("newline in string (escape it with \\)")
+		case c != '\\':
+			b.WriteByte(c)
+			p.i++
+			continue
+		}
+		p.i++
+		if p.i >= len(p.s) {
+			return "", syntaxErrorAt(p.s, start, "unterminated string")
+		}
+		esc, n := utf8.DecodeRune(p.s[p.i:])
+		p.i += n
+		switch esc {
+		case 'b':
+			b.WriteByte('\b')
+		case 'f':
+			b.WriteByte('\f')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		case 'v':
+			b.WriteByte('\v')
+		case '0':
+			if p.i < len(p.s) && p.s[p.i] >= '0' && p.s[p.i] <= '9' {
+				return "", p.errf("bad escape \\0 followed by a digit")
+			}
+			b.WriteByte(0)
+		case 'x':
+			r, err := p.hex5(2)
+			if err != nil {
+				return "", err
+			}
+			b.WriteRune(r)
+		case 'u':
+			r, err := p.hex5(4)
+			if err != nil {
+				return "", err
+			}
+			if utf16.IsSurrogate(r) && bytes.HasPrefix(p.s[p.i:], []byte(`\u`)) {
+				p.i += 2
+				lo, err := p.hex5(4)
+				if err != nil {
+					return "", err
+				}
+				r = utf16.DecodeRune(r, lo)
+			}
+			b.WriteRune(r)
+		case '\n', '\u2028', '\u2029':
+			// line continuation
+		case '\r':
+			if p.i < len(p.s) && p.s[p.i] == '\n' {
+				p.i++
+			}
+		default:
+			if esc >= '1' && esc <= '9' {
+				p.i -= n
+				return "", p.errf("bad escape \\%c", esc)
+			}
+			b.WriteRune(esc)
+		}
+	}
+}
+
+func (p *jsoncParser) hex5(n int) (rune, error) {
+	if p.i+n > len(p.s) {
+		return 0, p.errf("short escape")
+	}
+	var r rune
+	for _, c := range p.s[p.i : p.i+n] {
+		d := strings.IndexByte("0123456789abcdef", byte(unicode.ToLower(rune(c))))
+		if d < 0 {
+			return 0, p.errf("bad hex escape %q", p.s[p.i:p.i+n])
+		}
+		r = r<<4 | rune(d)
+	}
+	p.i += n
+	return r, nil
+}
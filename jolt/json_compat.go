@@ -2,7 +2,14 @@ package jolt
 
 import "encoding/json"
 
+// MarshalJSONCompat writes v as JSON with "@type" wrappers for rich types.
+// With indent, comments kept by UnmarshalJSONC are written back as JSONC.
 func MarshalJSONCompat(v any, indent bool) ([]byte, error) {
+    v, err := toJSONCompat(v)
+    if err != nil { return nil, err }
+    if indent && hasComments(v) {
+        return marshalJSONC(v)
+    }
     if indent {
         return json.MarshalIndent(v, "", "  ")
     }
@@ -0,0 +1,324 @@
+package jolt
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"reflect"
+	"strings"
+)
+
+// UnmarshalJSONTyped parses JSON (comments allowed) into JOLT values: "@type"
+// wrappers are lifted into rich types and numbers are kept exact, integers as
+// Int and everything else as Decimal.
+func UnmarshalJSONTyped(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(StripJSONComments(data)))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("jolt: trailing data after JSON value")
+	}
+	return LiftTyped(v)
+}
+
+// LiftTyped walks a generic JSON tree and replaces "@type" wrappers (as
+// produced by MarshalJSONCompat) with the JOLT values they describe. Objects
+// with an unknown "@type" are left as plain objects.
+func LiftTyped(v any) (any, error) {
+	switch x := v.(type) {
+	case json.Number:
+		return numberFromString(x.String())
+	case []any:
+		out := make([]any, len(x))
+		for i, it := range x {
+			lv, err := LiftTyped(it)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = lv
+		}
+		return out, nil
+	case map[string]any:
+		if name, ok := x["@type"].(string); ok {
+			lv, ok, err := liftWrapper(name, x)
+			if err != nil || ok {
+				return lv, err
+			}
+		}
+		out := make(map[string]any, len(x))
+		for k, it := range x {
+			lv, err := LiftTyped(it)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = lv
+		}
+		return out, nil
+	case Envelope:
+		body, err := LiftTyped(x.Body)
+		if err != nil {
+			return nil, err
+		}
+		x.Body = body
+		return x, nil
+	default:
+		return v, nil
+	}
+}
+
+// numberFromString keeps integer literals as Int and everything else as Decimal.
+func numberFromString(s string) (any, error) {
+	if !strings.ContainsAny(s, ".eE") {
+		if z, ok := new(big.Int).SetString(s, 10); ok {
+			return Int{V: z}, nil
+		}
+	}
+	d, err := DecFromString(s)
+	if err != nil {
+		return nil, fmt.Errorf("jolt: bad number %q", s)
+	}
+	return d, nil
+}
+
+func liftWrapper(name string, m map[string]any) (any, bool, error) {
+	str := func(keys ...string) (string, error) {
+		for _, k := range keys {
+			if s, ok := m[k].(string); ok {
+				return s, nil
+			}
+		}
+		return "", fmt.Errorf("jolt: @type %q needs a string %q", name, keys[0])
+	}
+	switch name {
+	case "int":
+		if n, ok := m["value"].(json.Number); ok {
+			v, err := IntFromString(n.String())
+			return v, true, err
+		}
+		s, err := str("value")
+		if err != nil {
+			return nil, true, err
+		}
+		v, err := IntFromString(s)
+		if err != nil {
+			return nil, true, fmt.Errorf("jolt: bad int %q", s)
+		}
+		return v, true, nil
+	case "dec":
+		var s string
+		if n, ok := m["value"].(json.Number); ok {
+			s = n.String()
+		} else {
+			var err error
+			if s, err = str("value"); err != nil {
+				return nil, true, err
+			}
+		}
+		d, err := DecFromString(s)
+		if err != nil {
+			return nil, true, fmt.Errorf("jolt: bad dec %q", s)
+		}
+		return d, true, nil
+	case "bin":
+		s, err := str("value")
+		if err != nil {
+			return nil, true, err
+		}
+		b, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return nil, true, fmt.Errorf("jolt: bad bin: %w", err)
+		}
+		return Binary(b), true, nil
+	case "uuid":
+		s, err := str("value")
+		if err != nil {
+			return nil, true, err
+		}
+		u, err := UUIDFromString(s)
+		return u, true, err
+	case "ts":
+		s, err := str("value")
+		return Timestamp{RFC3339: s}, true, err
+	case "date":
+		s, err := str("value")
+		return Date{YYYYMMDD: s}, true, err
+	case "time":
+		s, err := str("value")
+		return Time{HHMMSS: s}, true, err
+	case "link":
+		s, err := str("ref", "value")
+		return Link{Ref: s}, true, err
+	case "annot":
+		s, err := str("note", "value")
+		if err != nil {
+			// annotated values ({"label":..,"value":{..}}) are not Annot notes
+			return nil, false, nil
+		}
+		return Annot{Note: s}, true, nil
+	case "set":
+		arr, ok := m["value"].([]any)
+		if !ok {
+			return nil, true, fmt.Errorf("jolt: @type \"set\" needs an array value")
+		}
+		out := make(Set, 0, len(arr))
+		for _, it := range arr {
+			lv, err := LiftTyped(it)
+			if err != nil {
+				return nil, true, err
+			}
+			out = append(out, lv)
+		}
+		return out, true, nil
+	case "map":
+		arr, ok := m["value"].([]any)
+		if !ok {
+			return nil, true, fmt.Errorf("jolt: @type \"map\" needs an array of entries")
+		}
+		out := make(Map, len(arr))
+		for _, it := range arr {
+			ent, ok := it.(map[string]any)
+			if !ok {
+				return nil, true, fmt.Errorf("jolt: map entry must be an object with key and value")
+			}
+			k, err := LiftTyped(ent["key"])
+			if err != nil {
+				return nil, true, err
+			}
+			if k != nil && !reflect.TypeOf(k).Comparable() {
+				return nil, true, fmt.Errorf("jolt: map key of type %T is not usable as a key", k)
+			}
+			val, err := LiftTyped(ent["value"])
+			if err != nil {
+				return nil, true, err
+			}
+			out[k] = val
+		}
+		return out, true, nil
+	case "ext":
+		tag, ok := m["tag"].(json.Number)
+		if !ok {
+			if f, isF := m["tag"].(float64); isF {
+				tag, ok = json.Number(fmt.Sprint(uint64(f))), true
+			}
+		}
+		if !ok {
+			return nil, true, fmt.Errorf("jolt: @type \"ext\" needs a numeric tag")
+		}
+		t, err := IntFromString(tag.String())
+		if err != nil || t.V.Sign() < 0 || !t.V.IsUint64() {
+			return nil, true, fmt.Errorf("jolt: bad extension tag %s", tag)
+		}
+		s, err := str("value")
+		if err != nil {
+			return nil, true, err
+		}
+		raw, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return nil, true, fmt.Errorf("jolt: bad ext payload: %w", err)
+		}
+		v, err := decodeExtension(t.V.Uint64(), raw, 0)
+		return v, true, err
+	}
+	if e := extensionForName(name); e != nil {
+		repr, err := LiftTyped(m["value"])
+		if err != nil {
+			return nil, true, err
+		}
+		v, err := e.dec(repr)
+		if err != nil {
+			return nil, true, fmt.Errorf("jolt: extension %q: %w", name, err)
+		}
+		return v, true, nil
+	}
+	return nil, false, nil
+}
+
+// toJSONCompat rewrites values that encoding/json cannot render in their
+// "@type" form on its own (Sets, Maps, extensions, and containers holding
+// them). Map entries come out in canonical key order, so output is stable.
+func toJSONCompat(v any) (any, error) {
+	switch x := v.(type) {
+	case nil, string, bool, Int, Decimal, Binary, UUID, Link, Annot, Timestamp, Date, Time:
+		return v, nil
+	case Set:
+		vals, err := toJSONCompat([]any(x))
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"@type": "set", "value": vals}, nil
+	case Map:
+		keys := make([]any, 0, len(x))
+		for k := range x {
+			if _, err := EncodeBinary(k); err != nil {
+				return nil, err
+			}
+			keys = append(keys, k)
+		}
+		vals := make([]any, 0, len(x))
+		for _, k := range sortedCanonical(keys) {
+			ck, err := toJSONCompat(k)
+			if err != nil {
+				return nil, err
+			}
+			cv, err := toJSONCompat(x[k])
+			if err != nil {
+				return nil, err
+			}
+			vals = append(vals, map[string]any{"key": ck, "value": cv})
+		}
+		return map[string]any{"@type": "map", "value": vals}, nil
+	case []any:
+		out := make([]any, len(x))
+		for i, it := range x {
+			cv, err := toJSONCompat(it)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = cv
+		}
+		return out, nil
+	case map[string]any:
+		out := make(map[string]any, len(x))
+		for k, it := range x {
+			cv, err := toJSONCompat(it)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = cv
+		}
+		return out, nil
+	case Envelope:
+		body, err := toJSONCompat(x.Body)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"$meta": x.Meta, "$body": body}, nil
+	case Extension:
+		return map[string]any{"@type": "ext", "tag": x.Tag, "value": base64.StdEncoding.EncodeToString(x.Raw)}, nil
+	}
+	if m, ok := v.(Marshaler); ok && !isNilValue(v) {
+		repr, err := m.MarshalJOLT()
+		if err != nil {
+			return nil, fmt.Errorf("jolt: MarshalJOLT for %T: %w", v, err)
+		}
+		return toJSONCompat(repr)
+	}
+	if e := extensionForType(reflect.TypeOf(v)); e != nil {
+		repr, err := e.enc(v)
+		if err != nil {
+			return nil, fmt.Errorf("jolt: extension %q: %w", e.name, err)
+		}
+		cv, err := toJSONCompat(repr)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"@type": e.name, "value": cv}, nil
+	}
+	return v, nil
+}
@@ -0,0 +1,524 @@
+package jolt
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Comments read by UnmarshalJSONC are kept in a "$comment" member of the
+// nearest enclosing object, keyed by the JSON pointer (relative to that
+// object) of the value they belong to:
+//
+//	{
+//	  // unit price, EUR
+//	  "price": 19.99, // before tax
+//	  "lines": [
+//	    // the free sample
+//	    { "sku": "A-1" }
+//	  ]
+//	}
+//
+// keeps
+//
+//	"$comment": {
+//	  "/price":   { "before": "// unit price, EUR", "after": "// before tax" },
+//	  "/lines/0": { "before": "// the free sample" }
+//	}
+//
+// "before" holds the comments on the lines above a value, "after" those
+// following it on the same line (after its comma, if any) and "end" those
+// before the closing bracket of an object or array. The entry for "" is the
+// object itself; only the root object keeps its "before" and "after" there,
+// "after" then being everything following the document. Comments are stored
+// as written, several joined by newlines. Comments inside "@type" wrappers,
+// in arrays outside any object, or in objects that already have an explicit
+// "$comment" are dropped.
+//
+// The binary codec keeps "$comment" only with PreserveComments set, so
+// JSONC → JOLT-B → JSONC keeps comments under that flag;
+// MarshalJSONCompat with indent writes them back in place.
+
+// Comment entry fields.
+const (
+	commentBefore = "before"
+	commentAfter  = "after"
+	commentEnd    = "end"
+)
+
+// UnmarshalJSONC is UnmarshalJSONTyped that keeps comments, attaching them
+// to the values they document as described above. Syntax errors are
+// *SyntaxError.
+func UnmarshalJSONC(data []byte) (any, error) {
+	return (&jsoncParser{s: data, keep: true}).parse()
+}
+
+func (p *jsoncParser) parse() (any, error) {
+	lead := p.comments()
+	var v any
+	var err error
+	if p.i < len(p.s) && p.s[p.i] == '{' {
+		var own commentScope
+		v, own, err = p.object()
+		if err == nil {
+			own.add("", commentBefore, lead)
+			own.add("", commentAfter, p.comments())
+			own.attach(v.(map[string]any))
+		}
+	} else {
+		v, err = p.value(nil, "")
+		p.comments()
+	}
+	if err != nil {
+		return nil, err
+	}
+	if p.err != nil {
+		return nil, p.err
+	}
+	if p.i < len(p.s) {
+		return nil, p.errf("unexpected %q after JSON value", p.s[p.i])
+	}
+	return LiftTyped(v)
+}
+
+// commentScope collects the comments of one object; nil drops them.
+type commentScope map[string]any
+
+func (c commentScope) add(ptr, field string, texts []string) {
+	if c == nil || len(texts) == 0 {
+		return
+	}
+	sep := "\n"
+	if field == commentAfter && ptr != "" {
+		sep = " "
+	}
+	e, _ := c[ptr].(map[string]any)
+	if e == nil {
+		e = map[string]any{}
+		c[ptr] = e
+	}
+	if prev, ok := e[field].(string); ok {
+		texts = append([]string{prev}, texts...)
+	}
+	e[field] = strings.Join(texts, sep)
+}
+
+func (c commentScope) attach(obj map[string]any) {
+	if _, explicit := obj["$comment"]; len(c) > 0 && !explicit {
+		obj["$comment"] = map[string]any(c)
+	}
+}
+
+type jsoncParser struct {
+	s     []byte
+	i     int
+	err   error // unterminated comment
+	keep  bool  // attach comments
+	json5 bool  // accept JSON5
+}
+
+func (p *jsoncParser) errf(format string, args ...any) error {
+	return syntaxErrorAt(p.s, p.i, format, args...)
+}
+
+// comments skips whitespace and returns the comments passed.
+func (p *jsoncParser) comments() []string {
+	var out []string
+	for p.i < len(p.s) {
+		switch c := p.s[p.i]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			p.i++
+		case c == '/':
+			text, ok := p.comment()
+			if !ok {
+				return out
+			}
+			out = append(out, text)
+		default:
+			if n := p.space5(); n > 0 {
+				p.i += n
+				continue
+			}
+			return out
+		}
+	}
+	return out
+}
+
+// trailing returns the comments before the end of the current line.
+func (p *jsoncParser) trailing() []string {
+	var out []string
+	for p.i < len(p.s) {
+		switch c := p.s[p.i]; {
+		case c == ' ' || c == '\t' || c == '\r':
+			p.i++
+		case c == '/':
+			text, ok := p.comment()
+			if !ok {
+				return out
+			}
+			out = append(out, text)
+		default:
+			if n := p.space5(); n > 0 {
+				p.i += n
+				continue
+			}
+			return out
+		}
+	}
+	return out
+}
+
+// comment reads the comment at p.i. Continuation lines of block comments
+// lose the indentation of the comment's first line, which MarshalJSONCompat
+// restores at the new position.
+func (p *jsoncParser) comment() (string, bool) {
+	start := p.i
+	if p.i+1 >= len(p.s) {
+		return "", false
+	}
+	switch p.s[p.i+1] {
+	case '/':
+		end := bytes.IndexByte(p.s[p.i:], '\n')
+		if end < 0 {
+			end = len(p.s) - p.i
+		}
+		p.i += end
+		return strings.TrimRight(string(p.s[start:p.i]), " \t\r"), true
+	case '*':
+		end := bytes.Index(p.s[p.i+2:], []byte("*/"))
+		if end < 0 {
+			p.err = p.errf("unterminated comment")
+			p.i = len(p.s)
+			return "", false
+		}
+		p.i += end + 4
+		col := start - (bytes.LastIndexByte(p.s[:start], '\n') + 1)
+		lines := strings.Split(string(p.s[start:p.i]), "\n")
+		for i := 1; i < len(lines); i++ {
+			l := strings.TrimRight(lines[i], " \t\r")
+			for n := 0; n < col && l != "" && (l[0] == ' ' || l[0] == '\t'); n++ {
+				l = l[1:]
+			}
+			lines[i] = l
+		}
+		return strings.Join(lines, "\n"), true
+	}
+	return "", false
+}
+
+func (p *jsoncParser) expect(c byte) error {
+	p.comments()
+	if p.i >= len(p.s) || p.s[p.i] != c {
+		return p.unexpected(strconv.QuoteRune(rune(c)))
+	}
+	p.i++
+	return nil
+}
+
+func (p *jsoncParser) unexpected(want string) error {
+	if p.err != nil {
+		return p.err
+	}
+	if p.i >= len(p.s) {
+		return p.errf("unexpected end of input, want %s", want)
+	}
+	return p.errf("unexpected %q, want %s", p.s[p.i], want)
+}
+
+// value parses the value at p.i; its comments go to scope under ptr.
+func (p *jsoncParser) value(scope commentScope, ptr string) (any, error) {
+	if p.comments(); p.err != nil {
+		return nil, p.err
+	}
+	if p.i >= len(p.s) {
+		return nil, p.unexpected("a value")
+	}
+	if p.json5 {
+		if v, ok, err := p.value5(); ok {
+			return v, err
+		}
+	}
+	switch c := p.s[p.i]; {
+	case c == '{':
+		obj, own, err := p.object()
+		if err == nil {
+			own.attach(obj)
+		}
+		return obj, err
+	case c == '[':
+		return p.array(scope, ptr)
+	case c == '"':
+		return p.str()
+	case c == '-' || c >= '0' && c <= '9':
+		start := p.i
+		for p.i < len(p.s) && strings.IndexByte("+-.eE0123456789", p.s[p.i]) >= 0 {
+			p.i++
+		}
+		lit := string(p.s[start:p.i])
+		if !json.Valid([]byte(lit)) {
+			return nil, syntaxErrorAt(p.s, start, "bad number %s", lit)
+		}
+		return json.Number(lit), nil
+	}
+	for lit, v := range map[string]any{"true": true, "false": false, "null": nil} {
+		if bytes.HasPrefix(p.s[p.i:], []byte(lit)) {
+			p.i += len(lit)
+			return v, nil
+		}
+	}
+	return nil, p.unexpected("a value")
+}
+
+func (p *jsoncParser) str() (string, error) {
+	start := p.i
+	for p.i++; p.i < len(p.s) && p.s[p.i] != '"'; p.i++ {
+		if p.s[p.i] == '\\' {
+			p.i++
+		}
+	}
+	if p.i >= len(p.s) {
+		return "", syntaxErrorAt(p.s, start, "unterminated string")
+	}
+	p.i++
+	var s string
+	if err := json.Unmarshal(p.s[start:p.i], &s); err != nil {
+		return "", syntaxErrorAt(p.s, start, "bad string: %v", err)
+	}
+	return s, nil
+}
+
+// object parses an object, returning the comments of its members; the
+// caller attaches them once it has added its own.
+func (p *jsoncParser) object() (map[string]any, commentScope, error) {
+	p.i++ // {
+	obj := map[string]any{}
+	var own commentScope
+	if p.keep {
+		own = commentScope{}
+	}
+	for more := true; ; {
+		lead := p.comments()
+		if p.i < len(p.s) && p.s[p.i] == '}' {
+			p.i++
+			own.add("", commentEnd, lead)
+			return obj, own, nil
+		}
+		if !more {
+			return nil, nil, p.unexpected(`"," or "}"`)
+		}
+		var k string
+		var err error
+		switch {
+		case p.json5:
+			k, err = p.key5()
+		case p.i < len(p.s) && p.s[p.i] == '"':
+			k, err = p.str()
+		default:
+			err = p.unexpected("a string key")
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		ptr := FormatPointer([]string{k})
+		own.add(ptr, commentBefore, lead)
+		if err := p.expect(':'); err != nil {
+			return nil, nil, err
+		}
+		if obj[k], err = p.value(own, ptr); err != nil {
+			return nil, nil, err
+		}
+		more = p.member(own, ptr)
+	}
+}
+
+func (p *jsoncParser) array(scope commentScope, ptr string) (any, error) {
+	p.i++ // [
+	arr := []any{}
+	for more := true; ; {
+		lead := p.comments()
+		if p.i < len(p.s) && p.s[p.i] == ']' {
+			p.i++
+			scope.add(ptr, commentEnd, lead)
+			return arr, nil
+		}
+		if !more {
+			return nil, p.unexpected(`"," or "]"`)
+		}
+		eptr := ptr + "/" + strconv.Itoa(len(arr))
+		scope.add(eptr, commentBefore, lead)
+		v, err := p.value(scope, eptr)
+		if err != nil {
+			return nil, err
+		}
+		arr = append(arr, v)
+		more = p.member(scope, eptr)
+	}
+}
+
+// member reads what follows a member or element up to the end of its line:
+// the comma, reporting whether there was one (another member may follow;
+// trailing commas are allowed, as in most JSONC), and trailing comments.
+func (p *jsoncParser) member(scope commentScope, ptr string) bool {
+	after := p.trailing()
+	comma := p.i < len(p.s) && p.s[p.i] == ','
+	if comma {
+		p.i++
+		after = append(after, p.trailing()...)
+	}
+	scope.add(ptr, commentAfter, after)
+	return comma
+}
+
+// commentsOf returns obj's "$comment" when it has the shape UnmarshalJSONC
+// produces.
+func commentsOf(obj map[string]any) (map[string]any, bool) {
+	c, ok := obj["$comment"].(map[string]any)
+	if !ok {
+		return nil, false
+	}
+	for ptr, e := range c {
+		em, ok := e.(map[string]any)
+		if !ok || ptr != "" && ptr[0] != '/' {
+			return nil, false
+		}
+		for field, text := range em {
+			if _, ok := text.(string); !ok || field != commentBefore && field != commentAfter && field != commentEnd {
+				return nil, false
+			}
+		}
+	}
+	return c, true
+}
+
+// hasComments reports whether v, in its toJSONCompat form, holds comments.
+func hasComments(v any) bool {
+	switch x := v.(type) {
+	case map[string]any:
+		if _, ok := commentsOf(x); ok {
+			return true
+		}
+		for _, it := range x {
+			if hasComments(it) {
+				return true
+			}
+		}
+	case []any:
+		for _, it := range x {
+			if hasComments(it) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// jsoncWriter writes indented JSON as json.MarshalIndent does, with the
+// comments of "$comment" members in place.
+type jsoncWriter struct{ buf bytes.Buffer }
+
+func commentText(scope map[string]any, ptr, field string) string {
+	e, _ := scope[ptr].(map[string]any)
+	s, _ := e[field].(string)
+	return s
+}
+
+// lines writes text on lines of its own at indent.
+func (w *jsoncWriter) lines(text, indent string) {
+	if text == "" {
+		return
+	}
+	w.buf.WriteString(indent + strings.ReplaceAll(text, "\n", "\n"+indent) + "\n")
+}
+
+// value writes v; scope and ptr locate its comments.
+func (w *jsoncWriter) value(v any, indent string, scope map[string]any, ptr string) error {
+	switch x := v.(type) {
+	case map[string]any:
+		own, ok := commentsOf(x)
+		keys := make([]string, 0, len(x))
+		for k := range x {
+			if k != "$comment" || !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		items := make([]func(string) error, len(keys))
+		ptrs := make([]string, len(keys))
+		for i, k := range keys {
+			kb, err := json.Marshal(k)
+			if err != nil {
+				return err
+			}
+			ptrs[i] = FormatPointer([]string{k})
+			val, p := x[k], ptrs[i]
+			items[i] = func(in string) error {
+				w.buf.Write(kb)
+				w.buf.WriteString(": ")
+				return w.value(val, in, own, p)
+			}
+		}
+		return w.block("{", "}", indent, own, ptrs, items, commentText(own, "", commentEnd))
+	case []any:
+		items := make([]func(string) error, len(x))
+		ptrs := make([]string, len(x))
+		for i, it := range x {
+			ptrs[i] = ptr + "/" + strconv.Itoa(i)
+			it, p := it, ptrs[i]
+			items[i] = func(in string) error { return w.value(it, in, scope, p) }
+		}
+		return w.block("[", "]", indent, scope, ptrs, items, commentText(scope, ptr, commentEnd))
+	}
+	b, err := json.MarshalIndent(v, indent, "  ")
+	if err != nil {
+		return err
+	}
+	w.buf.Write(b)
+	return nil
+}
+
+func (w *jsoncWriter) block(open, close, indent string, scope map[string]any, ptrs []string, items []func(string) error, end string) error {
+	if len(items) == 0 && end == "" {
+		w.buf.WriteString(open + close)
+		return nil
+	}
+	in := indent + "  "
+	w.buf.WriteString(open + "\n")
+	for i, item := range items {
+		w.lines(commentText(scope, ptrs[i], commentBefore), in)
+		w.buf.WriteString(in)
+		if err := item(in); err != nil {
+			return err
+		}
+		if i < len(items)-1 {
+			w.buf.WriteByte(',')
+		}
+		if after := commentText(scope, ptrs[i], commentAfter); after != "" {
+			w.buf.WriteString(" " + after)
+		}
+		w.buf.WriteByte('\n')
+	}
+	w.lines(end, in)
+	w.buf.WriteString(indent + close)
+	return nil
+}
+
+// marshalJSONC writes v, in its toJSONCompat form, as indented JSONC.
+func marshalJSONC(v any) ([]byte, error) {
+	var w jsoncWriter
+	root, _ := v.(map[string]any)
+	var own map[string]any
+	if root != nil {
+		own, _ = commentsOf(root)
+	}
+	w.lines(commentText(own, "", commentBefore), "")
+	if err := w.value(v, "", nil, ""); err != nil {
+		return nil, err
+	}
+	if after := commentText(own, "", commentAfter); after != "" {
+		w.buf.WriteString("\n" + after)
+	}
+	return w.buf.Bytes(), nil
+}
@@ -0,0 +1,484 @@
+package jolt
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/apd/v3"
+)
+
+// Marshaler is implemented by types that can describe themselves as a JOLT
+// value (scalars, []any, map[string]any, Set, Map, ...). EncodeBinary uses
+// the returned value in place of the receiver.
+type Marshaler interface {
+	MarshalJOLT() (any, error)
+}
+
+// Unmarshaler is implemented by types that can populate themselves from a
+// decoded JOLT value. DecodeInto and Unmarshal call it instead of assigning
+// fields reflectively.
+type Unmarshaler interface {
+	UnmarshalJOLT(v any) error
+}
+
+var (
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	timeType            = reflect.TypeOf(time.Time{})
+)
+
+// field describes one encodable struct field. Names come from the `jolt`
+// tag, then the `json` tag, then the Go field name.
+type field struct {
+	name      string
+	index     []int
+	omitEmpty bool
+}
+
+var fieldCache sync.Map // reflect.Type -> []field (sorted by name)
+
+func structFields(t reflect.Type) []field {
+	if fs, ok := fieldCache.Load(t); ok {
+		return fs.([]field)
+	}
+	var fs []field
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag, ok := sf.Tag.Lookup("jolt")
+		if !ok {
+			tag = sf.Tag.Get("json")
+		}
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if sf.Anonymous && name == "" {
+			ft := sf.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				for _, inner := range structFields(ft) {
+					inner.index = append([]int{i}, inner.index...)
+					fs = append(fs, inner)
+				}
+				continue
+			}
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		fs = append(fs, field{name: name, index: []int{i}, omitEmpty: strings.Contains(","+opts+",", ",omitempty,")})
+	}
+	sort.SliceStable(fs, func(i, j int) bool { return fs[i].name < fs[j].name })
+	fieldCache.Store(t, fs)
+	return fs
+}
+
+// fieldByIndex is reflect.Value.FieldByIndex that reports nil embedded pointers
+// instead of panicking.
+func fieldByIndex(rv reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && rv.Kind() == reflect.Pointer {
+			if rv.IsNil() {
+				return reflect.Value{}, false
+			}
+			rv = rv.Elem()
+		}
+		rv = rv.Field(x)
+	}
+	return rv, true
+}
+
+func isNilValue(v any) bool {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Interface:
+		return rv.IsNil()
+	}
+	return false
+}
+
+// encodeReflect handles Go values that are not JOLT values themselves: structs,
+// pointers, typed slices and maps, and named scalar types. Types with their own
+// MarshalJSON, and anything else it cannot describe, go through encoding/json.
+func encodeReflect(w io.Writer, x any, depth int) error {
+	if isNilValue(x) {
+		return encodeAny(w, nil, depth)
+	}
+	// time.Time is a TextMarshaler too, but it travels as a Timestamp.
+	switch t := x.(type) {
+	case time.Time:
+		return encodeAny(w, TS(t), depth)
+	case *time.Time:
+		return encodeAny(w, TS(*t), depth)
+	}
+	if tm, ok := x.(encoding.TextMarshaler); ok {
+		txt, err := tm.MarshalText()
+		if err != nil {
+			return err
+		}
+		return encodeAny(w, string(txt), depth)
+	}
+	if _, ok := x.(json.Marshaler); ok {
+		return encodeViaJSON(w, x, depth)
+	}
+	rv := reflect.ValueOf(x)
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if rv.IsNil() {
+			return encodeAny(w, nil, depth)
+		}
+		return encodeAny(w, rv.Elem().Interface(), depth)
+	case reflect.Struct:
+		type kv struct {
+			k string
+			v any
+		}
+		fs := structFields(rv.Type())
+		kvs := make([]kv, 0, len(fs))
+		for _, f := range fs {
+			fv, ok := fieldByIndex(rv, f.index)
+			if !ok || (f.omitEmpty && fv.IsZero()) {
+				continue
+			}
+			kvs = append(kvs, kv{f.name, fv.Interface()})
+		}
+		if _, err := w.Write([]byte{tagObj}); err != nil {
+			return err
+		}
+		if err := putUvarint(w, uint64(len(kvs))); err != nil {
+			return err
+		}
+		for _, p := range kvs {
+			if err := writeString(w, p.k); err != nil {
+				return err
+			}
+			if err := encodeAny(w, p.v, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return encodeAny(w, nil, depth)
+		}
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, rv.Len())
+			reflect.Copy(reflect.ValueOf(b), rv)
+			return encodeAny(w, Binary(b), depth)
+		}
+		arr := make([]any, rv.Len())
+		for i := range arr {
+			arr[i] = rv.Index(i).Interface()
+		}
+		return encodeAny(w, arr, depth)
+	case reflect.Map:
+		if rv.IsNil() {
+			return encodeAny(w, nil, depth)
+		}
+		if rv.Type().Key().Kind() == reflect.String {
+			obj := make(map[string]any, rv.Len())
+			for it := rv.MapRange(); it.Next(); {
+				obj[it.Key().String()] = it.Value().Interface()
+			}
+			return encodeAny(w, obj, depth)
+		}
+		m := make(Map, rv.Len())
+		for it := rv.MapRange(); it.Next(); {
+			m[it.Key().Interface()] = it.Value().Interface()
+		}
+		return encodeAny(w, m, depth)
+	case reflect.Bool:
+		return encodeAny(w, rv.Bool(), depth)
+	case reflect.String:
+		return encodeAny(w, rv.String(), depth)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return encodeAny(w, rv.Int(), depth)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return encodeAny(w, rv.Uint(), depth)
+	case reflect.Float32, reflect.Float64:
+		return encodeAny(w, rv.Float(), depth)
+	}
+	return encodeViaJSON(w, x, depth)
+}
+
+func encodeViaJSON(w io.Writer, x any, depth int) error {
+	blob, err := json.Marshal(x)
+	if err != nil {
+		return fmt.Errorf("unsupported type %T", x)
+	}
+	var g any
+	if err := json.Unmarshal(blob, &g); err != nil {
+		return err
+	}
+	return encodeAny(w, g, depth+1)
+}
+
+// Unmarshal decodes JOLT-B into the value pointed to by out.
+func Unmarshal(b []byte, out any) error {
+	v, err := DecodeBinary(b)
+	if err != nil {
+		return err
+	}
+	return DecodeInto(v, out)
+}
+
+// DecodeInto stores a decoded JOLT value (as returned by DecodeBinary) into
+// the Go value pointed to by out. Struct fields are matched by the same names
+// EncodeBinary writes; Unmarshaler and encoding.TextUnmarshaler implementations
+// take precedence over reflective assignment.
+func DecodeInto(v any, out any) error {
+	rv := reflect.ValueOf(out)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("jolt: DecodeInto needs a non-nil pointer, got %T", out)
+	}
+	return assign(rv.Elem(), v, "$")
+}
+
+func assign(dst reflect.Value, v any, path string) error {
+	if dst.Kind() == reflect.Pointer {
+		if v == nil {
+			dst.Set(reflect.Zero(dst.Type()))
+			return nil
+		}
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+		return assign(dst.Elem(), v, path)
+	}
+	if dst.CanAddr() {
+		if u, ok := dst.Addr().Interface().(Unmarshaler); ok {
+			if err := u.UnmarshalJOLT(v); err != nil {
+				return fmt.Errorf("jolt: %s: %w", path, err)
+			}
+			return nil
+		}
+	}
+	if v == nil {
+		dst.Set(reflect.Zero(dst.Type()))
+		return nil
+	}
+	src := reflect.ValueOf(v)
+	if src.Type().AssignableTo(dst.Type()) {
+		dst.Set(src)
+		return nil
+	}
+	if s, ok := v.(string); ok && dst.CanAddr() && dst.Addr().Type().Implements(textUnmarshalerType) {
+		if err := dst.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s)); err != nil {
+			return fmt.Errorf("jolt: %s: %w", path, err)
+		}
+		return nil
+	}
+	mismatch := func() error {
+		return fmt.Errorf("jolt: cannot decode %T into %s at %s", v, dst.Type(), path)
+	}
+	switch dst.Kind() {
+	case reflect.Bool:
+		b, ok := v.(bool)
+		if !ok {
+			return mismatch()
+		}
+		dst.SetBool(b)
+	case reflect.String:
+		s, ok := v.(string)
+		if !ok {
+			return mismatch()
+		}
+		dst.SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := integerOf(v)
+		if err != nil {
+			return mismatch()
+		}
+		if !n.IsInt64() || dst.OverflowInt(n.Int64()) {
+			return fmt.Errorf("jolt: %s overflows %s at %s", n, dst.Type(), path)
+		}
+		dst.SetInt(n.Int64())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := integerOf(v)
+		if err != nil {
+			return mismatch()
+		}
+		if n.Sign() < 0 || !n.IsUint64() || dst.OverflowUint(n.Uint64()) {
+			return fmt.Errorf("jolt: %s overflows %s at %s", n, dst.Type(), path)
+		}
+		dst.SetUint(n.Uint64())
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		switch x := v.(type) {
+		case Int:
+			f, _ = new(big.Float).SetInt(x.V).Float64()
+		case Decimal:
+			var err error
+			if f, err = x.D.Float64(); err != nil {
+				return mismatch()
+			}
+		case float64:
+			f = x
+		default:
+			return mismatch()
+		}
+		if dst.OverflowFloat(f) {
+			return fmt.Errorf("jolt: %v overflows %s at %s", f, dst.Type(), path)
+		}
+		dst.SetFloat(f)
+	case reflect.Struct:
+		if dst.Type() == timeType {
+			ts, ok := v.(Timestamp)
+			if !ok {
+				return mismatch()
+			}
+			t, err := time.Parse(time.RFC3339Nano, ts.RFC3339)
+			if err != nil {
+				return fmt.Errorf("jolt: %s: %w", path, err)
+			}
+			dst.Set(reflect.ValueOf(t))
+			return nil
+		}
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return mismatch()
+		}
+		for _, f := range structFields(dst.Type()) {
+			fv, present := obj[f.name]
+			if !present {
+				continue
+			}
+			fd, err := settableField(dst, f.index)
+			if err != nil {
+				return err
+			}
+			if err := assign(fd, fv, path+"."+f.name); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice:
+		if dst.Type().Elem().Kind() == reflect.Uint8 {
+			b, ok := v.(Binary)
+			if !ok {
+				return mismatch()
+			}
+			dst.SetBytes(append([]byte(nil), b...))
+			return nil
+		}
+		var items []any
+		switch x := v.(type) {
+		case []any:
+			items = x
+		case Set:
+			items = x
+		default:
+			return mismatch()
+		}
+		out := reflect.MakeSlice(dst.Type(), len(items), len(items))
+		for i, it := range items {
+			if err := assign(out.Index(i), it, path+"["+strconv.Itoa(i)+"]"); err != nil {
+				return err
+			}
+		}
+		dst.Set(out)
+	case reflect.Array:
+		items, ok := v.([]any)
+		if !ok || len(items) != dst.Len() {
+			return mismatch()
+		}
+		for i, it := range items {
+			if err := assign(dst.Index(i), it, path+"["+strconv.Itoa(i)+"]"); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		out := reflect.MakeMap(dst.Type())
+		kt, vt := dst.Type().Key(), dst.Type().Elem()
+		put := func(k, val any, p string) error {
+			kv := reflect.New(kt).Elem()
+			if err := assign(kv, k, p); err != nil {
+				return err
+			}
+			vv := reflect.New(vt).Elem()
+			if err := assign(vv, val, p); err != nil {
+				return err
+			}
+			out.SetMapIndex(kv, vv)
+			return nil
+		}
+		switch x := v.(type) {
+		case map[string]any:
+			for k, val := range x {
+				if err := put(k, val, path+"."+k); err != nil {
+					return err
+				}
+			}
+		case Map:
+			for k, val := range x {
+				if err := put(k, val, fmt.Sprintf("%s[%v]", path, k)); err != nil {
+					return err
+				}
+			}
+		default:
+			return mismatch()
+		}
+		dst.Set(out)
+	default:
+		return mismatch()
+	}
+	return nil
+}
+
+// settableField walks index, allocating nil embedded struct pointers.
+func settableField(rv reflect.Value, index []int) (reflect.Value, error) {
+	for i, x := range index {
+		if i > 0 && rv.Kind() == reflect.Pointer {
+			if rv.IsNil() {
+				if !rv.CanSet() {
+					return reflect.Value{}, fmt.Errorf("jolt: cannot set embedded pointer %s", rv.Type())
+				}
+				rv.Set(reflect.New(rv.Type().Elem()))
+			}
+			rv = rv.Elem()
+		}
+		rv = rv.Field(x)
+	}
+	return rv, nil
+}
+
+// integerOf returns the exact integer carried by an Int, an integral Decimal
+// or an integral float64.
+func integerOf(v any) (*big.Int, error) {
+	switch x := v.(type) {
+	case Int:
+		return x.V, nil
+	case Decimal:
+		var r apd.Decimal
+		r.Reduce(&x.D)
+		if r.Exponent < 0 {
+			return nil, fmt.Errorf("jolt: %s is not an integer", x)
+		}
+		n := new(big.Int).SetBytes(r.Coeff.Bytes())
+		n.Mul(n, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(r.Exponent)), nil))
+		if r.Negative {
+			n.Neg(n)
+		}
+		return n, nil
+	case float64:
+		if math.Trunc(x) != x {
+			return nil, fmt.Errorf("jolt: %v is not an integer", x)
+		}
+		n, _ := big.NewFloat(x).Int(nil)
+		return n, nil
+	}
+	return nil, fmt.Errorf("jolt: %T is not an integer", v)
+}
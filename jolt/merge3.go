@@ -0,0 +1,259 @@
+package jolt
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// MergeOptions controls Merge3.
+type MergeOptions struct {
+	// IDFields names the fields that identify array elements, tried in
+	// order (e.g. "$id", "sku"). Arrays whose elements are all objects with
+	// a distinct identity are merged element by element; any other array is
+	// merged as a single value.
+	IDFields []string
+}
+
+// Conflict is a position both sides changed in different ways. Path is a
+// JSON pointer into the merged document (see ParsePointer); a side with no
+// value at Path is reported as nil.
+type Conflict struct {
+	Path               string
+	Base, Ours, Theirs any
+}
+
+func (c Conflict) String() string {
+	return fmt.Sprintf("%s: base %v, ours %v, theirs %v", c.Path, c.Base, c.Ours, c.Theirs)
+}
+
+// Merge3 merges the edits ours and theirs each made to base. A side that left
+// a value unchanged takes the other's edit. When both changed it, objects and
+// Maps are merged by key, Sets by membership (a member either side removed
+// is dropped, one either side added is kept), envelopes by $meta and $body,
+// and arrays by identity when opts.IDFields allows. Anything else is a
+// conflict; the merged document keeps ours there. Comparisons use Equal, so
+// 1.5 and 1.50 differ. The inputs are not modified.
+func Merge3(base, ours, theirs any, opts MergeOptions) (any, []Conflict) {
+	m := &merger{opts: opts}
+	out := m.merge(nil, base, ours, theirs)
+	if isMissing(out) {
+		return nil, m.conflicts
+	}
+	return out, m.conflicts
+}
+
+// missing stands for an absent key or element, which is not the same as null.
+type missing struct{}
+
+func same(a, b any) bool {
+	if am, bm := isMissing(a), isMissing(b); am || bm {
+		return am && bm
+	}
+	return Equal(a, b)
+}
+
+type merger struct {
+	opts      MergeOptions
+	conflicts []Conflict
+}
+
+func (m *merger) merge(path []string, b, o, t any) any {
+	switch {
+	case same(o, t), same(b, t):
+		return cloneValue(o)
+	case same(b, o):
+		return cloneValue(t)
+	}
+	at := func(seg string) []string { return append(append([]string(nil), path...), seg) }
+	switch x := o.(type) {
+	case map[string]any:
+		if y, ok := t.(map[string]any); ok {
+			bm, _ := b.(map[string]any)
+			out := map[string]any{}
+			for _, k := range unionStringKeys(bm, x, y) {
+				if v := m.merge(at(k), side(bm, k), side(x, k), side(y, k)); !isMissing(v) {
+					out[k] = v
+				}
+			}
+			return out
+		}
+	case Map:
+		if y, ok := t.(Map); ok {
+			bm, _ := b.(Map)
+			out := Map{}
+			for _, k := range unionMapKeys(bm, x, y) {
+				seg, _ := KeySegment(k)
+				if v := m.merge(at(seg), mapSide(bm, k), mapSide(x, k), mapSide(y, k)); !isMissing(v) {
+					out[k] = v
+				}
+			}
+			return out
+		}
+	case Set:
+		if y, ok := t.(Set); ok {
+			bs, _ := b.(Set)
+			out := Set{}
+			for _, s := range []Set{x, y} {
+				for _, v := range s {
+					keep := (setHas(x, v) && setHas(y, v)) || !setHas(bs, v)
+					if keep && !setHas(out, v) {
+						out = append(out, cloneValue(v))
+					}
+				}
+			}
+			return out
+		}
+	case Envelope:
+		if y, ok := t.(Envelope); ok {
+			var bmeta map[string]any
+			var bbody any = missing{}
+			if be, ok := b.(Envelope); ok {
+				bmeta, bbody = be.Meta.ToMap(), be.Body
+			}
+			out := Envelope{Meta: cloneMeta(x.Meta)}
+			mm, _ := m.merge(at("$meta"), bmeta, x.Meta.ToMap(), y.Meta.ToMap()).(map[string]any)
+			if meta, err := MetaFromMap(mm); err == nil {
+				out.Meta = meta
+			}
+			if body := m.merge(at("$body"), bbody, x.Body, y.Body); !isMissing(body) {
+				out.Body = body
+			}
+			return out
+		}
+	case []any:
+		if y, ok := t.([]any); ok {
+			if out, ok := m.mergeArray(path, b, x, y); ok {
+				return out
+			}
+		}
+	}
+	m.conflicts = append(m.conflicts, Conflict{Path: FormatPointer(path), Base: reported(b), Ours: reported(o), Theirs: reported(t)})
+	return cloneValue(o)
+}
+
+// mergeArray merges arrays element by element when every element has an
+// identity; ok is false otherwise. The result follows ours' order, with
+// elements only theirs added appended in theirs' order.
+func (m *merger) mergeArray(path []string, b any, o, t []any) (out []any, ok bool) {
+	ba, _ := b.([]any)
+	bi, ok1 := m.index(ba)
+	oi, ok2 := m.index(o)
+	ti, ok3 := m.index(t)
+	if !ok1 || !ok2 || !ok3 {
+		return nil, false
+	}
+	var order []string
+	seen := map[string]bool{}
+	for _, arr := range [][]any{o, t, ba} {
+		for _, v := range arr {
+			if id := m.id(v); !seen[id] {
+				seen[id] = true
+				order = append(order, id)
+			}
+		}
+	}
+	out = []any{}
+	for _, id := range order {
+		seg := append(append([]string(nil), path...), strconv.Itoa(len(out)))
+		if v := m.merge(seg, arraySide(ba, bi, id), arraySide(o, oi, id), arraySide(t, ti, id)); !isMissing(v) {
+			out = append(out, v)
+		}
+	}
+	return out, true
+}
+
+// index maps element identities to positions, failing if an element has no
+// identity or two share one.
+func (m *merger) index(arr []any) (map[string]int, bool) {
+	if len(m.opts.IDFields) == 0 {
+		return nil, false
+	}
+	idx := make(map[string]int, len(arr))
+	for i, v := range arr {
+		id := m.id(v)
+		if id == "" {
+			return nil, false
+		}
+		if _, dup := idx[id]; dup {
+			return nil, false
+		}
+		idx[id] = i
+	}
+	return idx, true
+}
+
+// id is the identity of an array element: the first IDFields field it has,
+// named and canonically encoded so "sku" "A" and "$id" "A" stay distinct.
+func (m *merger) id(v any) string {
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return ""
+	}
+	for _, f := range m.opts.IDFields {
+		if fv, ok := obj[f]; ok {
+			b, err := EncodeBinary(fv)
+			if err != nil {
+				return ""
+			}
+			return f + "\x00" + string(b)
+		}
+	}
+	return ""
+}
+
+// side, mapSide and arraySide return the value one side has at a key or
+// identity, or missing.
+func arraySide(arr []any, idx map[string]int, id string) any {
+	if i, ok := idx[id]; ok {
+		return arr[i]
+	}
+	return missing{}
+}
+
+func side(m map[string]any, k string) any {
+	if v, ok := m[k]; ok {
+		return v
+	}
+	return missing{}
+}
+
+func mapSide(m Map, k any) any {
+	if mk, ok := mapFind(m, k); ok {
+		return m[mk]
+	}
+	return missing{}
+}
+
+func isMissing(v any) bool {
+	_, ok := v.(missing)
+	return ok
+}
+
+func reported(v any) any {
+	if isMissing(v) {
+		return nil
+	}
+	return cloneValue(v)
+}
+
+func unionStringKeys(ms ...map[string]any) []string {
+	all := map[string]any{}
+	for _, m := range ms {
+		for k := range m {
+			all[k] = nil
+		}
+	}
+	return sortedStringKeys(all)
+}
+
+func unionMapKeys(ms ...Map) []any {
+	var keys []any
+	for _, m := range ms {
+		for k := range m {
+			if !setHas(keys, k) {
+				keys = append(keys, k)
+			}
+		}
+	}
+	return sortedCanonical(keys)
+}
@@ -0,0 +1,171 @@
+package jolt
+
+import "fmt"
+
+// Set markers in a merge patch: {"$add": [...], "$remove": [...]} adds and
+// removes members of the Set at that position instead of replacing it.
+const (
+	MergeAdd    = "$add"
+	MergeRemove = "$remove"
+)
+
+// MergePatch applies an RFC 7396 merge patch to a copy of target, extended
+// for JOLT values:
+//
+//   - an object patch merges key by key into an object target (any other
+//     target is first replaced by an empty object); null deletes a key;
+//   - a Map patch merges into a Map target the same way, matching keys by
+//     canonical encoding;
+//   - an object holding only $add and/or $remove lists unions members into,
+//     or subtracts them from, a Set target;
+//   - an envelope target takes patches for "$meta" and "$body";
+//   - everything else, including Decimal and Int, replaces the target
+//     exactly.
+func MergePatch(target, patch any) (any, error) {
+	return mergePatch(cloneValue(target), patch, "$")
+}
+
+func mergePatch(target, patch any, path string) (any, error) {
+	switch p := patch.(type) {
+	case map[string]any:
+		if isSetMarker(p) {
+			return mergeSet(target, p, path)
+		}
+		if env, ok := target.(Envelope); ok {
+			return mergeEnvelope(env, p, path)
+		}
+		obj, ok := target.(map[string]any)
+		if !ok {
+			obj = map[string]any{}
+		}
+		for _, k := range sortedStringKeys(p) {
+			if p[k] == nil {
+				delete(obj, k)
+				continue
+			}
+			v, err := mergePatch(obj[k], p[k], path+"."+k)
+			if err != nil {
+				return nil, err
+			}
+			obj[k] = v
+		}
+		return obj, nil
+	case Map:
+		m, ok := target.(Map)
+		if !ok {
+			m = Map{}
+		}
+		for _, k := range sortedCanonical(mapKeys(p)) {
+			mk, found := mapFind(m, k)
+			if p[k] == nil {
+				if found {
+					delete(m, mk)
+				}
+				continue
+			}
+			var cur any
+			if found {
+				cur = m[mk]
+				delete(m, mk)
+			}
+			v, err := mergePatch(cur, p[k], fmt.Sprintf("%s[%v]", path, k))
+			if err != nil {
+				return nil, err
+			}
+			m[k] = v
+		}
+		return m, nil
+	case Envelope:
+		meta := p.Meta.ToMap()
+		return mergePatch(target, map[string]any{"$meta": meta, "$body": p.Body}, path)
+	}
+	return cloneValue(patch), nil
+}
+
+func isSetMarker(p map[string]any) bool {
+	if len(p) == 0 {
+		return false
+	}
+	for k := range p {
+		if k != MergeAdd && k != MergeRemove {
+			return false
+		}
+	}
+	return true
+}
+
+func mergeSet(target any, p map[string]any, path string) (any, error) {
+	var s Set
+	switch x := target.(type) {
+	case nil:
+		s = Set{}
+	case Set:
+		s = x
+	default:
+		return nil, fmt.Errorf("jolt: merge patch at %s: $add/$remove need a set, got %T", path, target)
+	}
+	list := func(k string) ([]any, error) {
+		switch v := p[k].(type) {
+		case nil:
+			return nil, nil
+		case []any:
+			return v, nil
+		case Set:
+			return v, nil
+		}
+		return nil, fmt.Errorf("jolt: merge patch at %s: %s must be a list, got %T", path, k, p[k])
+	}
+	remove, err := list(MergeRemove)
+	if err != nil {
+		return nil, err
+	}
+	add, err := list(MergeAdd)
+	if err != nil {
+		return nil, err
+	}
+	out := s[:0]
+	for _, m := range s {
+		if !setHas(remove, m) {
+			out = append(out, m)
+		}
+	}
+	for _, m := range add {
+		if !setHas(out, m) {
+			out = append(out, cloneValue(m))
+		}
+	}
+	return out, nil
+}
+
+func mergeEnvelope(env Envelope, p map[string]any, path string) (any, error) {
+	for k := range p {
+		if k != "$meta" && k != "$body" {
+			return nil, fmt.Errorf("jolt: merge patch at %s: envelopes have only $meta and $body, got %q", path, k)
+		}
+	}
+	if mp, ok := p["$meta"]; ok {
+		mm, err := mergePatch(env.Meta.ToMap(), mp, path+".$meta")
+		if err != nil {
+			return nil, err
+		}
+		obj, ok := mm.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("jolt: merge patch at %s: $meta must stay an object", path)
+		}
+		if env.Meta, err = MetaFromMap(obj); err != nil {
+			return nil, err
+		}
+	}
+	if bp, ok := p["$body"]; ok {
+		if bp == nil {
+			env.Body = nil
+		} else {
+			body, err := mergePatch(env.Body, bp, path+".$body")
+			if err != nil {
+				return nil, err
+			}
+			env.Body = body
+		}
+	}
+	return env, nil
+}
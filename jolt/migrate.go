@@ -0,0 +1,342 @@
+package jolt
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/cockroachdb/apd/v3"
+)
+
+var ErrNoMigrationPath = errors.New("jolt: no migration path")
+
+// Migration step operations.
+const (
+	MigrateRename  = "rename"  // move Path to the sibling field To
+	MigrateDefault = "default" // set Path to Value when absent
+	MigrateRemove  = "remove"  // delete Path
+	MigrateToDec   = "toDec"   // turn the Int at Path into a Decimal, shifted by Scale places
+	MigrateSplit   = "split"   // split the string at Path on Sep into the sibling fields Into
+	MigrateFunc    = "func"    // call Func with the whole body
+)
+
+// MigrationStep is one transform applied to an envelope body. Paths are
+// dot-separated field names; a "[]" suffix visits every element of an array
+// or set, so "lines[].price" addresses the price of each line.
+type MigrationStep struct {
+	Op    string   `jolt:"op"`
+	Path  string   `jolt:"path"`
+	To    string   `jolt:"to,omitempty"`
+	Value any      `jolt:"value,omitempty"`
+	Scale int      `jolt:"scale,omitempty"`
+	Sep   string   `jolt:"sep,omitempty"`
+	Into  []string `jolt:"into,omitempty"`
+	// Func is only available to migrations declared in Go.
+	Func func(body any) (any, error) `jolt:"-"`
+}
+
+// Migration rewrites bodies of Type at version From into version To.
+type Migration struct {
+	Type  string          `jolt:"type"`
+	From  string          `jolt:"from"`
+	To    string          `jolt:"to"`
+	Steps []MigrationStep `jolt:"steps"`
+}
+
+// ParseMigrations reads migrations declared as JSONC: either one migration
+// object or an array of them.
+//
+//	{ "type": "urn:jolt:example/Order", "from": "2.0.0", "to": "2.1.0",
+//	  "steps": [ { "op": "rename", "path": "total", "to": "price" },
+//	             { "op": "toDec", "path": "lines[].cents", "scale": 2 } ] }
+func ParseMigrations(data []byte) ([]Migration, error) {
+	v, err := UnmarshalJSONTyped(data)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := v.([]any); !ok {
+		v = []any{v}
+	}
+	var ms []Migration
+	if err := DecodeInto(v, &ms); err != nil {
+		return nil, err
+	}
+	for _, m := range ms {
+		if err := m.check(); err != nil {
+			return nil, err
+		}
+	}
+	return ms, nil
+}
+
+func (m Migration) check() error {
+	if m.Type == "" {
+		return errors.New("jolt: migration needs a type")
+	}
+	for _, v := range []string{m.From, m.To} {
+		if _, err := parseSemver(v); err != nil {
+			return fmt.Errorf("jolt: migration %s: %w", m.Type, err)
+		}
+	}
+	for i, st := range m.Steps {
+		var bad string
+		switch st.Op {
+		case MigrateRename:
+			if st.Path == "" || st.To == "" || strings.Contains(st.To, ".") {
+				bad = "needs path and a sibling field name in to"
+			}
+		case MigrateDefault, MigrateRemove, MigrateToDec:
+			if st.Path == "" {
+				bad = "needs a path"
+			}
+		case MigrateSplit:
+			if st.Path == "" || len(st.Into) == 0 {
+				bad = "needs path and into"
+			}
+		case MigrateFunc:
+			if st.Func == nil {
+				bad = "needs Func"
+			}
+		default:
+			bad = fmt.Sprintf("unknown op %q", st.Op)
+		}
+		if bad != "" {
+			return fmt.Errorf("jolt: migration %s %s->%s step %d: %s", m.Type, m.From, m.To, i, bad)
+		}
+	}
+	return nil
+}
+
+// MigrationRegistry holds migrations and finds chains between versions.
+type MigrationRegistry struct {
+	mu    sync.RWMutex
+	edges map[string]map[string][]Migration // type -> from -> migrations
+}
+
+func NewMigrationRegistry() *MigrationRegistry {
+	return &MigrationRegistry{edges: map[string]map[string][]Migration{}}
+}
+
+// DefaultMigrations backs RegisterMigration and Migrate.
+var DefaultMigrations = NewMigrationRegistry()
+
+func RegisterMigration(m Migration) error { return DefaultMigrations.Register(m) }
+
+func Migrate(env Envelope, targetVersion string) (Envelope, error) {
+	return DefaultMigrations.Migrate(env, targetVersion)
+}
+
+func (r *MigrationRegistry) Register(m Migration) error {
+	if err := m.check(); err != nil {
+		return err
+	}
+	from, _ := parseSemver(m.From)
+	to, _ := parseSemver(m.To)
+	m.From, m.To = from.String(), to.String()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.edges == nil {
+		r.edges = map[string]map[string][]Migration{}
+	}
+	byFrom := r.edges[m.Type]
+	if byFrom == nil {
+		byFrom = map[string][]Migration{}
+		r.edges[m.Type] = byFrom
+	}
+	for _, e := range byFrom[m.From] {
+		if e.To == m.To {
+			return fmt.Errorf("jolt: migration %s %s->%s already registered", m.Type, m.From, m.To)
+		}
+	}
+	byFrom[m.From] = append(byFrom[m.From], m)
+	return nil
+}
+
+// Path returns the shortest chain of migrations from one version to another.
+func (r *MigrationRegistry) Path(typeURN, from, to string) ([]Migration, error) {
+	fv, err := parseSemver(from)
+	if err != nil {
+		return nil, err
+	}
+	tv, err := parseSemver(to)
+	if err != nil {
+		return nil, err
+	}
+	start, goal := fv.String(), tv.String()
+	if start == goal {
+		return nil, nil
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	byFrom := r.edges[typeURN]
+	prev := map[string]Migration{}
+	seen := map[string]bool{start: true}
+	queue := []string{start}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, m := range byFrom[cur] {
+			if seen[m.To] {
+				continue
+			}
+			seen[m.To] = true
+			prev[m.To] = m
+			if m.To == goal {
+				var chain []Migration
+				for v := goal; v != start; v = prev[v].From {
+					chain = append([]Migration{prev[v]}, chain...)
+				}
+				return chain, nil
+			}
+			queue = append(queue, m.To)
+		}
+	}
+	return nil, fmt.Errorf("%w: %s %s -> %s", ErrNoMigrationPath, typeURN, from, to)
+}
+
+// Migrate returns a copy of env with its body rewritten to targetVersion.
+// Meta.Version is set in canonical form ("v2.1" gives "2.1.0"). The input
+// envelope is not modified.
+func (r *MigrationRegistry) Migrate(env Envelope, targetVersion string) (Envelope, error) {
+	chain, err := r.Path(env.Meta.Type, env.Meta.Version, targetVersion)
+	if err != nil {
+		return Envelope{}, err
+	}
+	out := cloneValue(env).(Envelope)
+	for _, m := range chain {
+		for i, st := range m.Steps {
+			body, err := st.apply(out.Body)
+			if err != nil {
+				return Envelope{}, fmt.Errorf("jolt: migration %s %s->%s step %d (%s %s): %w", m.Type, m.From, m.To, i, st.Op, st.Path, err)
+			}
+			out.Body = body
+		}
+	}
+	// Path has parsed targetVersion; an empty chain still canonicalizes it.
+	tv, _ := parseSemver(targetVersion)
+	out.Meta.Version = tv.String()
+	return out, nil
+}
+
+func (st MigrationStep) apply(body any) (any, error) {
+	if st.Op == MigrateFunc {
+		return st.Func(body)
+	}
+	segs := strings.Split(st.Path, ".")
+	err := eachField(body, segs, func(parent map[string]any, key string) error {
+		cur, present := parent[key]
+		switch st.Op {
+		case MigrateRename:
+			if present {
+				delete(parent, key)
+				parent[st.To] = cur
+			}
+		case MigrateDefault:
+			if !present {
+				parent[key] = cloneValue(st.Value)
+			}
+		case MigrateRemove:
+			delete(parent, key)
+		case MigrateToDec:
+			if !present || cur == nil {
+				return nil
+			}
+			d, err := toDecimal(cur, st.Scale)
+			if err != nil {
+				return err
+			}
+			parent[key] = d
+		case MigrateSplit:
+			if !present {
+				return nil
+			}
+			s, ok := cur.(string)
+			if !ok {
+				return fmt.Errorf("split needs a string, got %T", cur)
+			}
+			parts := strings.SplitN(s, st.Sep, len(st.Into))
+			if st.Sep == "" {
+				parts = []string{s}
+			}
+			delete(parent, key)
+			for i, name := range st.Into {
+				if i < len(parts) {
+					parent[name] = parts[i]
+				} else {
+					parent[name] = ""
+				}
+			}
+		}
+		return nil
+	})
+	return body, err
+}
+
+// eachField calls fn with the object holding the last segment of segs, for
+// every object reached through "[]" fan-out. Missing intermediate objects are
+// skipped.
+func eachField(v any, segs []string, fn func(parent map[string]any, key string) error) error {
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return nil
+	}
+	seg := segs[0]
+	name, fan := strings.CutSuffix(seg, "[]")
+	if len(segs) == 1 && !fan {
+		return fn(obj, name)
+	}
+	child := obj[name]
+	if !fan {
+		return eachField(child, segs[1:], fn)
+	}
+	var items []any
+	switch x := child.(type) {
+	case []any:
+		items = x
+	case Set:
+		items = x
+	}
+	for _, it := range items {
+		if len(segs) == 1 {
+			continue
+		}
+		if err := eachField(it, segs[1:], fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// toDecimal converts a number to a Decimal; scale shifts the point left, so
+// 1999 with scale 2 becomes 19.99.
+func toDecimal(v any, scale int) (Decimal, error) {
+	var d Decimal
+	switch x := v.(type) {
+	case Decimal:
+		d = x
+	case Int:
+		d.D.Coeff.SetMathBigInt(new(big.Int).Abs(x.V))
+		d.D.Negative = x.V.Sign() < 0
+	case string:
+		var err error
+		if d, err = DecFromString(x); err != nil {
+			return Decimal{}, fmt.Errorf("%q is not a number", x)
+		}
+	default:
+		n, err := integerOf(v)
+		if err != nil {
+			return Decimal{}, fmt.Errorf("toDec needs a number, got %T", v)
+		}
+		d.D.Coeff.SetMathBigInt(new(big.Int).Abs(n))
+		d.D.Negative = n.Sign() < 0
+	}
+	if scale != 0 {
+		var out apd.Decimal
+		out.Set(&d.D)
+		out.Exponent -= int32(scale)
+		d.D = out
+	}
+	return d, nil
+}
@@ -0,0 +1,717 @@
+// Package msgpack converts JOLT values to and from MessagePack. Plain values
+// use the native formats (nil, bool, int, str, bin, array, map; floats read
+// as Decimals) and the JOLT kinds MessagePack lacks use extension types
+// numbered like their JOLT-B tags:
+//
+//	-1  Timestamp   the standard timestamp extension, when the RFC 3339 text is
+//	                canonical UTC (as time.RFC3339Nano writes it)
+//	 3  Int         beyond 64 bits: sign byte (1 = negative), big-endian magnitude
+//	 4  Decimal     its string form, e.g. "1999.95", "1E+3"
+//	 9  Timestamp   any other RFC 3339 text, kept as written
+//	10  Date        "YYYY-MM-DD"
+//	11  Time        "HH:MM:SS"
+//	12  Set         a MessagePack array
+//	13  Map         a MessagePack map, keys of any kind
+//	14  UUID        16 bytes
+//	15  Link        the reference
+//	16  Annot       the note
+//	17  Envelope    a MessagePack array [meta, body]
+//	18  extension   the JOLT-B extension payload: uvarint tag, then the value
+//
+// Objects are maps with string keys; every JOLT value round-trips exactly. A
+// native map with other keys, as other clients write, reads as a Map, and
+// FromMsgPack rejects maps with duplicate keys.
+// ToMsgPack sorts object keys, and Set members and Map entries by their
+// encoding, so equal values encode to equal bytes.
+package msgpack
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/cockroachdb/apd/v3"
+
+	"github.com/chandan-cmd-dev/jolt-go/jolt"
+)
+
+// Extension type codes.
+const (
+	ExtTimestamp int8 = -1
+	ExtInt       int8 = 0x03
+	ExtDecimal   int8 = 0x04
+	ExtTS        int8 = 0x09
+	ExtDate      int8 = 0x0A
+	ExtTime      int8 = 0x0B
+	ExtSet       int8 = 0x0C
+	ExtMap       int8 = 0x0D
+	ExtUUID      int8 = 0x0E
+	ExtLink      int8 = 0x0F
+	ExtAnnot     int8 = 0x10
+	ExtEnvelope  int8 = 0x11
+	ExtJOLT      int8 = 0x12
+)
+
+var errTruncated = errors.New("jolt/msgpack: truncated input")
+
+// ToMsgPack encodes v.
+func ToMsgPack(v any) ([]byte, error) {
+	var b bytes.Buffer
+	if err := encode(&b, v, 0); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// FromMsgPack decodes a single MessagePack object. Extension types other
+// than those above are an error.
+func FromMsgPack(data []byte) (any, error) {
+	d := &decoder{b: data}
+	v, err := d.value(0)
+	if err != nil {
+		return nil, err
+	}
+	if d.i != len(d.b) {
+		return nil, fmt.Errorf("jolt/msgpack: trailing data after value")
+	}
+	return v, nil
+}
+
+// ----- encoding -----
+
+func be16(n uint16) []byte { return binary.BigEndian.AppendUint16(nil, n) }
+func be32(n uint32) []byte { return binary.BigEndian.AppendUint32(nil, n) }
+func be64(n uint64) []byte { return binary.BigEndian.AppendUint64(nil, n) }
+
+func encodeUint(b *bytes.Buffer, n uint64) {
+	switch {
+	case n < 0x80:
+		b.WriteByte(byte(n))
+	case n <= math.MaxUint8:
+		b.Write([]byte{0xcc, byte(n)})
+	case n <= math.MaxUint16:
+		b.WriteByte(0xcd)
+		b.Write(be16(uint16(n)))
+	case n <= math.MaxUint32:
+		b.WriteByte(0xce)
+		b.Write(be32(uint32(n)))
+	default:
+		b.WriteByte(0xcf)
+		b.Write(be64(n))
+	}
+}
+
+func encodeInt(b *bytes.Buffer, n int64) {
+	switch {
+	case n >= 0:
+		encodeUint(b, uint64(n))
+	case n >= -32:
+		b.WriteByte(byte(n))
+	case n >= math.MinInt8:
+		b.Write([]byte{0xd0, byte(n)})
+	case n >= math.MinInt16:
+		b.WriteByte(0xd1)
+		b.Write(be16(uint16(n)))
+	case n >= math.MinInt32:
+		b.WriteByte(0xd2)
+		b.Write(be32(uint32(n)))
+	default:
+		b.WriteByte(0xd3)
+		b.Write(be64(uint64(n)))
+	}
+}
+
+func encodeStr(b *bytes.Buffer, s string) {
+	switch n := len(s); {
+	case n < 32:
+		b.WriteByte(0xa0 | byte(n))
+	case n <= math.MaxUint8:
+		b.Write([]byte{0xd9, byte(n)})
+	case n <= math.MaxUint16:
+		b.WriteByte(0xda)
+		b.Write(be16(uint16(n)))
+	default:
+		b.WriteByte(0xdb)
+		b.Write(be32(uint32(n)))
+	}
+	b.WriteString(s)
+}
+
+func encodeBin(b *bytes.Buffer, p []byte) {
+	switch n := len(p); {
+	case n <= math.MaxUint8:
+		b.Write([]byte{0xc4, byte(n)})
+	case n <= math.MaxUint16:
+		b.WriteByte(0xc5)
+		b.Write(be16(uint16(n)))
+	default:
+		b.WriteByte(0xc6)
+		b.Write(be32(uint32(n)))
+	}
+	b.Write(p)
+}
+
+func encodeExt(b *bytes.Buffer, typ int8, p []byte) {
+	switch n := len(p); n {
+	case 1:
+		b.WriteByte(0xd4)
+	case 2:
+		b.WriteByte(0xd5)
+	case 4:
+		b.WriteByte(0xd6)
+	case 8:
+		b.WriteByte(0xd7)
+	case 16:
+		b.WriteByte(0xd8)
+	default:
+		switch {
+		case n <= math.MaxUint8:
+			b.Write([]byte{0xc7, byte(n)})
+		case n <= math.MaxUint16:
+			b.WriteByte(0xc8)
+			b.Write(be16(uint16(n)))
+		default:
+			b.WriteByte(0xc9)
+			b.Write(be32(uint32(n)))
+		}
+	}
+	b.WriteByte(byte(typ))
+	b.Write(p)
+}
+
+func arrayHead(b *bytes.Buffer, n int) {
+	switch {
+	case n < 16:
+		b.WriteByte(0x90 | byte(n))
+	case n <= math.MaxUint16:
+		b.WriteByte(0xdc)
+		b.Write(be16(uint16(n)))
+	default:
+		b.WriteByte(0xdd)
+		b.Write(be32(uint32(n)))
+	}
+}
+
+func mapHead(b *bytes.Buffer, n int) {
+	switch {
+	case n < 16:
+		b.WriteByte(0x80 | byte(n))
+	case n <= math.MaxUint16:
+		b.WriteByte(0xde)
+		b.Write(be16(uint16(n)))
+	default:
+		b.WriteByte(0xdf)
+		b.Write(be32(uint32(n)))
+	}
+}
+
+// encodeTimestamp uses the standard extension when decoding it gives back
+// the same text, in its smallest form.
+func encodeTimestamp(b *bytes.Buffer, ts jolt.Timestamp) {
+	t, err := time.Parse(time.RFC3339Nano, ts.RFC3339)
+	if err != nil || t.UTC().Format(time.RFC3339Nano) != ts.RFC3339 {
+		encodeExt(b, ExtTS, []byte(ts.RFC3339))
+		return
+	}
+	sec, nsec := t.Unix(), uint64(t.Nanosecond())
+	switch {
+	case nsec == 0 && sec >= 0 && sec <= math.MaxUint32:
+		encodeExt(b, ExtTimestamp, be32(uint32(sec)))
+	case sec >= 0 && sec < 1<<34:
+		encodeExt(b, ExtTimestamp, be64(nsec<<34|uint64(sec)))
+	default:
+		encodeExt(b, ExtTimestamp, append(be32(uint32(nsec)), be64(uint64(sec))...))
+	}
+}
+
+func encode(b *bytes.Buffer, v any, depth int) error {
+	if depth > jolt.DefaultLimits.MaxDepth {
+		return jolt.ErrTooDeep
+	}
+	switch x := v.(type) {
+	case nil:
+		b.WriteByte(0xc0)
+	case bool:
+		if x {
+			b.WriteByte(0xc3)
+		} else {
+			b.WriteByte(0xc2)
+		}
+	case string:
+		encodeStr(b, x)
+	case jolt.Binary:
+		encodeBin(b, x)
+	case jolt.Int:
+		z := x.V
+		switch {
+		case z == nil:
+			encodeUint(b, 0)
+		case z.IsInt64():
+			encodeInt(b, z.Int64())
+		case z.IsUint64():
+			encodeUint(b, z.Uint64())
+		default:
+			sign := byte(0)
+			if z.Sign() < 0 {
+				sign = 1
+			}
+			encodeExt(b, ExtInt, append([]byte{sign}, new(big.Int).Abs(z).Bytes()...))
+		}
+	case jolt.Decimal:
+		encodeExt(b, ExtDecimal, []byte(x.String()))
+	case jolt.Timestamp:
+		encodeTimestamp(b, x)
+	case jolt.Date:
+		encodeExt(b, ExtDate, []byte(x.YYYYMMDD))
+	case jolt.Time:
+		encodeExt(b, ExtTime, []byte(x.HHMMSS))
+	case jolt.UUID:
+		encodeExt(b, ExtUUID, x[:])
+	case jolt.Link:
+		encodeExt(b, ExtLink, []byte(x.Ref))
+	case jolt.Annot:
+		encodeExt(b, ExtAnnot, []byte(x.Note))
+	case []any:
+		arrayHead(b, len(x))
+		for _, it := range x {
+			if err := encode(b, it, depth+1); err != nil {
+				return err
+			}
+		}
+	case map[string]any:
+		ks := make([]string, 0, len(x))
+		for k := range x {
+			ks = append(ks, k)
+		}
+		sort.Strings(ks)
+		mapHead(b, len(ks))
+		for _, k := range ks {
+			encodeStr(b, k)
+			if err := encode(b, x[k], depth+1); err != nil {
+				return err
+			}
+		}
+	case jolt.Set:
+		items := make([][]byte, len(x))
+		for i, it := range x {
+			var ib bytes.Buffer
+			if err := encode(&ib, it, depth+1); err != nil {
+				return err
+			}
+			items[i] = ib.Bytes()
+		}
+		var p bytes.Buffer
+		arrayHead(&p, len(items))
+		writeSorted(&p, items)
+		encodeExt(b, ExtSet, p.Bytes())
+	case jolt.Map:
+		entries := make([][]byte, 0, len(x))
+		for k, it := range x {
+			var eb bytes.Buffer
+			if err := encode(&eb, k, depth+1); err != nil {
+				return err
+			}
+			if err := encode(&eb, it, depth+1); err != nil {
+				return err
+			}
+			entries = append(entries, eb.Bytes())
+		}
+		var p bytes.Buffer
+		mapHead(&p, len(entries))
+		writeSorted(&p, entries)
+		encodeExt(b, ExtMap, p.Bytes())
+	case jolt.Envelope:
+		var p bytes.Buffer
+		arrayHead(&p, 2)
+		if err := encode(&p, x.Meta.ToMap(), depth+1); err != nil {
+			return err
+		}
+		if err := encode(&p, x.Body, depth+1); err != nil {
+			return err
+		}
+		encodeExt(b, ExtEnvelope, p.Bytes())
+	default:
+		return encodeOther(b, v, depth)
+	}
+	return nil
+}
+
+// writeSorted writes encoded items (or key/value pairs, whose keys are
+// unique) in bytewise order.
+func writeSorted(b *bytes.Buffer, items [][]byte) {
+	sort.Slice(items, func(i, j int) bool { return bytes.Compare(items[i], items[j]) < 0 })
+	for _, it := range items {
+		b.Write(it)
+	}
+}
+
+// encodeOther handles Go numbers, extension values (as their JOLT-B
+// payload), and everything else through its JOLT-B form.
+func encodeOther(b *bytes.Buffer, v any, depth int) error {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		encodeInt(b, rv.Int())
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		encodeUint(b, rv.Uint())
+		return nil
+	case reflect.Float32:
+		b.WriteByte(0xca)
+		b.Write(be32(math.Float32bits(float32(rv.Float()))))
+		return nil
+	case reflect.Float64:
+		b.WriteByte(0xcb)
+		b.Write(be64(math.Float64bits(rv.Float())))
+		return nil
+	}
+	jb, err := jolt.EncodeBinary(v)
+	if err != nil {
+		return err
+	}
+	if len(jb) > 0 && jb[0] == byte(ExtJOLT) {
+		encodeExt(b, ExtJOLT, jb[1:])
+		return nil
+	}
+	nv, err := jolt.DecodeBinary(jb)
+	if err != nil {
+		return err
+	}
+	return encode(b, nv, depth)
+}
+
+// ----- decoding -----
+
+type decoder struct {
+	b       []byte
+	i       int
+	anyKeys bool
+}
+
+func (d *decoder) take(n uint64) ([]byte, error) {
+	if n > uint64(len(d.b)-d.i) {
+		return nil, errTruncated
+	}
+	p := d.b[d.i : d.i+int(n)]
+	d.i += int(n)
+	return p, nil
+}
+
+// uint reads an n-byte big-endian unsigned integer.
+func (d *decoder) uint(n int) (uint64, error) {
+	p, err := d.take(uint64(n))
+	if err != nil {
+		return 0, err
+	}
+	var x uint64
+	for _, c := range p {
+		x = x<<8 | uint64(c)
+	}
+	return x, nil
+}
+
+func (d *decoder) value(depth int) (any, error) {
+	if depth > jolt.DefaultLimits.MaxDepth {
+		return nil, jolt.ErrTooDeep
+	}
+	if d.i >= len(d.b) {
+		return nil, errTruncated
+	}
+	start := d.i
+	c := d.b[d.i]
+	d.i++
+	switch {
+	case c <= 0x7f:
+		return jolt.BigInt(int64(c)), nil
+	case c >= 0xe0:
+		return jolt.BigInt(int64(int8(c))), nil
+	case c&0xf0 == 0x80:
+		return d.mapValue(uint64(c&0x0f), depth)
+	case c&0xf0 == 0x90:
+		return d.array(uint64(c&0x0f), depth)
+	case c&0xe0 == 0xa0:
+		return d.str(uint64(c & 0x1f))
+	}
+	switch c {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xc4, 0xc5, 0xc6:
+		n, err := d.uint(1 << (c - 0xc4))
+		if err != nil {
+			return nil, err
+		}
+		p, err := d.take(n)
+		if err != nil {
+			return nil, err
+		}
+		return jolt.Binary(append([]byte(nil), p...)), nil
+	case 0xc7, 0xc8, 0xc9:
+		n, err := d.uint(1 << (c - 0xc7))
+		if err != nil {
+			return nil, err
+		}
+		return d.ext(n, start, depth)
+	case 0xca:
+		x, err := d.uint(4)
+		if err != nil {
+			return nil, err
+		}
+		return floatDecimal(float64(math.Float32frombits(uint32(x))))
+	case 0xcb:
+		x, err := d.uint(8)
+		if err != nil {
+			return nil, err
+		}
+		return floatDecimal(math.Float64frombits(x))
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		x, err := d.uint(1 << (c - 0xcc))
+		if err != nil {
+			return nil, err
+		}
+		return jolt.Int{V: new(big.Int).SetUint64(x)}, nil
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		n := 1 << (c - 0xd0)
+		x, err := d.uint(n)
+		if err != nil {
+			return nil, err
+		}
+		// Sign-extend from n bytes.
+		shift := uint(64 - 8*n)
+		return jolt.BigInt(int64(x<<shift) >> shift), nil
+	case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8:
+		return d.ext(1<<(c-0xd4), start, depth)
+	case 0xd9, 0xda, 0xdb:
+		n, err := d.uint(1 << (c - 0xd9))
+		if err != nil {
+			return nil, err
+		}
+		return d.str(n)
+	case 0xdc, 0xdd:
+		n, err := d.uint(2 << (c - 0xdc))
+		if err != nil {
+			return nil, err
+		}
+		return d.array(n, depth)
+	case 0xde, 0xdf:
+		n, err := d.uint(2 << (c - 0xde))
+		if err != nil {
+			return nil, err
+		}
+		return d.mapValue(n, depth)
+	}
+	return nil, fmt.Errorf("jolt/msgpack: invalid byte 0x%02x at offset %d", c, start)
+}
+
+func (d *decoder) str(n uint64) (any, error) {
+	p, err := d.take(n)
+	if err != nil {
+		return nil, err
+	}
+	if !utf8.Valid(p) {
+		return nil, fmt.Errorf("jolt/msgpack: invalid UTF-8 in string at offset %d", d.i-len(p))
+	}
+	return string(p), nil
+}
+
+func (d *decoder) array(n uint64, depth int) (any, error) {
+	if n > uint64(len(d.b)-d.i) {
+		return nil, errTruncated
+	}
+	out := make([]any, n)
+	for i := range out {
+		v, err := d.value(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = v
+	}
+	return out, nil
+}
+
+// mapValue reads a map as an object when its keys are all strings, and as a
+// Map otherwise; the map inside a Map extension (anyKeys) is always a Map.
+func (d *decoder) mapValue(n uint64, depth int) (any, error) {
+	// Every key and value takes at least one byte.
+	if n > uint64(len(d.b)-d.i)/2 {
+		return nil, errTruncated
+	}
+	anyKeys := d.anyKeys
+	d.anyKeys = false
+	keys, vals := make([]any, 0, n), make([]any, 0, n)
+	seen := make(map[string]bool, n)
+	for i := uint64(0); i < n; i++ {
+		at := d.i
+		k, err := d.value(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if k != nil && !reflect.TypeOf(k).Comparable() {
+			return nil, fmt.Errorf("jolt/msgpack: map key of type %T at offset %d is not usable as a key", k, at)
+		}
+		// Keys are told apart by their JOLT-B encoding, so Int 1 and Int 1
+		// collide.
+		if kb, err := jolt.EncodeBinary(k); err == nil {
+			if seen[string(kb)] {
+				return nil, fmt.Errorf("jolt/msgpack: duplicate map key at offset %d", at)
+			}
+			seen[string(kb)] = true
+		}
+		v, err := d.value(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := k.(string); !ok {
+			anyKeys = true
+		}
+		keys, vals = append(keys, k), append(vals, v)
+	}
+	if anyKeys {
+		m := make(jolt.Map, n)
+		for i, k := range keys {
+			m[k] = vals[i]
+		}
+		return m, nil
+	}
+	obj := make(map[string]any, n)
+	for i, k := range keys {
+		obj[k.(string)] = vals[i]
+	}
+	return obj, nil
+}
+
+func (d *decoder) ext(n uint64, start, depth int) (any, error) {
+	tb, err := d.take(1)
+	if err != nil {
+		return nil, err
+	}
+	typ := int8(tb[0])
+	p, err := d.take(n)
+	if err != nil {
+		return nil, err
+	}
+	bad := func(what string) error {
+		return fmt.Errorf("jolt/msgpack: bad %s in extension %d at offset %d", what, typ, start)
+	}
+	switch typ {
+	case ExtTimestamp:
+		var sec int64
+		var nsec uint32
+		switch len(p) {
+		case 4:
+			sec = int64(binary.BigEndian.Uint32(p))
+		case 8:
+			x := binary.BigEndian.Uint64(p)
+			sec, nsec = int64(x&(1<<34-1)), uint32(x>>34)
+		case 12:
+			nsec, sec = binary.BigEndian.Uint32(p), int64(binary.BigEndian.Uint64(p[4:]))
+		default:
+			return nil, bad("length")
+		}
+		if nsec >= 1e9 {
+			return nil, bad("nanoseconds")
+		}
+		return jolt.Timestamp{RFC3339: time.Unix(sec, int64(nsec)).UTC().Format(time.RFC3339Nano)}, nil
+	case ExtInt:
+		if len(p) < 1 || p[0] > 1 {
+			return nil, bad("sign")
+		}
+		z := new(big.Int).SetBytes(p[1:])
+		if p[0] == 1 {
+			z.Neg(z)
+		}
+		return jolt.Int{V: z}, nil
+	case ExtDecimal:
+		dec, err := jolt.DecFromString(string(p))
+		if err != nil || dec.D.Form != apd.Finite {
+			return nil, bad("decimal")
+		}
+		return dec, nil
+	case ExtUUID:
+		if len(p) != 16 {
+			return nil, bad("length")
+		}
+		var u jolt.UUID
+		copy(u[:], p)
+		return u, nil
+	case ExtTS, ExtDate, ExtTime, ExtLink, ExtAnnot:
+		if !utf8.Valid(p) {
+			return nil, bad("UTF-8")
+		}
+		s := string(p)
+		switch typ {
+		case ExtTS:
+			return jolt.Timestamp{RFC3339: s}, nil
+		case ExtDate:
+			return jolt.Date{YYYYMMDD: s}, nil
+		case ExtTime:
+			return jolt.Time{HHMMSS: s}, nil
+		case ExtLink:
+			return jolt.Link{Ref: s}, nil
+		}
+		return jolt.Annot{Note: s}, nil
+	case ExtSet, ExtMap, ExtEnvelope:
+		return d.container(typ, p, depth)
+	case ExtJOLT:
+		return jolt.DecodeBinary(append([]byte{byte(ExtJOLT)}, p...))
+	}
+	return nil, fmt.Errorf("jolt/msgpack: unknown extension type %d at offset %d", typ, start)
+}
+
+// container decodes the MessagePack payload of a Set, Map or Envelope.
+func (d *decoder) container(typ int8, p []byte, depth int) (any, error) {
+	sub := &decoder{b: p, anyKeys: typ == ExtMap}
+	v, err := sub.value(depth + 1)
+	if err != nil {
+		return nil, err
+	}
+	if sub.i != len(p) {
+		return nil, fmt.Errorf("jolt/msgpack: trailing data in extension %d", typ)
+	}
+	arr, _ := v.([]any)
+	switch typ {
+	case ExtSet:
+		if arr == nil {
+			return nil, fmt.Errorf("jolt/msgpack: Set extension holds %T, not an array", v)
+		}
+		return jolt.Set(arr), nil
+	case ExtMap:
+		m, ok := v.(jolt.Map)
+		if !ok {
+			return nil, fmt.Errorf("jolt/msgpack: Map extension holds %T, not a map", v)
+		}
+		return m, nil
+	}
+	if len(arr) != 2 {
+		return nil, fmt.Errorf("jolt/msgpack: envelope extension needs [meta, body]")
+	}
+	mm, ok := arr[0].(map[string]any)
+	if !ok {
+		return nil, jolt.ErrBadEnvelope
+	}
+	meta, err := jolt.MetaFromMap(mm)
+	if err != nil {
+		return nil, err
+	}
+	return jolt.Envelope{Meta: meta, Body: arr[1]}, nil
+}
+
+// floatDecimal keeps a float as the Decimal with its shortest digits.
+// Infinities and NaN have no JOLT-B form.
+func floatDecimal(f float64) (any, error) {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return nil, fmt.Errorf("jolt/msgpack: float %v has no JOLT value", f)
+	}
+	return jolt.DecFromString(strconv.FormatFloat(f, 'g', -1, 64))
+}
@@ -0,0 +1,446 @@
+package jolt
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+var ErrPatchTestFailed = errors.New("jolt: patch test failed")
+
+// PatchType is the $meta type of an encoded Patch.
+const PatchType = "urn:jolt:patch"
+
+// Patch operations, as in RFC 6902.
+const (
+	OpAdd     = "add"
+	OpRemove  = "remove"
+	OpReplace = "replace"
+	OpMove    = "move"
+	OpCopy    = "copy"
+	OpTest    = "test"
+)
+
+// Operation is one step of a Patch. Path and From are JSON pointers; see
+// ParsePointer for the segments that address Set members and Map entries.
+type Operation struct {
+	Op    string
+	Path  string
+	From  string // move, copy
+	Value any    // add, replace, test
+}
+
+// Patch is an ordered list of operations. It encodes as an envelope of type
+// PatchType whose body is the list of operations, so patches can be stored
+// and queued like any other JOLT document.
+type Patch []Operation
+
+// MarshalJOLT implements Marshaler.
+func (p Patch) MarshalJOLT() (any, error) {
+	ops := make([]any, len(p))
+	for i, op := range p {
+		m := map[string]any{"op": op.Op, "path": op.Path}
+		switch op.Op {
+		case OpMove, OpCopy:
+			m["from"] = op.From
+		case OpAdd, OpReplace, OpTest:
+			m["value"] = op.Value
+		}
+		ops[i] = m
+	}
+	return Envelope{Meta: Meta{Type: PatchType, Version: "1.0.0"}, Body: ops}, nil
+}
+
+// UnmarshalJOLT implements Unmarshaler. Both the envelope written by
+// MarshalJOLT and a bare list of operations are accepted.
+func (p *Patch) UnmarshalJOLT(v any) error {
+	if env, err := AsEnvelope(v); err == nil {
+		if env.Meta.Type != PatchType {
+			return fmt.Errorf("jolt: envelope type %q is not a patch", env.Meta.Type)
+		}
+		v = env.Body
+	}
+	list, ok := v.([]any)
+	if !ok {
+		return fmt.Errorf("jolt: patch must be a list of operations, got %T", v)
+	}
+	out := make(Patch, len(list))
+	for i, it := range list {
+		m, ok := it.(map[string]any)
+		if !ok {
+			return fmt.Errorf("jolt: patch operation %d is %T, not an object", i, it)
+		}
+		op, _ := m["op"].(string)
+		path, ok := m["path"].(string)
+		if !ok {
+			return fmt.Errorf("jolt: patch operation %d has no path", i)
+		}
+		out[i] = Operation{Op: op, Path: path, Value: m["value"]}
+		switch op {
+		case OpMove, OpCopy:
+			if out[i].From, ok = m["from"].(string); !ok {
+				return fmt.Errorf("jolt: patch operation %d (%s) has no from", i, op)
+			}
+		case OpAdd, OpReplace, OpTest:
+			if _, ok := m["value"]; !ok {
+				return fmt.Errorf("jolt: patch operation %d (%s) has no value", i, op)
+			}
+		case OpRemove:
+		default:
+			return fmt.Errorf("jolt: patch operation %d: unknown op %q", i, op)
+		}
+	}
+	*p = out
+	return nil
+}
+
+// ApplyPatch applies p to a copy of v and returns the result; v itself is not
+// modified. Operations run in order and the first failure aborts the patch.
+// A test operation compares with Equal, so decimals must match exactly.
+func ApplyPatch(v any, p Patch) (any, error) {
+	doc := cloneValue(v)
+	for i, op := range p {
+		var err error
+		doc, err = applyOp(doc, op)
+		if err != nil {
+			return nil, fmt.Errorf("jolt: patch operation %d (%s %s): %w", i, op.Op, op.Path, err)
+		}
+	}
+	return doc, nil
+}
+
+func applyOp(doc any, op Operation) (any, error) {
+	path, err := ParsePointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+	switch op.Op {
+	case OpAdd, OpRemove, OpReplace:
+		return edit(doc, path, op.Op, cloneValue(op.Value))
+	case OpTest:
+		got, err := lookup(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		if !Equal(got, op.Value) {
+			return nil, ErrPatchTestFailed
+		}
+		return doc, nil
+	case OpMove, OpCopy:
+		from, err := ParsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		val, err := lookup(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		if op.Op == OpCopy {
+			return edit(doc, path, OpAdd, cloneValue(val))
+		}
+		if len(from) < len(path) && FormatPointer(path[:len(from)]) == op.From {
+			return nil, errors.New("cannot move a value into itself")
+		}
+		if doc, err = edit(doc, from, OpRemove, nil); err != nil {
+			return nil, err
+		}
+		return edit(doc, path, OpAdd, val)
+	}
+	return nil, fmt.Errorf("unknown op %q", op.Op)
+}
+
+// edit performs add, remove or replace at path and returns the new root.
+// Containers are updated in place where possible; slices are re-stored in
+// their parent since their headers change.
+func edit(node any, path []string, op string, val any) (any, error) {
+	if len(path) == 0 {
+		if op == OpRemove {
+			return nil, nil
+		}
+		return val, nil
+	}
+	seg := path[0]
+	if s, ok := node.(Set); ok && len(path) > 1 {
+		// Editing a member changes its canonical form, so it is found by
+		// index once, before the edit, rather than looked up again after.
+		i, err := setIndex(s, seg)
+		if err != nil {
+			return nil, err
+		}
+		if i < 0 {
+			return nil, fmt.Errorf("%w: no set member %s", ErrPathNotFound, seg)
+		}
+		nc, err := edit(s[i], path[1:], op, val)
+		if err != nil {
+			return nil, err
+		}
+		for j, m := range s {
+			if j != i && Equal(m, nc) {
+				return nil, fmt.Errorf("set member %s became equal to another member", seg)
+			}
+		}
+		s[i] = nc
+		return s, nil
+	}
+	if len(path) > 1 {
+		c, err := child(node, seg)
+		if err != nil {
+			return nil, err
+		}
+		nc, err := edit(c, path[1:], op, val)
+		if err != nil {
+			return nil, err
+		}
+		return edit(node, path[:1], OpReplace, nc)
+	}
+
+	switch x := node.(type) {
+	case map[string]any:
+		if _, ok := x[seg]; !ok && op != OpAdd {
+			return nil, fmt.Errorf("%w: no field %q", ErrPathNotFound, seg)
+		}
+		if op == OpRemove {
+			delete(x, seg)
+		} else {
+			x[seg] = val
+		}
+		return x, nil
+	case []any:
+		i, err := arrayIndex(seg, len(x), op == OpAdd)
+		if err != nil {
+			return nil, err
+		}
+		switch op {
+		case OpAdd:
+			x = append(x, nil)
+			copy(x[i+1:], x[i:])
+			x[i] = val
+		case OpRemove:
+			x = append(x[:i], x[i+1:]...)
+		default:
+			x[i] = val
+		}
+		return x, nil
+	case Set:
+		if op == OpAdd && seg == "-" {
+			for _, m := range x {
+				if Equal(m, val) {
+					return x, nil
+				}
+			}
+			return append(x, val), nil
+		}
+		i, err := setIndex(x, seg)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case i < 0 && op != OpAdd:
+			return nil, fmt.Errorf("%w: no set member %s", ErrPathNotFound, seg)
+		case op == OpRemove:
+			return append(x[:i], x[i+1:]...), nil
+		case i >= 0:
+			x[i] = val
+			return x, nil
+		}
+		return append(x, val), nil
+	case Map:
+		k, ok, err := mapKey(x, seg)
+		if err != nil {
+			return nil, err
+		}
+		if !ok && op != OpAdd {
+			return nil, fmt.Errorf("%w: no map key %s", ErrPathNotFound, seg)
+		}
+		if op == OpRemove {
+			delete(x, k)
+		} else {
+			x[k] = val
+		}
+		return x, nil
+	case Envelope:
+		switch {
+		case op == OpRemove:
+			return nil, errors.New("cannot remove $meta or $body")
+		case seg == "$body":
+			x.Body = val
+		case seg == "$meta":
+			mm, ok := val.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("$meta must be an object, got %T", val)
+			}
+			meta, err := MetaFromMap(mm)
+			if err != nil {
+				return nil, err
+			}
+			x.Meta = meta
+		default:
+			return nil, fmt.Errorf("%w: envelopes have only $meta and $body", ErrPathNotFound)
+		}
+		return x, nil
+	}
+	return nil, fmt.Errorf("%w: %T has no children", ErrPathNotFound, node)
+}
+
+// Diff returns a patch that turns a into b. Objects, Maps and envelopes are
+// compared entry by entry and Sets by membership; arrays are compared
+// position by position, with elements added or removed at the end.
+func Diff(a, b any) Patch {
+	var p Patch
+	diffInto(&p, nil, a, b)
+	return p
+}
+
+func diffInto(p *Patch, path []string, a, b any) {
+	if Equal(a, b) {
+		return
+	}
+	at := func(seg string) []string { return append(append([]string(nil), path...), seg) }
+	replace := func() { *p = append(*p, Operation{Op: OpReplace, Path: FormatPointer(path), Value: cloneValue(b)}) }
+	switch x := a.(type) {
+	case map[string]any:
+		y, ok := b.(map[string]any)
+		if !ok {
+			replace()
+			return
+		}
+		for _, k := range sortedStringKeys(x) {
+			if _, ok := y[k]; !ok {
+				*p = append(*p, Operation{Op: OpRemove, Path: FormatPointer(at(k))})
+			}
+		}
+		for _, k := range sortedStringKeys(y) {
+			if xv, ok := x[k]; ok {
+				diffInto(p, at(k), xv, y[k])
+			} else {
+				*p = append(*p, Operation{Op: OpAdd, Path: FormatPointer(at(k)), Value: cloneValue(y[k])})
+			}
+		}
+	case []any:
+		y, ok := b.([]any)
+		if !ok {
+			replace()
+			return
+		}
+		n := min(len(x), len(y))
+		for i := 0; i < n; i++ {
+			diffInto(p, at(strconv.Itoa(i)), x[i], y[i])
+		}
+		for i := len(x) - 1; i >= n; i-- {
+			*p = append(*p, Operation{Op: OpRemove, Path: FormatPointer(at(strconv.Itoa(i)))})
+		}
+		for i := n; i < len(y); i++ {
+			*p = append(*p, Operation{Op: OpAdd, Path: FormatPointer(at("-")), Value: cloneValue(y[i])})
+		}
+	case Set:
+		y, ok := b.(Set)
+		if !ok {
+			replace()
+			return
+		}
+		for _, m := range sortedCanonical(x) {
+			if !setHas(y, m) {
+				seg, _ := MemberSegment(m)
+				*p = append(*p, Operation{Op: OpRemove, Path: FormatPointer(at(seg))})
+			}
+		}
+		for _, m := range sortedCanonical(y) {
+			if !setHas(x, m) {
+				*p = append(*p, Operation{Op: OpAdd, Path: FormatPointer(at("-")), Value: cloneValue(m)})
+			}
+		}
+	case Map:
+		y, ok := b.(Map)
+		if !ok {
+			replace()
+			return
+		}
+		for _, k := range sortedCanonical(mapKeys(x)) {
+			if _, ok := mapFind(y, k); !ok {
+				seg, _ := KeySegment(k)
+				*p = append(*p, Operation{Op: OpRemove, Path: FormatPointer(at(seg))})
+			}
+		}
+		for _, k := range sortedCanonical(mapKeys(y)) {
+			seg, _ := KeySegment(k)
+			if xk, ok := mapFind(x, k); ok {
+				diffInto(p, at(seg), x[xk], y[k])
+			} else {
+				*p = append(*p, Operation{Op: OpAdd, Path: FormatPointer(at(seg)), Value: cloneValue(y[k])})
+			}
+		}
+	case Envelope:
+		y, ok := b.(Envelope)
+		if !ok {
+			replace()
+			return
+		}
+		if !Equal(x.Meta.ToMap(), y.Meta.ToMap()) {
+			*p = append(*p, Operation{Op: OpReplace, Path: FormatPointer(at("$meta")), Value: y.Meta.ToMap()})
+		}
+		diffInto(p, at("$body"), x.Body, y.Body)
+	default:
+		replace()
+	}
+}
+
+func sortedStringKeys(m map[string]any) []string {
+	ks := make([]string, 0, len(m))
+	for k := range m {
+		ks = append(ks, k)
+	}
+	sort.Strings(ks)
+	return ks
+}
+
+func mapKeys(m Map) []any {
+	ks := make([]any, 0, len(m))
+	for k := range m {
+		ks = append(ks, k)
+	}
+	return ks
+}
+
+// sortedCanonical orders values by their canonical encoding, the order Sets
+// and Maps are written in.
+func sortedCanonical(vs []any) []any {
+	type kb struct {
+		v any
+		b string
+	}
+	tmp := make([]kb, len(vs))
+	for i, v := range vs {
+		b, _ := EncodeBinary(v)
+		tmp[i] = kb{v, string(b)}
+	}
+	sort.Slice(tmp, func(i, j int) bool { return tmp[i].b < tmp[j].b })
+	out := make([]any, len(vs))
+	for i, t := range tmp {
+		out[i] = t.v
+	}
+	return out
+}
+
+func setHas(s Set, v any) bool {
+	for _, m := range s {
+		if Equal(m, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// mapFind returns the key of m equal to k; Map keys such as Int hold
+// pointers, so lookups go by canonical encoding rather than ==.
+func mapFind(m Map, k any) (any, bool) {
+	if _, ok := m[k]; ok {
+		return k, true
+	}
+	for mk := range m {
+		if Equal(mk, k) {
+			return mk, true
+		}
+	}
+	return nil, false
+}
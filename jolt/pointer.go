@@ -0,0 +1,188 @@
+package jolt
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var ErrPathNotFound = errors.New("jolt: path not found")
+
+// Pointer segments for JOLT containers JSON has no syntax for. A Set member
+// is addressed as "$m:" and a Map entry as "$k:" followed by the base64url
+// (unpadded) canonical JOLT-B encoding of the member or key. Maps with string
+// keys also accept the plain key, and envelopes have the "$meta" and "$body"
+// children of their JSON form.
+const (
+	memberPrefix = "$m:"
+	keyPrefix    = "$k:"
+)
+
+// ParsePointer splits an RFC 6901 JSON pointer ("/lines/0/qty") into
+// unescaped segments. The empty pointer addresses the whole document.
+func ParsePointer(p string) ([]string, error) {
+	if p == "" {
+		return nil, nil
+	}
+	if p[0] != '/' {
+		return nil, fmt.Errorf("jolt: pointer %q must start with /", p)
+	}
+	segs := strings.Split(p[1:], "/")
+	for i, s := range segs {
+		segs[i] = strings.ReplaceAll(strings.ReplaceAll(s, "~1", "/"), "~0", "~")
+	}
+	return segs, nil
+}
+
+// FormatPointer is the inverse of ParsePointer.
+func FormatPointer(segs []string) string {
+	var b strings.Builder
+	for _, s := range segs {
+		b.WriteByte('/')
+		b.WriteString(strings.ReplaceAll(strings.ReplaceAll(s, "~", "~0"), "/", "~1"))
+	}
+	return b.String()
+}
+
+// MemberSegment returns the pointer segment addressing v as a Set member.
+func MemberSegment(v any) (string, error) {
+	b, err := EncodeBinary(v)
+	if err != nil {
+		return "", err
+	}
+	return memberPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// KeySegment returns the pointer segment addressing key k of a Map: the key
+// itself for strings (unless it could be mistaken for an encoded key),
+// otherwise its "$k:" form.
+func KeySegment(k any) (string, error) {
+	if s, ok := k.(string); ok && !strings.HasPrefix(s, keyPrefix) {
+		return s, nil
+	}
+	b, err := EncodeBinary(k)
+	if err != nil {
+		return "", err
+	}
+	return keyPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// segmentBytes returns the canonical encoding a "$m:"/"$k:" segment names,
+// or the encoding of seg as a string key.
+func segmentBytes(seg, prefix string) ([]byte, error) {
+	if rest, ok := strings.CutPrefix(seg, prefix); ok {
+		b, err := base64.RawURLEncoding.DecodeString(rest)
+		if err != nil {
+			return nil, fmt.Errorf("jolt: bad segment %q: %w", seg, err)
+		}
+		return b, nil
+	}
+	return EncodeBinary(seg)
+}
+
+// setIndex finds the member of s a "$m:" segment names.
+func setIndex(s Set, seg string) (int, error) {
+	want, err := segmentBytes(seg, memberPrefix)
+	if err != nil {
+		return 0, err
+	}
+	for i, m := range s {
+		if b, err := EncodeBinary(m); err == nil && bytes.Equal(b, want) {
+			return i, nil
+		}
+	}
+	return -1, nil
+}
+
+// mapKey finds the key of m a segment names. ok is false when there is no
+// such entry; key is then the decoded key to insert under.
+func mapKey(m Map, seg string) (key any, ok bool, err error) {
+	want, err := segmentBytes(seg, keyPrefix)
+	if err != nil {
+		return nil, false, err
+	}
+	for k := range m {
+		if b, err := EncodeBinary(k); err == nil && bytes.Equal(b, want) {
+			return k, true, nil
+		}
+	}
+	if !strings.HasPrefix(seg, keyPrefix) {
+		return seg, false, nil
+	}
+	k, err := DecodeBinary(want)
+	return k, false, err
+}
+
+func arrayIndex(seg string, n int, allowEnd bool) (int, error) {
+	if seg == "-" && allowEnd {
+		return n, nil
+	}
+	i, err := strconv.Atoi(seg)
+	if err != nil || i < 0 || (seg != "0" && seg[0] == '0') {
+		return 0, fmt.Errorf("jolt: bad array index %q", seg)
+	}
+	if i > n || (i == n && !allowEnd) {
+		return 0, fmt.Errorf("%w: index %d out of range", ErrPathNotFound, i)
+	}
+	return i, nil
+}
+
+// child returns the value seg addresses inside v.
+func child(v any, seg string) (any, error) {
+	switch x := v.(type) {
+	case map[string]any:
+		c, ok := x[seg]
+		if !ok {
+			return nil, fmt.Errorf("%w: no field %q", ErrPathNotFound, seg)
+		}
+		return c, nil
+	case []any:
+		i, err := arrayIndex(seg, len(x), false)
+		if err != nil {
+			return nil, err
+		}
+		return x[i], nil
+	case Set:
+		i, err := setIndex(x, seg)
+		if err != nil {
+			return nil, err
+		}
+		if i < 0 {
+			return nil, fmt.Errorf("%w: no set member %s", ErrPathNotFound, seg)
+		}
+		return x[i], nil
+	case Map:
+		k, ok, err := mapKey(x, seg)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, fmt.Errorf("%w: no map key %s", ErrPathNotFound, seg)
+		}
+		return x[k], nil
+	case Envelope:
+		switch seg {
+		case "$meta":
+			return x.Meta.ToMap(), nil
+		case "$body":
+			return x.Body, nil
+		}
+		return nil, fmt.Errorf("%w: envelopes have only $meta and $body", ErrPathNotFound)
+	}
+	return nil, fmt.Errorf("%w: %T has no children", ErrPathNotFound, v)
+}
+
+// lookup follows segs from v.
+func lookup(v any, segs []string) (any, error) {
+	for _, s := range segs {
+		c, err := child(v, s)
+		if err != nil {
+			return nil, err
+		}
+		v = c
+	}
+	return v, nil
+}
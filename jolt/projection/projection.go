@@ -0,0 +1,292 @@
+// Package projection gives API consumers role-specific views of documents.
+// A view lists the paths its role may see and, optionally, redaction rules
+// (see package redact) for what it sees; everything else is removed, not
+// nulled, so responses keep a clean shape. Views are usually declared as
+// JSONC:
+//
+//	[
+//	  { "name": "support", "allow": ["$body.$id", "$body.status", "$body.pii"],
+//	    "rules": [ { "path": "$body.pii.email", "action": "mask", "keep": 4 } ] },
+//	  { "name": "finance", "allow": ["$body.$id", "$body.price", "$body.lines.*.price"] },
+//	  { "name": "partner", "allow": ["$body.$id", "$body.status"], "default": true }
+//	]
+//
+// Envelope (and {"$meta","$body"}) $meta is always visible and records the
+// view applied under "view".
+package projection
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/chandan-cmd-dev/jolt-go/jolt"
+	"github.com/chandan-cmd-dev/jolt-go/jolt/redact"
+	"github.com/chandan-cmd-dev/jolt-go/joltschema"
+)
+
+// MetaKey is the $meta key naming the view applied.
+const MetaKey = "view"
+
+// ErrNoView is returned for a role with no view, or no role and no default
+// view.
+var ErrNoView = errors.New("projection: no view for role")
+
+// ErrNotAllowed is returned by CheckMergePatch for a patch that writes
+// outside the view.
+var ErrNotAllowed = errors.New("projection: path not in view")
+
+// View is what one role may see. Allow holds path patterns in the syntax of
+// redact.Rule.Path; a value is kept when a pattern matches it or one of its
+// ancestors, and containers on the way to a match are kept with only their
+// allowed children. Rules then redact what remains.
+type View struct {
+	Name    string        `jolt:"name"`
+	Allow   []string      `jolt:"allow"`
+	Rules   []redact.Rule `jolt:"rules,omitempty"`
+	Default bool          `jolt:"default,omitempty"`
+
+	allow  []redact.Pattern
+	policy *redact.Policy
+}
+
+// ParseViews reads one view or an array of views from JSONC.
+func ParseViews(data []byte) ([]*View, error) {
+	v, err := jolt.UnmarshalJSONTyped(data)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := v.([]any); !ok {
+		v = []any{v}
+	}
+	var views []*View
+	if err := jolt.DecodeInto(v, &views); err != nil {
+		return nil, err
+	}
+	for _, vw := range views {
+		if err := vw.Compile(); err != nil {
+			return nil, err
+		}
+	}
+	return views, nil
+}
+
+// Compile checks the view; ParseViews calls it, views built in Go must call
+// it before use.
+func (v *View) Compile() error {
+	if v.Name == "" {
+		return errors.New("projection: view needs a name")
+	}
+	v.allow = v.allow[:0]
+	for _, a := range v.Allow {
+		v.allow = append(v.allow, redact.ParsePattern(a))
+	}
+	v.policy = nil
+	if len(v.Rules) > 0 {
+		v.policy = &redact.Policy{Rules: v.Rules}
+		if err := v.policy.Compile(); err != nil {
+			return fmt.Errorf("projection: view %s: %w", v.Name, err)
+		}
+	}
+	return nil
+}
+
+// visible reports whether the value at path is kept whole (all) or kept as a
+// container on the way to allowed values (partial).
+func (v *View) visible(path jolt.Path, env bool) (all, partial bool) {
+	if n := len(path); n > 0 {
+		if k, ok := path[n-1].(jolt.MapKey); ok {
+			// A Map key is visible with its value.
+			path = append(path[:n-1:n-1], k.Key)
+		}
+	}
+	if env && len(path) > 0 && path[0] == "$meta" {
+		return true, false
+	}
+	for i := 0; i <= len(path); i++ {
+		for _, p := range v.allow {
+			if p.Match(path[:i]) {
+				return true, false
+			}
+		}
+	}
+	for _, p := range v.allow {
+		if p.Prefix(path) {
+			return false, true
+		}
+	}
+	return false, false
+}
+
+// Allows reports whether the view shows the value at path whole. Unlike
+// projection, $meta is allowed only when a pattern names it.
+func (v *View) Allows(path jolt.Path) bool {
+	all, _ := v.visible(path, false)
+	return all
+}
+
+// CheckMergePatch reports, as ErrNotAllowed, the first value a merge patch
+// (see jolt.MergePatch) would write or delete outside the view. Objects in
+// the patch are followed into partly visible containers; every other value
+// must land on a path the view allows whole.
+func (v *View) CheckMergePatch(patch any) error {
+	return v.checkPatch(jolt.Path{}, patch)
+}
+
+func (v *View) checkPatch(path jolt.Path, patch any) error {
+	if v.Allows(path) {
+		return nil
+	}
+	obj, ok := patch.(map[string]any)
+	if !ok || len(path) > 0 && !v.partial(path) {
+		return fmt.Errorf("%w: %s", ErrNotAllowed, path)
+	}
+	for k, x := range obj {
+		if err := v.checkPatch(append(path[:len(path):len(path)], k), x); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (v *View) partial(path jolt.Path) bool {
+	_, partial := v.visible(path, false)
+	return partial
+}
+
+// ProjectBinary applies the view to JOLT-B: allowed subtrees are copied
+// through byte for byte and denied ones skipped without being decoded.
+func (v *View) ProjectBinary(jb []byte) ([]byte, error) {
+	view, err := jolt.NewView(jb)
+	if err != nil {
+		return nil, err
+	}
+	_, env := view.Field("$meta")
+	out, err := jolt.TransformBinary(jb, func(path jolt.Path, x any) (any, error) {
+		all, partial := v.visible(path, env)
+		switch {
+		case env && len(path) == 1 && path[0] == "$meta":
+			return v.stampMeta(x)
+		case all, partial:
+			return x, nil
+		}
+		return nil, jolt.Remove
+	})
+	if err != nil || v.policy == nil {
+		return out, err
+	}
+	return v.policy.ApplyBinary(out)
+}
+
+// Project applies the view to a decoded document, returning a copy.
+func (v *View) Project(doc any) (any, error) {
+	_, err := jolt.AsEnvelope(doc)
+	env := err == nil
+	out, err := jolt.Transform(doc, func(path jolt.Path, x any) (any, error) {
+		all, partial := v.visible(path, env)
+		switch {
+		case env && len(path) == 1 && path[0] == "$meta":
+			return v.stampMeta(x)
+		case all, partial:
+			return x, nil
+		}
+		return nil, jolt.Remove
+	})
+	if err != nil || v.policy == nil {
+		return out, err
+	}
+	return v.policy.Apply(out)
+}
+
+func (v *View) stampMeta(x any) (any, error) {
+	if mv, ok := x.(jolt.View); ok {
+		d, err := mv.Decode()
+		if err != nil {
+			return nil, err
+		}
+		x = d
+	}
+	if m, ok := x.(map[string]any); ok {
+		m[MetaKey] = v.Name
+	}
+	return x, nil
+}
+
+// Registry picks a view by role.
+type Registry struct {
+	views map[string]*View
+	def   *View
+}
+
+// NewRegistry registers views by name; at most one may be the default.
+func NewRegistry(views ...*View) (*Registry, error) {
+	r := &Registry{views: map[string]*View{}}
+	for _, v := range views {
+		if _, dup := r.views[v.Name]; dup {
+			return nil, fmt.Errorf("projection: duplicate view %s", v.Name)
+		}
+		if v.Default {
+			if r.def != nil {
+				return nil, fmt.Errorf("projection: views %s and %s are both default", r.def.Name, v.Name)
+			}
+			r.def = v
+		}
+		r.views[v.Name] = v
+	}
+	return r, nil
+}
+
+// SetKey and SetSchema configure hash and annotation redaction rules of all
+// views.
+func (r *Registry) SetKey(key []byte) {
+	for _, v := range r.views {
+		if v.policy != nil {
+			v.policy.Key = key
+		}
+	}
+}
+
+func (r *Registry) SetSchema(s *joltschema.Schema) {
+	for _, v := range r.views {
+		if v.policy != nil {
+			v.policy.Schema = s
+		}
+	}
+}
+
+// For returns the view named role, or the default view when role is empty.
+// An unknown role gets ErrNoView rather than the default, so a misspelt
+// role never widens what a caller sees.
+func (r *Registry) For(role string) (*View, error) {
+	if role == "" && r.def != nil {
+		return r.def, nil
+	}
+	if v, ok := r.views[role]; ok {
+		return v, nil
+	}
+	return nil, fmt.Errorf("%w %q", ErrNoView, role)
+}
+
+type roleKey struct{}
+
+// WithRole returns a context carrying the caller's role, as set by
+// authentication middleware.
+func WithRole(ctx context.Context, role string) context.Context {
+	return context.WithValue(ctx, roleKey{}, role)
+}
+
+// RoleFrom returns the role WithRole stored, or "".
+func RoleFrom(ctx context.Context) string {
+	role, _ := ctx.Value(roleKey{}).(string)
+	return role
+}
+
+// ProjectRequest projects stored JOLT-B for the role in r's context.
+func (reg *Registry) ProjectRequest(r *http.Request, jb []byte) ([]byte, error) {
+	v, err := reg.For(RoleFrom(r.Context()))
+	if err != nil {
+		return nil, err
+	}
+	return v.ProjectBinary(jb)
+}
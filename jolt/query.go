@@ -0,0 +1,752 @@
+package jolt
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/apd/v3"
+)
+
+// Match is one value selected by a query. Path is a JSON pointer to it (see
+// ParsePointer), so matches can feed ApplyPatch. Querying a View yields
+// Views.
+type Match struct {
+	Path  string
+	Value any
+}
+
+// PathExpr is a parsed JOLTPath query. The syntax follows JSONPath:
+//
+//	$body.lines[0]           fields (leading "$" optional) and array indexes
+//	lines[-1]                indexes count from the end when negative
+//	$body['ship-to']         quoted field names
+//	lines[*].sku, $meta.*    wildcards over elements, fields, members, values
+//	..price                  recursive descent
+//	lines[?(@.qty > 2)]      filters over the children of a node
+//
+// Filters combine comparisons (== != < <= > >=) with && || ! and
+// parentheses; "@" is the child being tested and "$" the document. Literals
+// are numbers, quoted strings, true, false, null and typed values written
+// dec('19.99'), int('7'), ts('2025-01-01T00:00:00Z'), date('2025-01-01'),
+// time('09:30:00') and uuid('…'). Numbers compare exactly by value, Int and
+// Decimal alike; timestamps compare chronologically, dates, times and strings
+// in order. "x in @.tags" tests Set (or array) membership with Equal, and a
+// path on its own tests that something matches. A comparison holds when any
+// value the path matches satisfies it.
+type PathExpr struct {
+	src   string
+	steps []qstep
+}
+
+type qstepKind int
+
+const (
+	qField qstepKind = iota
+	qIndex
+	qWild
+	qFilter
+)
+
+type qstep struct {
+	kind   qstepKind
+	deep   bool // recursive descent
+	name   string
+	index  int
+	filter qexpr
+}
+
+// Query evaluates expr against v, a decoded tree or a View.
+func Query(v any, expr string) ([]Match, error) {
+	p, err := ParsePath(expr)
+	if err != nil {
+		return nil, err
+	}
+	return p.Find(v), nil
+}
+
+// QueryBinary evaluates expr against JOLT-B without decoding it; the matches
+// hold Views.
+func QueryBinary(jb []byte, expr string) ([]Match, error) {
+	p, err := ParsePath(expr)
+	if err != nil {
+		return nil, err
+	}
+	v, err := NewView(jb)
+	if err != nil {
+		return nil, err
+	}
+	return p.Find(v), nil
+}
+
+// ParsePath compiles a query for repeated use.
+func ParsePath(expr string) (*PathExpr, error) {
+	qp := &qparser{s: expr}
+	qp.space()
+	var steps []qstep
+	if qp.peek() == '$' && !isNameByte(qp.at(1)) {
+		qp.i++
+	} else if isNameByte(qp.peek()) {
+		var st qstep // leading name without "$."
+		if err := qp.dotted(&st); err != nil {
+			return nil, err
+		}
+		steps = append(steps, st)
+	}
+	more, err := qp.steps()
+	if err != nil {
+		return nil, err
+	}
+	steps = append(steps, more...)
+	qp.space()
+	if qp.i < len(qp.s) {
+		return nil, qp.errorf("unexpected %q", qp.s[qp.i:])
+	}
+	return &PathExpr{src: expr, steps: steps}, nil
+}
+
+func (p *PathExpr) String() string { return p.src }
+
+// Find returns the values p selects in v, in document order.
+func (p *PathExpr) Find(v any) []Match {
+	nodes := p.find(v, v)
+	out := make([]Match, len(nodes))
+	for i, n := range nodes {
+		out[i] = Match{Path: FormatPointer(n.path), Value: n.val}
+	}
+	return out
+}
+
+type qnode struct {
+	path []string
+	val  any
+}
+
+func (p *PathExpr) find(v, root any) []qnode {
+	cur := []qnode{{val: v}}
+	for _, st := range p.steps {
+		var next []qnode
+		for _, n := range cur {
+			if st.deep {
+				for _, d := range descendants(n) {
+					next = append(next, st.apply(d, root)...)
+				}
+			} else {
+				next = append(next, st.apply(n, root)...)
+			}
+		}
+		cur = next
+	}
+	return cur
+}
+
+func (st qstep) apply(n qnode, root any) []qnode {
+	switch st.kind {
+	case qField:
+		if c, ok := qfield(n.val, st.name); ok {
+			return []qnode{{path: extend(n.path, st.name), val: c}}
+		}
+	case qIndex:
+		if c, i, ok := qindex(n.val, st.index); ok {
+			return []qnode{{path: extend(n.path, strconv.Itoa(i)), val: c}}
+		}
+	case qWild:
+		return qchildren(n)
+	case qFilter:
+		var out []qnode
+		for _, c := range qchildren(n) {
+			if st.filter.test(c.val, root) {
+				out = append(out, c)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+func extend(path []string, seg string) []string {
+	return append(append([]string(nil), path...), seg)
+}
+
+func descendants(n qnode) []qnode {
+	out := []qnode{n}
+	for _, c := range qchildren(n) {
+		out = append(out, descendants(c)...)
+	}
+	return out
+}
+
+// qfield and qindex select one child of a decoded value or View; qchildren
+// lists all of them.
+func qfield(v any, name string) (any, bool) {
+	switch x := v.(type) {
+	case map[string]any:
+		c, ok := x[name]
+		return c, ok
+	case Map:
+		if k, ok := mapFind(x, name); ok {
+			return x[k], true
+		}
+	case Envelope:
+		switch name {
+		case "$meta":
+			return x.Meta.ToMap(), true
+		case "$body":
+			return x.Body, true
+		}
+	case View:
+		return x.Field(name)
+	}
+	return nil, false
+}
+
+func qindex(v any, i int) (any, int, bool) {
+	n := -1
+	switch x := v.(type) {
+	case []any:
+		n = len(x)
+	case View:
+		if x.tag() == tagArr {
+			n = x.Len()
+		}
+	}
+	if i < 0 {
+		i += n
+	}
+	if n < 0 || i < 0 || i >= n {
+		return nil, 0, false
+	}
+	if a, ok := v.([]any); ok {
+		return a[i], i, true
+	}
+	c, _ := v.(View).Index(i)
+	return c, i, true
+}
+
+func qchildren(n qnode) []qnode {
+	var out []qnode
+	add := func(seg string, v any) { out = append(out, qnode{path: extend(n.path, seg), val: v}) }
+	switch x := n.val.(type) {
+	case map[string]any:
+		for _, k := range sortedStringKeys(x) {
+			add(k, x[k])
+		}
+	case []any:
+		for i, it := range x {
+			add(strconv.Itoa(i), it)
+		}
+	case Set:
+		for _, m := range x {
+			seg, _ := MemberSegment(m)
+			add(seg, m)
+		}
+	case Map:
+		for _, k := range sortedCanonical(mapKeys(x)) {
+			seg, _ := KeySegment(k)
+			add(seg, x[k])
+		}
+	case Envelope:
+		add("$meta", x.Meta.ToMap())
+		add("$body", x.Body)
+	case View:
+		switch x.tag() {
+		case tagArr:
+			for i, it := range x.items() {
+				add(strconv.Itoa(i), it)
+			}
+		case tagSet:
+			for _, it := range x.items() {
+				add(it.memberSegment(), it)
+			}
+		case tagObj:
+			for _, f := range x.fields() {
+				add(f.name, f.val)
+			}
+		case tagMap:
+			for _, e := range x.entries() {
+				add(e[0].keySegment(), e[1])
+			}
+		case tagEnv:
+			meta, body := x.envelope()
+			add("$meta", meta)
+			add("$body", body)
+		}
+	}
+	return out
+}
+
+// Filter expressions.
+
+type qexpr interface {
+	test(cur, root any) bool
+}
+
+type qor struct{ a, b qexpr }
+type qand struct{ a, b qexpr }
+type qnot struct{ x qexpr }
+
+func (e qor) test(cur, root any) bool  { return e.a.test(cur, root) || e.b.test(cur, root) }
+func (e qand) test(cur, root any) bool { return e.a.test(cur, root) && e.b.test(cur, root) }
+func (e qnot) test(cur, root any) bool { return !e.x.test(cur, root) }
+
+// qoperand is a literal or a path from "@" (rel) or "$".
+type qoperand struct {
+	path *PathExpr
+	rel  bool
+	lit  any
+}
+
+func (o qoperand) values(cur, root any) []any {
+	if o.path == nil {
+		return []any{o.lit}
+	}
+	from := root
+	if o.rel {
+		from = cur
+	}
+	var out []any
+	for _, n := range o.path.find(from, root) {
+		v := n.val
+		if vw, ok := v.(View); ok {
+			d, err := vw.Decode()
+			if err != nil {
+				continue
+			}
+			v = d
+		}
+		out = append(out, v)
+	}
+	return out
+}
+
+type qexists struct{ x qoperand }
+
+func (e qexists) test(cur, root any) bool {
+	if e.x.path == nil {
+		b, _ := e.x.lit.(bool)
+		return b
+	}
+	from := root
+	if e.x.rel {
+		from = cur
+	}
+	return len(e.x.path.find(from, root)) > 0
+}
+
+type qcmp struct {
+	op   string
+	l, r qoperand
+}
+
+func (e qcmp) test(cur, root any) bool {
+	rs := e.r.values(cur, root)
+	for _, l := range e.l.values(cur, root) {
+		for _, r := range rs {
+			if compareOp(e.op, l, r) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func compareOp(op string, a, b any) bool {
+	switch op {
+	case "in":
+		switch c := b.(type) {
+		case Set:
+			return setHas(c, a)
+		case []any:
+			return setHas(c, a)
+		}
+		return false
+	case "==":
+		return valuesEqual(a, b)
+	case "!=":
+		return !valuesEqual(a, b)
+	}
+	c, ok := compareValues(a, b)
+	if !ok {
+		return false
+	}
+	switch op {
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	}
+	return false
+}
+
+func valuesEqual(a, b any) bool {
+	if c, ok := compareValues(a, b); ok {
+		return c == 0
+	}
+	return Equal(a, b)
+}
+
+// compareValues orders a and b when they are both numbers, timestamps, dates,
+// times or strings. A string on one side is read as the other side's
+// temporal type.
+func compareValues(a, b any) (int, bool) {
+	if x, ok := numericOf(a); ok {
+		if y, ok := numericOf(b); ok {
+			return x.Cmp(y), true
+		}
+		return 0, false
+	}
+	if x, ok := timestampOf(a, b); ok {
+		if y, ok := timestampOf(b, a); ok {
+			return x.Compare(y), true
+		}
+		return 0, false
+	}
+	if x, ok := orderedString(a, b); ok {
+		if y, ok := orderedString(b, a); ok {
+			return strings.Compare(x, y), true
+		}
+	}
+	return 0, false
+}
+
+func numericOf(v any) (*apd.Decimal, bool) {
+	switch x := v.(type) {
+	case Int:
+		return apd.NewWithBigInt(new(apd.BigInt).SetMathBigInt(x.V), 0), true
+	case Decimal:
+		return &x.D, true
+	case int:
+		return apd.New(int64(x), 0), true
+	case int64:
+		return apd.New(x, 0), true
+	case float64:
+		d, _, err := apd.NewFromString(strconv.FormatFloat(x, 'g', -1, 64))
+		return d, err == nil
+	}
+	return nil, false
+}
+
+func timestampOf(v, other any) (time.Time, bool) {
+	var s string
+	switch x := v.(type) {
+	case Timestamp:
+		s = x.RFC3339
+	case string:
+		if _, ok := other.(Timestamp); !ok {
+			return time.Time{}, false
+		}
+		s = x
+	default:
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	return t, err == nil
+}
+
+func orderedString(v, other any) (string, bool) {
+	switch x := v.(type) {
+	case Date:
+		return x.YYYYMMDD, true
+	case Time:
+		return x.HHMMSS, true
+	case string:
+		switch other.(type) {
+		case string, Date, Time:
+			return x, true
+		}
+	}
+	return "", false
+}
+
+// Parser.
+
+type qparser struct {
+	s string
+	i int
+}
+
+func (p *qparser) errorf(format string, args ...any) error {
+	return fmt.Errorf("jolt: query %q: %s at offset %d", p.s, fmt.Sprintf(format, args...), p.i)
+}
+
+func (p *qparser) at(off int) byte {
+	if p.i+off < len(p.s) {
+		return p.s[p.i+off]
+	}
+	return 0
+}
+
+func (p *qparser) peek() byte { return p.at(0) }
+
+func (p *qparser) space() {
+	for p.i < len(p.s) && strings.IndexByte(" \t\r\n", p.s[p.i]) >= 0 {
+		p.i++
+	}
+}
+
+func (p *qparser) eat(tok string) bool {
+	p.space()
+	if strings.HasPrefix(p.s[p.i:], tok) {
+		p.i += len(tok)
+		return true
+	}
+	return false
+}
+
+func isNameByte(c byte) bool {
+	return c == '_' || c == '$' || c == '-' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+func (p *qparser) name() string {
+	start := p.i
+	for p.i < len(p.s) && isNameByte(p.s[p.i]) {
+		p.i++
+	}
+	return p.s[start:p.i]
+}
+
+// steps parses "." and "[" steps until neither follows.
+func (p *qparser) steps() ([]qstep, error) {
+	var steps []qstep
+	for {
+		var st qstep
+		switch {
+		case strings.HasPrefix(p.s[p.i:], ".."):
+			p.i += 2
+			st.deep = true
+			if p.peek() == '[' {
+				if err := p.bracket(&st); err != nil {
+					return nil, err
+				}
+				break
+			}
+			if err := p.dotted(&st); err != nil {
+				return nil, err
+			}
+		case p.peek() == '.':
+			p.i++
+			if err := p.dotted(&st); err != nil {
+				return nil, err
+			}
+		case p.peek() == '[':
+			if err := p.bracket(&st); err != nil {
+				return nil, err
+			}
+		default:
+			return steps, nil
+		}
+		steps = append(steps, st)
+	}
+}
+
+func (p *qparser) dotted(st *qstep) error {
+	if p.peek() == '*' {
+		p.i++
+		st.kind = qWild
+		return nil
+	}
+	if st.name = p.name(); st.name == "" {
+		return p.errorf("expected a field name")
+	}
+	st.kind = qField
+	return nil
+}
+
+func (p *qparser) bracket(st *qstep) error {
+	p.i++ // [
+	p.space()
+	switch c := p.peek(); {
+	case c == '*':
+		p.i++
+		st.kind = qWild
+	case c == '\'' || c == '"':
+		s, err := p.quoted()
+		if err != nil {
+			return err
+		}
+		st.kind, st.name = qField, s
+	case c == '?':
+		p.i++
+		if !p.eat("(") {
+			return p.errorf("expected ( after ?")
+		}
+		e, err := p.or()
+		if err != nil {
+			return err
+		}
+		if !p.eat(")") {
+			return p.errorf("expected )")
+		}
+		st.kind, st.filter = qFilter, e
+	case c == '-' || c >= '0' && c <= '9':
+		start := p.i
+		p.i++
+		for p.peek() >= '0' && p.peek() <= '9' {
+			p.i++
+		}
+		n, err := strconv.Atoi(p.s[start:p.i])
+		if err != nil {
+			return p.errorf("bad index %q", p.s[start:p.i])
+		}
+		st.kind, st.index = qIndex, n
+	default:
+		return p.errorf("expected an index, name, * or filter")
+	}
+	if !p.eat("]") {
+		return p.errorf("expected ]")
+	}
+	return nil
+}
+
+func (p *qparser) quoted() (string, error) {
+	q := p.peek()
+	p.i++
+	var b strings.Builder
+	for p.i < len(p.s) {
+		c := p.s[p.i]
+		p.i++
+		switch {
+		case c == q:
+			return b.String(), nil
+		case c == '\\' && p.i < len(p.s):
+			b.WriteByte(p.s[p.i])
+			p.i++
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+func (p *qparser) or() (qexpr, error) {
+	a, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for p.eat("||") {
+		b, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		a = qor{a, b}
+	}
+	return a, nil
+}
+
+func (p *qparser) and() (qexpr, error) {
+	a, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for p.eat("&&") {
+		b, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		a = qand{a, b}
+	}
+	return a, nil
+}
+
+func (p *qparser) unary() (qexpr, error) {
+	if p.eat("!") {
+		x, err := p.unary()
+		return qnot{x}, err
+	}
+	if p.eat("(") {
+		e, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if !p.eat(")") {
+			return nil, p.errorf("expected )")
+		}
+		return e, nil
+	}
+	l, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+	p.space()
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">", "in"} {
+		if strings.HasPrefix(p.s[p.i:], op) && (op != "in" || !isNameByte(p.at(2))) {
+			p.i += len(op)
+			r, err := p.operand()
+			if err != nil {
+				return nil, err
+			}
+			return qcmp{op: op, l: l, r: r}, nil
+		}
+	}
+	return qexists{l}, nil
+}
+
+var typedLiterals = map[string]func(string) (any, error){
+	"dec": func(s string) (any, error) { return DecFromString(s) },
+	"int": func(s string) (any, error) { return IntFromString(s) },
+	"ts": func(s string) (any, error) {
+		_, err := time.Parse(time.RFC3339Nano, s)
+		return Timestamp{RFC3339: s}, err
+	},
+	"date": func(s string) (any, error) { return Date{YYYYMMDD: s}, nil },
+	"time": func(s string) (any, error) { return Time{HHMMSS: s}, nil },
+	"uuid": func(s string) (any, error) { return UUIDFromString(s) },
+}
+
+func (p *qparser) operand() (qoperand, error) {
+	p.space()
+	switch c := p.peek(); {
+	case c == '@' || c == '$':
+		p.i++
+		steps, err := p.steps()
+		if err != nil {
+			return qoperand{}, err
+		}
+		return qoperand{path: &PathExpr{steps: steps}, rel: c == '@'}, nil
+	case c == '\'' || c == '"':
+		s, err := p.quoted()
+		return qoperand{lit: s}, err
+	case c == '-' || c >= '0' && c <= '9':
+		start := p.i
+		p.i++
+		for p.i < len(p.s) && strings.IndexByte("0123456789.eE+-", p.s[p.i]) >= 0 {
+			p.i++
+		}
+		v, err := numberFromString(p.s[start:p.i])
+		if err != nil {
+			return qoperand{}, p.errorf("bad number %q", p.s[start:p.i])
+		}
+		return qoperand{lit: v}, nil
+	}
+	word := p.name()
+	switch word {
+	case "true", "false":
+		return qoperand{lit: word == "true"}, nil
+	case "null":
+		return qoperand{}, nil
+	}
+	if mk, ok := typedLiterals[word]; ok && p.eat("(") {
+		p.space()
+		if c := p.peek(); c != '\'' && c != '"' {
+			return qoperand{}, p.errorf("%s() takes a quoted string", word)
+		}
+		s, err := p.quoted()
+		if err != nil {
+			return qoperand{}, err
+		}
+		if !p.eat(")") {
+			return qoperand{}, p.errorf("expected )")
+		}
+		v, err := mk(s)
+		if err != nil {
+			return qoperand{}, p.errorf("%s(%q): %v", word, s, err)
+		}
+		return qoperand{lit: v}, nil
+	}
+	return qoperand{}, p.errorf("expected a value")
+}
@@ -0,0 +1,384 @@
+// Package redact masks, hashes, truncates or drops fields of JOLT documents
+// on their way out, following a policy usually written as JSONC:
+//
+//	{
+//	  "version": "2025-03",
+//	  "rules": [
+//	    { "path": "$body.pii.email", "action": "hash" },
+//	    { "path": "$body.pii.phones.*", "action": "mask", "keep": 2 },
+//	    { "field": "street", "action": "drop" },
+//	    { "annotation": "pii", "action": "mask" }
+//	  ]
+//	}
+//
+// A rule selects values by path pattern, by field name anywhere in the
+// document, or by an annotation on the value's schema (Policy.Schema). The
+// first matching rule wins. Values inside Sets, Maps and envelope bodies are
+// reached like any other, and the policy version is recorded in the
+// envelope's $meta under "redaction".
+package redact
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/chandan-cmd-dev/jolt-go/jolt"
+	"github.com/chandan-cmd-dev/jolt-go/joltschema"
+)
+
+// Actions a rule can take.
+const (
+	Mask     = "mask"     // replace the characters of a string with '*', leaving the last Keep visible; other values become "****"
+	Hash     = "hash"     // replace with a keyed HMAC-SHA256 of the value's canonical JOLT-B encoding
+	Truncate = "truncate" // keep the first Keep characters of a string or bytes of a Binary
+	Drop     = "drop"     // remove the field, element, member or entry
+)
+
+// MetaKey is the $meta key recording the version of the policy applied.
+const MetaKey = "redaction"
+
+// HashPrefix starts every hashed value.
+const HashPrefix = "hmac-sha256:"
+
+// Rule selects values by exactly one of Path, Field or Annotation.
+//
+// Path is a dot-separated pattern matched against the whole path of a value:
+// "*" matches one field, index, Set member or Map key and "**" any number of
+// them, so "$body.lines.*.sku" and "**.email" both work. Envelopes and
+// {"$meta","$body"} objects are addressed through "$meta" and "$body".
+//
+// Field matches any object field (or string Map key) with that name.
+//
+// Annotation matches values whose schema, found by following the value's
+// path through Policy.Schema, has that key set in its Extra annotations
+// (e.g. "pii": true). The schema describes envelope bodies.
+type Rule struct {
+	Path       string `jolt:"path,omitempty"`
+	Field      string `jolt:"field,omitempty"`
+	Annotation string `jolt:"annotation,omitempty"`
+	Action     string `jolt:"action"`
+	Keep       int    `jolt:"keep,omitempty"`
+
+	pattern Pattern
+}
+
+// Policy is an ordered list of rules. Key is the HMAC key for Hash rules and
+// Schema the schema Annotation rules consult; both are set by the caller,
+// never read from the policy document.
+type Policy struct {
+	Version string `jolt:"version"`
+	Rules   []Rule `jolt:"rules"`
+
+	Key    []byte             `jolt:"-"`
+	Schema *joltschema.Schema `jolt:"-"`
+}
+
+// ParsePolicy reads a policy from JSONC.
+func ParsePolicy(data []byte) (*Policy, error) {
+	v, err := jolt.UnmarshalJSONTyped(data)
+	if err != nil {
+		return nil, err
+	}
+	var p Policy
+	if err := jolt.DecodeInto(v, &p); err != nil {
+		return nil, err
+	}
+	if err := p.Compile(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Compile checks the rules; ParsePolicy calls it, policies built in Go must
+// call it before use.
+func (p *Policy) Compile() error {
+	for i := range p.Rules {
+		r := &p.Rules[i]
+		set := 0
+		for _, s := range []string{r.Path, r.Field, r.Annotation} {
+			if s != "" {
+				set++
+			}
+		}
+		if set != 1 {
+			return fmt.Errorf("redact: rule %d needs exactly one of path, field or annotation", i)
+		}
+		switch r.Action {
+		case Mask, Hash, Truncate, Drop:
+		default:
+			return fmt.Errorf("redact: rule %d: unknown action %q", i, r.Action)
+		}
+		if r.Keep < 0 {
+			return fmt.Errorf("redact: rule %d: keep must not be negative", i)
+		}
+		if r.Path != "" {
+			r.pattern = ParsePattern(r.Path)
+		}
+	}
+	return nil
+}
+
+var errNoKey = errors.New("redact: hash rules need Policy.Key")
+
+// Apply returns a redacted copy of v, a decoded document.
+func (p *Policy) Apply(v any) (any, error) {
+	_, err := jolt.AsEnvelope(v)
+	env := err == nil
+	return jolt.Transform(v, func(path jolt.Path, x any) (any, error) {
+		if r := p.match(path, env); r != nil {
+			if isContainer(x) {
+				// Transform hands over the rebuilt container; act on the
+				// original so Hash agrees with ApplyBinary.
+				x, _ = jolt.Get[any](v, path...)
+			}
+			return p.act(r, x)
+		}
+		if env && isMeta(path) && p.Version != "" {
+			m, ok := x.(map[string]any)
+			if ok {
+				m[MetaKey] = p.Version
+			}
+		}
+		return x, nil
+	})
+}
+
+// ApplyBinary redacts stored JOLT-B, rewriting only what the rules touch.
+func (p *Policy) ApplyBinary(jb []byte) ([]byte, error) {
+	view, err := jolt.NewView(jb)
+	if err != nil {
+		return nil, err
+	}
+	_, env := view.Field("$meta")
+	return jolt.TransformBinary(jb, func(path jolt.Path, x any) (any, error) {
+		if r := p.match(path, env); r != nil {
+			return p.act(r, x)
+		}
+		if env && isMeta(path) {
+			if mv, ok := x.(jolt.View); ok {
+				// Returning the decoded meta stops the walk there, so the
+				// rules below $meta are applied to it here.
+				d, err := mv.Decode()
+				if err != nil {
+					return nil, err
+				}
+				d, err = p.applyMeta(d)
+				if err != nil {
+					return nil, err
+				}
+				m, ok := d.(map[string]any)
+				if !ok {
+					return d, nil
+				}
+				// Round trip through Meta so a dropped named field reads
+				// back as Apply's envelope writes it.
+				meta, err := jolt.MetaFromMap(m)
+				if err != nil {
+					return nil, err
+				}
+				m = meta.ToMap()
+				if p.Version != "" {
+					m[MetaKey] = p.Version
+				}
+				return m, nil
+			}
+		}
+		return x, nil
+	})
+}
+
+// applyMeta redacts a decoded $meta object as Apply would inside an envelope.
+func (p *Policy) applyMeta(meta any) (any, error) {
+	return jolt.Transform(meta, func(sub jolt.Path, x any) (any, error) {
+		if len(sub) == 0 {
+			return x, nil
+		}
+		if r := p.match(append(jolt.Path{"$meta"}, sub...), true); r != nil {
+			if isContainer(x) {
+				x, _ = jolt.Get[any](meta, sub...)
+			}
+			return p.act(r, x)
+		}
+		return x, nil
+	})
+}
+
+func isMeta(path jolt.Path) bool { return len(path) == 1 && path[0] == "$meta" }
+
+func isContainer(v any) bool {
+	switch v.(type) {
+	case map[string]any, []any, jolt.Set, jolt.Map, jolt.Envelope:
+		return true
+	}
+	return false
+}
+
+// match returns the first rule selecting the value at path.
+func (p *Policy) match(path jolt.Path, env bool) *Rule {
+	for i := range p.Rules {
+		r := &p.Rules[i]
+		switch {
+		case r.pattern != nil:
+			if r.pattern.Match(path) {
+				return r
+			}
+		case r.Field != "":
+			if len(path) > 0 && path[len(path)-1] == r.Field {
+				return r
+			}
+		case r.Annotation != "":
+			if s := p.schemaAt(path, env); s != nil {
+				if a, ok := s.Extra[r.Annotation]; ok && a != false && a != nil {
+					return r
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Pattern is a parsed path pattern, as in Rule.Path.
+type Pattern []string
+
+// ParsePattern splits a dot-separated pattern. A leading "$." (or a lone
+// "$") is the root; "$body" and "$meta" are field names.
+func ParsePattern(s string) Pattern {
+	if s == "$" {
+		return Pattern{}
+	}
+	return strings.Split(strings.TrimPrefix(s, "$."), ".")
+}
+
+// Match reports whether the pattern matches all of path.
+func (p Pattern) Match(path jolt.Path) bool {
+	if len(p) == 0 {
+		return len(path) == 0
+	}
+	if p[0] == "**" {
+		for i := 0; i <= len(path); i++ {
+			if p[1:].Match(path[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(path) == 0 || !matchSegment(p[0], path[0]) {
+		return false
+	}
+	return p[1:].Match(path[1:])
+}
+
+// Prefix reports whether path leads towards values the pattern matches,
+// i.e. whether some extension of path (or path itself) could match.
+func (p Pattern) Prefix(path jolt.Path) bool {
+	if len(path) == 0 {
+		return true
+	}
+	if len(p) == 0 {
+		return false
+	}
+	if p[0] == "**" {
+		return true
+	}
+	return matchSegment(p[0], path[0]) && p[1:].Prefix(path[1:])
+}
+
+func matchSegment(pat string, seg any) bool {
+	if pat == "*" {
+		_, isKey := seg.(jolt.MapKey)
+		return !isKey
+	}
+	switch s := seg.(type) {
+	case string:
+		return s == pat
+	case int:
+		return strconv.Itoa(s) == pat
+	}
+	return false
+}
+
+// schemaAt follows path through p.Schema.
+func (p *Policy) schemaAt(path jolt.Path, env bool) *joltschema.Schema {
+	if p.Schema == nil {
+		return nil
+	}
+	if env {
+		if len(path) == 0 || path[0] != "$body" {
+			return nil
+		}
+		path = path[1:]
+	}
+	s := p.Schema.Resolve()
+	for _, seg := range path {
+		switch x := seg.(type) {
+		case string:
+			if s.Type == joltschema.Map {
+				s = s.Values
+			} else {
+				s = s.Fields[x]
+			}
+		case int, jolt.SetMember:
+			s = s.Items
+		case jolt.MapKey:
+			s = s.Keys
+		default:
+			s = s.Values
+		}
+		if s == nil {
+			return nil
+		}
+		s = s.Resolve()
+	}
+	return s
+}
+
+func (p *Policy) act(r *Rule, v any) (any, error) {
+	switch r.Action {
+	case Drop:
+		return nil, jolt.Remove
+	case Mask:
+		s, ok := v.(string)
+		if !ok {
+			return "****", nil
+		}
+		rs := []rune(s)
+		for i := 0; i < len(rs)-r.Keep; i++ {
+			rs[i] = '*'
+		}
+		return string(rs), nil
+	case Truncate:
+		switch x := v.(type) {
+		case string:
+			if rs := []rune(x); len(rs) > r.Keep {
+				return string(rs[:r.Keep]), nil
+			}
+		case jolt.Binary:
+			if len(x) > r.Keep {
+				return x[:r.Keep], nil
+			}
+		}
+		return v, nil
+	case Hash:
+		if len(p.Key) == 0 {
+			return nil, errNoKey
+		}
+		var b []byte
+		if vw, ok := v.(jolt.View); ok {
+			b = vw.Raw()
+		} else {
+			var err error
+			if b, err = jolt.EncodeBinary(v); err != nil {
+				return nil, err
+			}
+		}
+		mac := hmac.New(sha256.New, p.Key)
+		mac.Write(b)
+		return HashPrefix + base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+	}
+	return v, nil
+}
@@ -0,0 +1,805 @@
+package jolt
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// JOLT-T is a text notation for JOLT values, meant for fixtures and humans:
+//
+//	// comments: // to end of line, or /* ... */
+//	$meta { type: "urn:jolt:example/Order", version: "2.1.0" }
+//	$body {
+//	  number: "SO-12988",
+//	  qty: 2,                          // Int; 123456789012345678901n also works
+//	  price: 1999.95d,                 // Decimal; a bare 1999.95 is one too
+//	  id: uuid"0f8fad5b-d9cb-469f-a165-70867728950e",
+//	  at: ts"2025-08-08T10:00:00Z",    // also date"…", time"…", link"…"
+//	  scan: b64"AAEC",
+//	  tags: #{"gift", "rush"},         // Set
+//	  stock: map{7 => "A-1"},          // Map with any keys
+//	  note: @"checked",                // Annot (@word for a single word)
+//	}
+//
+// Object keys are bare words or strings, commas may trail, and strings take
+// JSON escapes plus \xHH for bytes that are not UTF-8. A leading $meta
+// object followed by $body is an Envelope; nested envelopes are written
+// env({…}, body). Registered extensions appear as name(representation) and
+// unregistered ones as ext(tag, b64"payload").
+
+// SyntaxError reports malformed text input, with a 1-based line and column
+// (counted in bytes).
+type SyntaxError struct {
+	Line, Col int
+	Msg       string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("jolt: line %d, col %d: %s", e.Line, e.Col, e.Msg)
+}
+
+// syntaxErrorAt builds a SyntaxError for byte offset off of src.
+func syntaxErrorAt(src []byte, off int, format string, args ...any) *SyntaxError {
+	if off > len(src) {
+		off = len(src)
+	}
+	line := 1 + bytes.Count(src[:off], []byte{'\n'})
+	col := off - bytes.LastIndexByte(src[:off], '\n')
+	return &SyntaxError{Line: line, Col: col, Msg: fmt.Sprintf(format, args...)}
+}
+
+// ParseText parses a JOLT-T document.
+func ParseText(data []byte) (any, error) {
+	p := &textParser{s: data}
+	p.space()
+	var v any
+	var err error
+	if p.word() == "$meta" {
+		v, err = p.header()
+	} else {
+		v, err = p.value(0)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if p.space(); p.i < len(p.s) {
+		return nil, p.errf("unexpected %q after value", p.s[p.i])
+	}
+	return v, nil
+}
+
+type textParser struct {
+	s   []byte
+	i   int
+	err error // from space, for unterminated comments
+}
+
+func (p *textParser) errf(format string, args ...any) error {
+	return syntaxErrorAt(p.s, p.i, format, args...)
+}
+
+// space skips whitespace and comments.
+func (p *textParser) space() {
+	for p.i < len(p.s) {
+		switch c := p.s[p.i]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			p.i++
+		case c == '/' && p.i+1 < len(p.s) && p.s[p.i+1] == '/':
+			for p.i < len(p.s) && p.s[p.i] != '\n' {
+				p.i++
+			}
+		case c == '/' && p.i+1 < len(p.s) && p.s[p.i+1] == '*':
+			end := bytes.Index(p.s[p.i+2:], []byte("*/"))
+			if end < 0 {
+				p.err = p.errf("unterminated comment")
+				p.i = len(p.s)
+				return
+			}
+			p.i += end + 4
+		default:
+			return
+		}
+	}
+}
+
+// word returns the bare word at p.i without consuming it.
+func (p *textParser) word() string {
+	j := p.i
+	for j < len(p.s) && isNameByte(p.s[j]) && p.s[j] != '-' {
+		j++
+	}
+	return string(p.s[p.i:j])
+}
+
+func (p *textParser) expect(c byte) error {
+	if p.space(); p.err != nil {
+		return p.err
+	}
+	if p.i >= len(p.s) || p.s[p.i] != c {
+		return p.unexpected(fmt.Sprintf("%q", c))
+	}
+	p.i++
+	return nil
+}
+
+func (p *textParser) unexpected(want string) error {
+	if p.err != nil {
+		return p.err
+	}
+	if p.i >= len(p.s) {
+		return p.errf("unexpected end of input, want %s", want)
+	}
+	r, _ := utf8.DecodeRune(p.s[p.i:])
+	return p.errf("unexpected %q, want %s", r, want)
+}
+
+// header parses "$meta {…} $body value" into an Envelope.
+func (p *textParser) header() (any, error) {
+	p.i += len("$meta")
+	p.space()
+	start := p.i
+	m, err := p.value(1)
+	if err != nil {
+		return nil, err
+	}
+	env, err := p.envelope(start, m)
+	if err != nil {
+		return nil, err
+	}
+	if p.space(); p.word() == "$body" {
+		p.i += len("$body")
+		p.space()
+		if env.Body, err = p.value(1); err != nil {
+			return nil, err
+		}
+	}
+	return env, nil
+}
+
+func (p *textParser) envelope(at int, m any) (Envelope, error) {
+	mm, ok := m.(map[string]any)
+	if !ok {
+		return Envelope{}, syntaxErrorAt(p.s, at, "$meta must be an object")
+	}
+	meta, err := MetaFromMap(mm)
+	if err != nil {
+		return Envelope{}, syntaxErrorAt(p.s, at, "%v", err)
+	}
+	return Envelope{Meta: meta}, nil
+}
+
+func (p *textParser) value(depth int) (any, error) {
+	if depth > DefaultLimits.MaxDepth {
+		return nil, ErrTooDeep
+	}
+	if p.space(); p.err != nil {
+		return nil, p.err
+	}
+	if p.i >= len(p.s) {
+		return nil, p.unexpected("a value")
+	}
+	switch c := p.s[p.i]; {
+	case c == '{':
+		p.i++
+		return p.object(depth)
+	case c == '[':
+		p.i++
+		out := []any{}
+		err := p.list(']', func() error {
+			v, err := p.value(depth + 1)
+			out = append(out, v)
+			return err
+		})
+		return out, err
+	case c == '#':
+		p.i++
+		if p.i >= len(p.s) || p.s[p.i] != '{' {
+			return nil, p.unexpected(`"{" after "#"`)
+		}
+		p.i++
+		out := Set{}
+		err := p.list('}', func() error {
+			v, err := p.value(depth + 1)
+			out = append(out, v)
+			return err
+		})
+		return out, err
+	case c == '"':
+		return p.str()
+	case c == '@':
+		p.i++
+		if p.i < len(p.s) && p.s[p.i] == '"' {
+			s, err := p.str()
+			return Annot{Note: s}, err
+		}
+		w := p.word()
+		if w == "" {
+			return nil, p.unexpected("a note after \"@\"")
+		}
+		p.i += len(w)
+		return Annot{Note: w}, nil
+	case c == '-' || c >= '0' && c <= '9':
+		return p.number()
+	}
+	w := p.word()
+	if w == "" {
+		return nil, p.unexpected("a value")
+	}
+	start := p.i
+	p.i += len(w)
+	if p.i < len(p.s) && p.s[p.i] == '"' {
+		return p.typedString(start, w)
+	}
+	switch w {
+	case "null":
+		return nil, nil
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+	if n := extNameLen(p.s[start:]); n > len(w) {
+		// Extension names may also use '-', ':', '.' and '/'.
+		w, p.i = string(p.s[start:start+n]), start+n
+	}
+	p.space()
+	if w == "map" && p.i < len(p.s) && p.s[p.i] == '{' {
+		p.i++
+		return p.mapValue(depth)
+	}
+	if p.i < len(p.s) && p.s[p.i] == '(' {
+		p.i++
+		return p.call(start, w, depth)
+	}
+	return nil, syntaxErrorAt(p.s, start, "unknown word %q", w)
+}
+
+// list parses comma-separated items up to close, allowing a trailing comma.
+func (p *textParser) list(close byte, item func() error) error {
+	for {
+		if p.space(); p.err != nil {
+			return p.err
+		}
+		if p.i < len(p.s) && p.s[p.i] == close {
+			p.i++
+			return nil
+		}
+		if err := item(); err != nil {
+			return err
+		}
+		if p.space(); p.i < len(p.s) && p.s[p.i] == ',' {
+			p.i++
+			continue
+		}
+		if p.i < len(p.s) && p.s[p.i] == close {
+			p.i++
+			return nil
+		}
+		return p.unexpected(fmt.Sprintf("\",\" or %q", close))
+	}
+}
+
+func (p *textParser) object(depth int) (any, error) {
+	out := map[string]any{}
+	err := p.list('}', func() error {
+		start := p.i
+		var k string
+		if p.i >= len(p.s) {
+			return p.unexpected("a key")
+		}
+		if p.s[p.i] == '"' {
+			var err error
+			if k, err = p.str(); err != nil {
+				return err
+			}
+		} else {
+			j := p.i
+			for j < len(p.s) && isNameByte(p.s[j]) {
+				j++
+			}
+			if j == p.i {
+				return p.unexpected("a key")
+			}
+			k, p.i = string(p.s[p.i:j]), j
+		}
+		if _, dup := out[k]; dup {
+			return syntaxErrorAt(p.s, start, "duplicate key %q", k)
+		}
+		if err := p.expect(':'); err != nil {
+			return err
+		}
+		v, err := p.value(depth + 1)
+		out[k] = v
+		return err
+	})
+	return out, err
+}
+
+func (p *textParser) mapValue(depth int) (any, error) {
+	out := Map{}
+	err := p.list('}', func() error {
+		start := p.i
+		k, err := p.value(depth + 1)
+		if err != nil {
+			return err
+		}
+		if k != nil && !reflect.TypeOf(k).Comparable() {
+			return syntaxErrorAt(p.s, start, "map key of type %T is not usable as a key", k)
+		}
+		if _, dup := mapFind(out, k); dup {
+			return syntaxErrorAt(p.s, start, "duplicate map key")
+		}
+		if p.space(); !bytes.HasPrefix(p.s[p.i:], []byte("=>")) {
+			return p.unexpected(`"=>"`)
+		}
+		p.i += 2
+		v, err := p.value(depth + 1)
+		out[k] = v
+		return err
+	})
+	return out, err
+}
+
+// call parses env(meta, body), ext(tag, b64"…") and name(repr) for
+// registered extensions; the opening parenthesis is consumed.
+func (p *textParser) call(start int, name string, depth int) (any, error) {
+	var args []any
+	var at []int
+	err := p.list(')', func() error {
+		at = append(at, p.i)
+		v, err := p.value(depth + 1)
+		args = append(args, v)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	switch name {
+	case "env":
+		if len(args) != 2 {
+			return nil, syntaxErrorAt(p.s, start, "env takes $meta and $body")
+		}
+		env, err := p.envelope(at[0], args[0])
+		env.Body = args[1]
+		return env, err
+	case "ext":
+		if len(args) != 2 {
+			return nil, syntaxErrorAt(p.s, start, "ext takes a tag and a payload")
+		}
+		tag, ok := args[0].(Int)
+		if !ok || tag.V.Sign() < 0 || !tag.V.IsUint64() {
+			return nil, syntaxErrorAt(p.s, at[0], "bad extension tag")
+		}
+		raw, ok := args[1].(Binary)
+		if !ok {
+			return nil, syntaxErrorAt(p.s, at[1], "extension payload must be b64\"…\"")
+		}
+		v, err := decodeExtension(tag.V.Uint64(), raw, depth)
+		if err != nil {
+			return nil, syntaxErrorAt(p.s, start, "%v", err)
+		}
+		return v, nil
+	}
+	e := extensionForName(name)
+	if e == nil {
+		return nil, syntaxErrorAt(p.s, start, "unknown extension %q", name)
+	}
+	if len(args) != 1 {
+		return nil, syntaxErrorAt(p.s, start, "%s takes one value", name)
+	}
+	v, err := e.dec(args[0])
+	if err != nil {
+		return nil, syntaxErrorAt(p.s, start, "extension %q: %v", name, err)
+	}
+	return v, nil
+}
+
+// extNameLen is the length of the extension name at the start of b.
+func extNameLen(b []byte) int {
+	n := 0
+	for n < len(b) && (isNameByte(b[n]) || b[n] == ':' || b[n] == '.' || b[n] == '/') {
+		n++
+	}
+	return n
+}
+
+func (p *textParser) typedString(start int, prefix string) (any, error) {
+	s, err := p.str()
+	if err != nil {
+		return nil, err
+	}
+	switch prefix {
+	case "uuid":
+		u, err := UUIDFromString(s)
+		if err != nil {
+			return nil, syntaxErrorAt(p.s, start, "bad uuid %q", s)
+		}
+		return u, nil
+	case "ts":
+		return Timestamp{RFC3339: s}, nil
+	case "date":
+		return Date{YYYYMMDD: s}, nil
+	case "time":
+		return Time{HHMMSS: s}, nil
+	case "link":
+		return Link{Ref: s}, nil
+	case "b64":
+		b, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return nil, syntaxErrorAt(p.s, start, "bad base64: %v", err)
+		}
+		return Binary(b), nil
+	}
+	return nil, syntaxErrorAt(p.s, start, "unknown string prefix %q", prefix)
+}
+
+func (p *textParser) number() (any, error) {
+	start := p.i
+	digits := func() bool {
+		j := p.i
+		for p.i < len(p.s) && p.s[p.i] >= '0' && p.s[p.i] <= '9' {
+			p.i++
+		}
+		return p.i > j
+	}
+	if p.s[p.i] == '-' {
+		p.i++
+	}
+	if !digits() {
+		return nil, p.unexpected("a digit")
+	}
+	integral := true
+	if p.i < len(p.s) && p.s[p.i] == '.' {
+		p.i++
+		integral = false
+		if !digits() {
+			return nil, p.unexpected("a digit")
+		}
+	}
+	if p.i < len(p.s) && (p.s[p.i] == 'e' || p.s[p.i] == 'E') {
+		p.i++
+		integral = false
+		if p.i < len(p.s) && (p.s[p.i] == '+' || p.s[p.i] == '-') {
+			p.i++
+		}
+		if !digits() {
+			return nil, p.unexpected("a digit")
+		}
+	}
+	lit := string(p.s[start:p.i])
+	suffix := byte(0)
+	if p.i < len(p.s) && (p.s[p.i] == 'n' || p.s[p.i] == 'd') {
+		suffix = p.s[p.i]
+		p.i++
+	}
+	if p.i < len(p.s) && isNameByte(p.s[p.i]) {
+		return nil, p.errf("bad number suffix")
+	}
+	switch {
+	case suffix == 'n' && !integral:
+		return nil, syntaxErrorAt(p.s, start, "%s is not an integer", lit)
+	case suffix == 'd' || !integral:
+		d, err := DecFromString(lit)
+		if err != nil {
+			return nil, syntaxErrorAt(p.s, start, "bad decimal %s", lit)
+		}
+		return d, nil
+	}
+	z, _ := new(big.Int).SetString(lit, 10)
+	return Int{V: z}, nil
+}
+
+// str parses a double-quoted string at p.i.
+func (p *textParser) str() (string, error) {
+	start := p.i
+	p.i++ // opening quote
+	var b []byte
+	for {
+		if p.i >= len(p.s) {
+			return "", syntaxErrorAt(p.s, start, "unterminated string")
+		}
+		c := p.s[p.i]
+		switch {
+		case c == '"':
+			p.i++
+			return string(b), nil
+		case c == '\n':
+			return "", p.errf("newline in string")
+		case c != '\\':
+			b = append(b, c)
+			p.i++
+			continue
+		}
+		if p.i+1 >= len(p.s) {
+			return "", syntaxErrorAt(p.s, start, "unterminated string")
+		}
+		esc := p.s[p.i+1]
+		p.i += 2
+		switch esc {
+		case '"', '\\', '/':
+			b = append(b, esc)
+		case 'b':
+			b = append(b, '\b')
+		case 'f':
+			b = append(b, '\f')
+		case 'n':
+			b = append(b, '\n')
+		case 'r':
+			b = append(b, '\r')
+		case 't':
+			b = append(b, '\t')
+		case 'x':
+			n, err := p.hex(2)
+			if err != nil {
+				return "", err
+			}
+			b = append(b, byte(n))
+		case 'u':
+			n, err := p.hex(4)
+			if err != nil {
+				return "", err
+			}
+			r := rune(n)
+			if utf16.IsSurrogate(r) && bytes.HasPrefix(p.s[p.i:], []byte(`\u`)) {
+				p.i += 2
+				lo, err := p.hex(4)
+				if err != nil {
+					return "", err
+				}
+				r = utf16.DecodeRune(r, rune(lo))
+			}
+			b = utf8.AppendRune(b, r)
+		default:
+			p.i--
+			return "", p.errf("bad escape \\%c", esc)
+		}
+	}
+}
+
+func (p *textParser) hex(n int) (uint64, error) {
+	if p.i+n > len(p.s) {
+		return 0, p.errf("short escape")
+	}
+	v, err := strconv.ParseUint(string(p.s[p.i:p.i+n]), 16, 32)
+	if err != nil {
+		return 0, p.errf("bad hex escape %q", p.s[p.i:p.i+n])
+	}
+	p.i += n
+	return v, nil
+}
+
+// FormatText renders v as JOLT-T. Values are written as their canonical
+// JOLT-B encoding would decode, so Go structs and registered extensions are
+// rendered through their JOLT form; ParseText gives back a value with the
+// same encoding.
+func FormatText(v any) ([]byte, error) {
+	jb, err := EncodeBinary(v)
+	if err != nil {
+		return nil, err
+	}
+	return FormatTextBinary(jb)
+}
+
+// FormatTextBinary renders the JOLT-B value jb as JOLT-T.
+func FormatTextBinary(jb []byte) ([]byte, error) {
+	v, err := NewView(jb)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if v.tag() == tagEnv {
+		meta, body := v.envelope()
+		buf.WriteString("$meta ")
+		if err := formatText(&buf, meta, 0); err != nil {
+			return nil, err
+		}
+		buf.WriteString("\n$body ")
+		v = body
+	}
+	if err := formatText(&buf, v, 0); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+func formatText(buf *bytes.Buffer, v View, indent int) error {
+	// block writes n items between open and close, one per line.
+	block := func(open, close string, n int, item func(i int) error) error {
+		buf.WriteString(open)
+		if n == 0 {
+			buf.WriteString(close)
+			return nil
+		}
+		for i := 0; i < n; i++ {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			buf.WriteByte('\n')
+			writeIndent(buf, indent+1)
+			if err := item(i); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('\n')
+		writeIndent(buf, indent)
+		buf.WriteString(close)
+		return nil
+	}
+	switch v.tag() {
+	case tagArr, tagSet:
+		open, close := "[", "]"
+		if v.tag() == tagSet {
+			open, close = "#{", "}"
+		}
+		items := v.items()
+		if line, ok := inlineText(items); ok {
+			buf.WriteString(open + line + close)
+			return nil
+		}
+		return block(open, close, len(items), func(i int) error {
+			return formatText(buf, items[i], indent+1)
+		})
+	case tagObj:
+		fields := v.fields()
+		return block("{", "}", len(fields), func(i int) error {
+			writeTextKey(buf, fields[i].name)
+			buf.WriteString(": ")
+			return formatText(buf, fields[i].val, indent+1)
+		})
+	case tagMap:
+		entries := v.entries()
+		return block("map{", "}", len(entries), func(i int) error {
+			if err := formatText(buf, entries[i][0], indent+1); err != nil {
+				return err
+			}
+			buf.WriteString(" => ")
+			return formatText(buf, entries[i][1], indent+1)
+		})
+	case tagEnv:
+		meta, body := v.envelope()
+		buf.WriteString("env(")
+		if err := formatText(buf, meta, indent); err != nil {
+			return err
+		}
+		buf.WriteString(", ")
+		if err := formatText(buf, body, indent); err != nil {
+			return err
+		}
+		buf.WriteByte(')')
+		return nil
+	case tagExt:
+		tag, pos, err := uvarintAt(v.b, 1)
+		if err != nil {
+			return err
+		}
+		n, pos, err := uvarintAt(v.b, pos)
+		if err != nil {
+			return err
+		}
+		payload := v.b[pos : pos+int(n)]
+		if e := extensionForTag(tag); e != nil && extNameLen([]byte(e.name)) == len(e.name) {
+			if pv, err := NewView(payload); err == nil {
+				buf.WriteString(e.name + "(")
+				if err := formatText(buf, pv, indent); err != nil {
+					return err
+				}
+				buf.WriteByte(')')
+				return nil
+			}
+		}
+		fmt.Fprintf(buf, "ext(%d, b64", tag)
+		writeTextString(buf, base64.StdEncoding.EncodeToString(payload))
+		buf.WriteByte(')')
+		return nil
+	}
+	x, err := v.Decode()
+	if err != nil {
+		return err
+	}
+	switch x := x.(type) {
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		buf.WriteString(strconv.FormatBool(x))
+	case string:
+		writeTextString(buf, x)
+	case Int:
+		buf.WriteString(x.V.String())
+	case Decimal:
+		buf.WriteString(x.String() + "d")
+	case Binary:
+		buf.WriteString("b64")
+		writeTextString(buf, base64.StdEncoding.EncodeToString(x))
+	case UUID:
+		buf.WriteString(`uuid"` + x.String() + `"`)
+	case Timestamp:
+		buf.WriteString("ts")
+		writeTextString(buf, x.RFC3339)
+	case Date:
+		buf.WriteString("date")
+		writeTextString(buf, x.YYYYMMDD)
+	case Time:
+		buf.WriteString("time")
+		writeTextString(buf, x.HHMMSS)
+	case Link:
+		buf.WriteString("link")
+		writeTextString(buf, x.Ref)
+	case Annot:
+		buf.WriteByte('@')
+		writeTextString(buf, x.Note)
+	default:
+		return fmt.Errorf("jolt: cannot format %T as text", x)
+	}
+	return nil
+}
+
+// inlineText renders short lists of scalars on one line.
+func inlineText(items []View) (string, bool) {
+	var line bytes.Buffer
+	for i, it := range items {
+		if isContainerTag(it.tag()) || it.tag() == tagExt {
+			return "", false
+		}
+		if i > 0 {
+			line.WriteString(", ")
+		}
+		if err := formatText(&line, it, 0); err != nil || line.Len() > 60 {
+			return "", false
+		}
+	}
+	return line.String(), true
+}
+
+func writeIndent(buf *bytes.Buffer, n int) {
+	for i := 0; i < n; i++ {
+		buf.WriteString("  ")
+	}
+}
+
+// writeTextKey writes an object key bare when it reads back as one.
+func writeTextKey(buf *bytes.Buffer, k string) {
+	bare := k != "" && utf8.ValidString(k) && k[0] != '-' && (k[0] < '0' || k[0] > '9')
+	for i := 0; bare && i < len(k); i++ {
+		bare = isNameByte(k[i])
+	}
+	if bare {
+		buf.WriteString(k)
+		return
+	}
+	writeTextString(buf, k)
+}
+
+// writeTextString quotes s with JSON escapes, using \xHH for bytes that are
+// not valid UTF-8 so any string survives a round trip.
+func writeTextString(buf *bytes.Buffer, s string) {
+	buf.WriteByte('"')
+	for i := 0; i < len(s); {
+		r, n := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == utf8.RuneError && n == 1:
+			fmt.Fprintf(buf, `\x%02x`, s[i])
+		case r == '"' || r == '\\':
+			buf.WriteByte('\\')
+			buf.WriteByte(byte(r))
+		case r == '\n':
+			buf.WriteString(`\n`)
+		case r == '\r':
+			buf.WriteString(`\r`)
+		case r == '\t':
+			buf.WriteString(`\t`)
+		case r < 0x20 || r == 0x7f:
+			fmt.Fprintf(buf, `\u%04x`, r)
+		default:
+			buf.WriteString(s[i : i+n])
+		}
+		i += n
+	}
+	buf.WriteByte('"')
+}
@@ -0,0 +1,444 @@
+package jolt
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// TranscodeOptions tunes TranscodeJSONToBinaryWith and
+// TranscodeBinaryToJSONWith.
+type TranscodeOptions struct {
+	// NDJSON transcodes a stream of values instead of a single one: JSON
+	// values separated by newlines (or any whitespace) on one side, JOLT-B
+	// frames as written by WriteFrame on the other.
+	NDJSON bool
+	// MaxBuffer is how many bytes of an open array or object are held in
+	// memory before the rest spills to a temporary file; 0 means 4 MiB.
+	MaxBuffer int
+	// TempDir is where spill files go; "" means os.TempDir().
+	TempDir string
+}
+
+const defaultTranscodeBuffer = 4 << 20
+
+// TranscodeJSONToBinary converts one JSON document from src to JOLT-B on dst
+// without building it in memory; see TranscodeJSONToBinaryWith.
+func TranscodeJSONToBinary(dst io.Writer, src io.Reader) error {
+	return TranscodeJSONToBinaryWith(dst, src, TranscodeOptions{})
+}
+
+// TranscodeBinaryToJSON converts one JOLT-B value from src to compact JSON on
+// dst without building it in memory; see TranscodeBinaryToJSONWith.
+func TranscodeBinaryToJSON(dst io.Writer, src io.Reader) error {
+	return TranscodeBinaryToJSONWith(dst, src, TranscodeOptions{})
+}
+
+// TranscodeJSONToBinaryWith writes the bytes EncodeBinary would produce for
+// UnmarshalJSONTyped's result, reading src token by token: "@type" wrappers
+// are lifted and numbers kept exact. JOLT-B prefixes containers with their
+// length and sorts object keys, so each open array or object is buffered
+// until it closes, spilling to disk past opts.MaxBuffer; only the keys of
+// open objects stay in memory. Comments are not accepted.
+func TranscodeJSONToBinaryWith(dst io.Writer, src io.Reader, opts TranscodeOptions) error {
+	dec := json.NewDecoder(src)
+	dec.UseNumber()
+	t := &jsonTranscoder{dec: dec, opts: opts}
+	bw := bufio.NewWriter(dst)
+	if !opts.NDJSON {
+		if err := t.value(bw, 0); err != nil {
+			return err
+		}
+	}
+	for opts.NDJSON && dec.More() {
+		s := t.spool()
+		err := t.value(s, 0)
+		if err == nil {
+			err = putUvarint(bw, uint64(s.n))
+		}
+		if err == nil {
+			err = s.copyTo(bw, 0, s.n)
+		}
+		s.close()
+		if err != nil {
+			return err
+		}
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return fmt.Errorf("jolt: trailing data after JSON value")
+	}
+	return bw.Flush()
+}
+
+// TranscodeBinaryToJSONWith writes what MarshalJSONCompat(v, false) would
+// for the decoded value, streaming arrays, objects and envelope bodies; other
+// values (sets, maps, extensions, scalars) are decoded one at a time. In
+// NDJSON mode each frame becomes one line.
+func TranscodeBinaryToJSONWith(dst io.Writer, src io.Reader, opts TranscodeOptions) error {
+	br := bufio.NewReader(src)
+	bw := bufio.NewWriter(dst)
+	if !opts.NDJSON {
+		if err := binaryToJSON(bw, br, 0); err != nil {
+			return err
+		}
+		if _, err := br.ReadByte(); err != io.EOF {
+			return fmt.Errorf("jolt: trailing data after JOLT-B value")
+		}
+		return bw.Flush()
+	}
+	fr := bufio.NewReader(nil)
+	for {
+		n, err := readUvarint(br)
+		if err == io.EOF {
+			return bw.Flush()
+		}
+		if err != nil {
+			return err
+		}
+		fr.Reset(io.LimitReader(br, int64(n)))
+		if err := binaryToJSON(bw, fr, 0); err != nil {
+			return err
+		}
+		if _, err := fr.ReadByte(); err != io.EOF {
+			return fmt.Errorf("jolt: trailing data in JOLT-B frame")
+		}
+		if err := bw.WriteByte('\n'); err != nil {
+			return err
+		}
+	}
+}
+
+type jsonTranscoder struct {
+	dec  *json.Decoder
+	opts TranscodeOptions
+}
+
+func (t *jsonTranscoder) spool() *spool {
+	max := t.opts.MaxBuffer
+	if max <= 0 {
+		max = defaultTranscodeBuffer
+	}
+	return &spool{max: max, dir: t.opts.TempDir}
+}
+
+// value transcodes the next JSON value to w.
+func (t *jsonTranscoder) value(w io.Writer, depth int) error {
+	if depth > DefaultLimits.MaxDepth {
+		return ErrTooDeep
+	}
+	tok, err := t.dec.Token()
+	if err != nil {
+		return err
+	}
+	switch x := tok.(type) {
+	case json.Delim:
+		if x == '[' {
+			return t.array(w, depth)
+		}
+		return t.object(w, depth)
+	case json.Number:
+		n, err := numberFromString(x.String())
+		if err != nil {
+			return err
+		}
+		return encodeAny(w, n, depth)
+	default:
+		return encodeAny(w, tok, depth)
+	}
+}
+
+func (t *jsonTranscoder) array(w io.Writer, depth int) error {
+	s := t.spool()
+	defer s.close()
+	var n uint64
+	for t.dec.More() {
+		if err := t.value(s, depth+1); err != nil {
+			return err
+		}
+		n++
+	}
+	if _, err := t.dec.Token(); err != nil {
+		return err
+	}
+	if _, err := w.Write([]byte{tagArr}); err != nil {
+		return err
+	}
+	if err := putUvarint(w, n); err != nil {
+		return err
+	}
+	return s.copyTo(w, 0, s.n)
+}
+
+// spooledMember is an object member whose encoded value sits at off in the
+// object's spool.
+type spooledMember struct {
+	key    string
+	off, n int64
+}
+
+func (t *jsonTranscoder) object(w io.Writer, depth int) error {
+	s := t.spool()
+	defer s.close()
+	var ms []spooledMember
+	typeName := ""
+	for t.dec.More() {
+		tok, err := t.dec.Token()
+		if err != nil {
+			return err
+		}
+		k, _ := tok.(string)
+		if k == "$comment" && !PreserveComments {
+			if err := t.value(io.Discard, depth+1); err != nil {
+				return err
+			}
+			continue
+		}
+		m := spooledMember{key: k, off: s.n}
+		if err := t.value(s, depth+1); err != nil {
+			return err
+		}
+		m.n = s.n - m.off
+		ms = append(ms, m)
+		if k == "@type" {
+			v, err := s.decode(m)
+			if err != nil {
+				return err
+			}
+			typeName, _ = v.(string)
+		}
+	}
+	if _, err := t.dec.Token(); err != nil {
+		return err
+	}
+	// Later duplicates win, as they do for encoding/json.
+	sort.SliceStable(ms, func(i, j int) bool { return ms[i].key < ms[j].key })
+	uniq := ms[:0]
+	for i, m := range ms {
+		if i+1 < len(ms) && ms[i+1].key == m.key {
+			continue
+		}
+		uniq = append(uniq, m)
+	}
+	ms = uniq
+	if isWrapperName(typeName) {
+		v, ok, err := s.lift(typeName, ms)
+		if err != nil {
+			return err
+		}
+		if ok {
+			return encodeAny(w, v, depth)
+		}
+	}
+	if _, err := w.Write([]byte{tagObj}); err != nil {
+		return err
+	}
+	if err := putUvarint(w, uint64(len(ms))); err != nil {
+		return err
+	}
+	for _, m := range ms {
+		if err := writeString(w, m.key); err != nil {
+			return err
+		}
+		if err := s.copyTo(w, m.off, m.n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isWrapperName reports whether liftWrapper may lift an object with this
+// "@type"; other objects are never decoded back out of their spool.
+func isWrapperName(name string) bool {
+	switch name {
+	case "":
+		return false
+	case "int", "dec", "bin", "uuid", "ts", "date", "time", "link", "annot", "set", "map", "ext":
+		return true
+	}
+	return extensionForName(name) != nil
+}
+
+// lift decodes a wrapper's members and lifts it. Top-level numbers go back to
+// json.Number, the form liftWrapper reads "value" and "tag" in.
+func (s *spool) lift(name string, ms []spooledMember) (any, bool, error) {
+	m := make(map[string]any, len(ms))
+	for _, sm := range ms {
+		v, err := s.decode(sm)
+		if err != nil {
+			return nil, false, err
+		}
+		switch x := v.(type) {
+		case Int:
+			v = json.Number(x.V.String())
+		case Decimal:
+			v = json.Number(x.String())
+		}
+		m[sm.key] = v
+	}
+	return liftWrapper(name, m)
+}
+
+func (s *spool) decode(m spooledMember) (any, error) {
+	var b bytes.Buffer
+	if err := s.copyTo(&b, m.off, m.n); err != nil {
+		return nil, err
+	}
+	return DecodeBinary(b.Bytes())
+}
+
+// spool holds the encoded contents of an open container: in memory up to
+// max bytes, then in a temporary file.
+type spool struct {
+	buf []byte
+	f   *os.File
+	fw  *bufio.Writer
+	n   int64
+	max int
+	dir string
+}
+
+func (s *spool) Write(p []byte) (int, error) {
+	if s.f == nil && len(s.buf)+len(p) > s.max {
+		f, err := os.CreateTemp(s.dir, "jolt-spool-*")
+		if err != nil {
+			return 0, err
+		}
+		s.f, s.fw = f, bufio.NewWriter(f)
+		if _, err := s.fw.Write(s.buf); err != nil {
+			return 0, err
+		}
+		s.buf = nil
+	}
+	s.n += int64(len(p))
+	if s.f != nil {
+		return s.fw.Write(p)
+	}
+	s.buf = append(s.buf, p...)
+	return len(p), nil
+}
+
+// copyTo writes the n bytes at off to w.
+func (s *spool) copyTo(w io.Writer, off, n int64) error {
+	if s.f == nil {
+		_, err := w.Write(s.buf[off : off+n])
+		return err
+	}
+	if err := s.fw.Flush(); err != nil {
+		return err
+	}
+	_, err := io.Copy(w, io.NewSectionReader(s.f, off, n))
+	return err
+}
+
+func (s *spool) close() {
+	if s.f != nil {
+		s.f.Close()
+		os.Remove(s.f.Name())
+		s.f = nil
+	}
+}
+
+// binaryToJSON writes the JOLT-B value at r as JSON.
+func binaryToJSON(w *bufio.Writer, r *bufio.Reader, depth int) error {
+	if depth > DefaultLimits.MaxDepth {
+		return ErrTooDeep
+	}
+	tag, err := r.ReadByte()
+	if err != nil {
+		return err
+	}
+	switch tag {
+	case tagArr:
+		n, err := readUvarint(r)
+		if err != nil {
+			return err
+		}
+		w.WriteByte('[')
+		for i := uint64(0); i < n; i++ {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			if err := binaryToJSON(w, r, depth+1); err != nil {
+				return err
+			}
+		}
+		return w.WriteByte(']')
+	case tagObj:
+		n, err := readUvarint(r)
+		if err != nil {
+			return err
+		}
+		w.WriteByte('{')
+		first := true
+		for i := uint64(0); i < n; i++ {
+			kl, err := readUvarint(r)
+			if err != nil {
+				return err
+			}
+			kb := make([]byte, kl)
+			if _, err := io.ReadFull(r, kb); err != nil {
+				return err
+			}
+			if string(kb) == "$comment" && !PreserveComments {
+				if _, err := decodeAny(r, depth+1); err != nil {
+					return err
+				}
+				continue
+			}
+			if !first {
+				w.WriteByte(',')
+			}
+			first = false
+			key, _ := json.Marshal(string(kb))
+			w.Write(key)
+			w.WriteByte(':')
+			if err := binaryToJSON(w, r, depth+1); err != nil {
+				return err
+			}
+		}
+		return w.WriteByte('}')
+	case tagEnv:
+		// The meta comes first in JOLT-B but sorts after "$body" in JSON;
+		// it is small, so it waits while the body streams.
+		metaAny, err := decodeAny(r, depth+1)
+		if err != nil {
+			return err
+		}
+		m, ok := metaAny.(map[string]any)
+		if !ok {
+			return ErrBadEnvelope
+		}
+		meta, err := MetaFromMap(m)
+		if err != nil {
+			return err
+		}
+		w.WriteString(`{"$body":`)
+		if err := binaryToJSON(w, r, depth+1); err != nil {
+			return err
+		}
+		mb, err := json.Marshal(meta)
+		if err != nil {
+			return err
+		}
+		w.WriteString(`,"$meta":`)
+		w.Write(mb)
+		return w.WriteByte('}')
+	}
+	if err := r.UnreadByte(); err != nil {
+		return err
+	}
+	v, err := decodeAny(r, depth)
+	if err != nil {
+		return err
+	}
+	js, err := MarshalJSONCompat(v, false)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(js)
+	return err
+}
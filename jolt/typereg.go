@@ -0,0 +1,151 @@
+package jolt
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+var (
+	ErrUnknownType  = errors.New("jolt: no Go type registered for envelope type")
+	ErrTypeConflict = errors.New("jolt: envelope type already registered")
+	ErrNotEnvelope  = errors.New("jolt: payload is not an envelope")
+)
+
+// TypeRegistry maps envelope types (Meta.Type plus a Meta.Version range) to
+// Go types so bodies can be decoded straight into structs.
+type TypeRegistry struct {
+	// AllowUnknown makes Decode leave bodies of unregistered types (or
+	// versions) as generic values instead of returning ErrUnknownType.
+	AllowUnknown bool
+
+	mu      sync.RWMutex
+	entries map[string][]typeEntry
+}
+
+type typeEntry struct {
+	spec   string
+	rng    versionRange
+	goType reflect.Type
+}
+
+func NewTypeRegistry() *TypeRegistry { return &TypeRegistry{entries: map[string][]typeEntry{}} }
+
+// DefaultTypes backs RegisterType and DecodeEnvelope.
+var DefaultTypes = NewTypeRegistry()
+
+func RegisterType(typeURN, versionRange string, sample any) error {
+	return DefaultTypes.Register(typeURN, versionRange, sample)
+}
+
+func DecodeEnvelope(b []byte) (Envelope, error) { return DefaultTypes.DecodeEnvelope(b) }
+
+// Register binds typeURN at versions matching versionRange ("*", "2.x",
+// ">=2.0.0 <3.0.0", "^2.1", ...) to the type of sample. Decoded bodies are
+// returned as pointers to that type. A range overlapping one already
+// registered for typeURN is an ErrTypeConflict.
+func (r *TypeRegistry) Register(typeURN, versionRange string, sample any) error {
+	if typeURN == "" || sample == nil {
+		return errors.New("jolt: Register needs a type URN and a sample value")
+	}
+	rng, err := parseVersionRange(versionRange)
+	if err != nil {
+		return err
+	}
+	t := reflect.TypeOf(sample)
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.entries == nil {
+		r.entries = map[string][]typeEntry{}
+	}
+	for _, e := range r.entries[typeURN] {
+		if e.rng.overlaps(rng) {
+			return fmt.Errorf("%w: %s@%s overlaps %q, bound to %s", ErrTypeConflict, typeURN, versionRange, e.spec, e.goType)
+		}
+	}
+	r.entries[typeURN] = append(r.entries[typeURN], typeEntry{spec: versionRange, rng: rng, goType: t})
+	return nil
+}
+
+// Lookup returns the Go type registered for typeURN at version.
+func (r *TypeRegistry) Lookup(typeURN, version string) (reflect.Type, error) {
+	r.mu.RLock()
+	entries := r.entries[typeURN]
+	r.mu.RUnlock()
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownType, typeURN)
+	}
+	v, err := parseSemver(version)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q has unparseable version %q", ErrUnknownType, typeURN, version)
+	}
+	var found *typeEntry
+	for i := range entries {
+		if !entries[i].rng.match(v) {
+			continue
+		}
+		if found != nil {
+			return nil, fmt.Errorf("jolt: %s@%s matches both %q and %q", typeURN, version, found.spec, entries[i].spec)
+		}
+		found = &entries[i]
+	}
+	if found == nil {
+		return nil, fmt.Errorf("%w: %q has no registration covering version %s", ErrUnknownType, typeURN, version)
+	}
+	return found.goType, nil
+}
+
+// DecodeEnvelope decodes JOLT-B holding an envelope and types its body.
+func (r *TypeRegistry) DecodeEnvelope(b []byte) (Envelope, error) {
+	v, err := DecodeBinary(b)
+	if err != nil {
+		return Envelope{}, err
+	}
+	return r.Decode(v)
+}
+
+// Decode types the body of an already decoded envelope. Both Envelope values
+// and {"$meta":..,"$body":..} objects (as ingested from JSON) are accepted.
+func (r *TypeRegistry) Decode(v any) (Envelope, error) {
+	env, err := AsEnvelope(v)
+	if err != nil {
+		return Envelope{}, err
+	}
+	t, err := r.Lookup(env.Meta.Type, env.Meta.Version)
+	if err != nil {
+		if r.AllowUnknown && errors.Is(err, ErrUnknownType) {
+			return env, nil
+		}
+		return Envelope{}, err
+	}
+	body := reflect.New(t)
+	if err := DecodeInto(env.Body, body.Interface()); err != nil {
+		return Envelope{}, fmt.Errorf("jolt: decoding %s@%s body: %w", env.Meta.Type, env.Meta.Version, err)
+	}
+	env.Body = body.Interface()
+	return env, nil
+}
+
+// AsEnvelope accepts an Envelope or a {"$meta":..,"$body":..} object as
+// ingested from JSON.
+func AsEnvelope(v any) (Envelope, error) {
+	switch x := v.(type) {
+	case Envelope:
+		return x, nil
+	case map[string]any:
+		mm, ok := x["$meta"].(map[string]any)
+		if !ok {
+			return Envelope{}, ErrNotEnvelope
+		}
+		meta, err := MetaFromMap(mm)
+		if err != nil {
+			return Envelope{}, err
+		}
+		return Envelope{Meta: meta, Body: x["$body"]}, nil
+	}
+	return Envelope{}, fmt.Errorf("%w: got %T", ErrNotEnvelope, v)
+}
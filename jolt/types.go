@@ -4,11 +4,13 @@ import (
     "crypto/rand"
     "encoding/base64"
     "encoding/binary"
+    "encoding/hex"
     "encoding/json"
     "errors"
     "fmt"
     "io"
     "math/big"
+    "strings"
     "time"
 
     "github.com/cockroachdb/apd/v3"
@@ -48,6 +50,13 @@ func NewUUID() (UUID, error) {
     u[8] = (u[8] & 0x3f) | 0x80
     return u, nil
 }
+func UUIDFromString(s string) (UUID, error) {
+    var u UUID
+    h := strings.ReplaceAll(s, "-", "")
+    if len(h) != 32 { return u, errors.New("bad uuid") }
+    if _, err := hex.Decode(u[:], []byte(h)); err != nil { return u, errors.New("bad uuid") }
+    return u, nil
+}
 func (u UUID) String() string {
     b := u
     return fmt.Sprintf("%08x-%04x-%04x-%04x-%012x",
@@ -0,0 +1,64 @@
+package jolt
+
+import "bytes"
+
+// cloneValue deep-copies the containers of a decoded JOLT tree so it can be
+// edited without touching the original. Scalars are shared: Int, Decimal and
+// Binary values are never mutated in place by this package.
+func cloneValue(v any) any {
+	switch x := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(x))
+		for k, it := range x {
+			out[k] = cloneValue(it)
+		}
+		return out
+	case []any:
+		out := make([]any, len(x))
+		for i, it := range x {
+			out[i] = cloneValue(it)
+		}
+		return out
+	case Set:
+		out := make(Set, len(x))
+		for i, it := range x {
+			out[i] = cloneValue(it)
+		}
+		return out
+	case Map:
+		out := make(Map, len(x))
+		for k, it := range x {
+			out[k] = cloneValue(it)
+		}
+		return out
+	case Envelope:
+		x.Meta = cloneMeta(x.Meta)
+		x.Body = cloneValue(x.Body)
+		return x
+	}
+	return v
+}
+
+func cloneMeta(m Meta) Meta {
+	if m.Features != nil {
+		m.Features = append([]string(nil), m.Features...)
+	}
+	if m.Extra != nil {
+		m.Extra = cloneValue(m.Extra).(map[string]any)
+	}
+	m.Sig = cloneValue(m.Sig)
+	return m
+}
+
+// Equal reports whether a and b are the same JOLT value, meaning their
+// canonical JOLT-B encodings match. Comparison is exact: Decimals differing
+// only in trailing zeros (1.5 and 1.50) are different values, as are Int 2
+// and Decimal 2.
+func Equal(a, b any) bool {
+	ab, err := EncodeBinary(a)
+	if err != nil {
+		return false
+	}
+	bb, err := EncodeBinary(b)
+	return err == nil && bytes.Equal(ab, bb)
+}
@@ -0,0 +1,284 @@
+package jolt
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// semver is the subset of semantic versioning used by Meta.Version:
+// MAJOR[.MINOR[.PATCH]][-PRERELEASE], with an optional leading "v".
+type semver struct {
+	major, minor, patch int
+	pre                 string
+}
+
+func parseSemver(s string) (semver, error) {
+	var v semver
+	core := strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if i := strings.IndexByte(core, '+'); i >= 0 {
+		core = core[:i]
+	}
+	if i := strings.IndexByte(core, '-'); i >= 0 {
+		core, v.pre = core[:i], core[i+1:]
+		for _, id := range strings.Split(v.pre, ".") {
+			if id == "" {
+				return v, fmt.Errorf("jolt: bad version %q", s)
+			}
+		}
+	}
+	parts := strings.Split(core, ".")
+	if core == "" || len(parts) > 3 {
+		return v, fmt.Errorf("jolt: bad version %q", s)
+	}
+	nums := [3]*int{&v.major, &v.minor, &v.patch}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return v, fmt.Errorf("jolt: bad version %q", s)
+		}
+		*nums[i] = n
+	}
+	return v, nil
+}
+
+func (v semver) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.major, v.minor, v.patch)
+	if v.pre != "" {
+		s += "-" + v.pre
+	}
+	return s
+}
+
+func (v semver) compare(o semver) int {
+	for _, d := range [3]int{v.major - o.major, v.minor - o.minor, v.patch - o.patch} {
+		if d != 0 {
+			return sign(d)
+		}
+	}
+	return comparePre(v.pre, o.pre)
+}
+
+// comparePre orders prerelease strings as semver §11 does: a version without
+// one is higher, identifiers compare left to right (numeric ones as numbers
+// and below alphanumeric ones), and a longer list wins a tie.
+func comparePre(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		if d := compareIdent(as[i], bs[i]); d != 0 {
+			return d
+		}
+	}
+	return sign(len(as) - len(bs))
+}
+
+func compareIdent(a, b string) int {
+	an, aNum := numericIdent(a)
+	bn, bNum := numericIdent(b)
+	switch {
+	case aNum && bNum:
+		return sign(an - bn)
+	case aNum:
+		return -1
+	case bNum:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+func numericIdent(s string) (int, bool) {
+	n, err := strconv.Atoi(s)
+	return n, err == nil && n >= 0
+}
+
+func sign(d int) int {
+	switch {
+	case d < 0:
+		return -1
+	case d > 0:
+		return 1
+	}
+	return 0
+}
+
+// CompareVersions orders two Meta.Version strings by semantic version.
+func CompareVersions(a, b string) (int, error) {
+	va, err := parseSemver(a)
+	if err != nil {
+		return 0, err
+	}
+	vb, err := parseSemver(b)
+	if err != nil {
+		return 0, err
+	}
+	return va.compare(vb), nil
+}
+
+type versionComparator struct {
+	op string
+	v  semver
+}
+
+func (c versionComparator) match(v semver) bool {
+	d := v.compare(c.v)
+	switch c.op {
+	case "=":
+		return d == 0
+	case ">":
+		return d > 0
+	case ">=":
+		return d >= 0
+	case "<":
+		return d < 0
+	case "<=":
+		return d <= 0
+	}
+	return false
+}
+
+// versionRange is an OR of AND-ed comparators, e.g. ">=2.0.0 <3.0.0 || 4.x".
+type versionRange [][]versionComparator
+
+// parseVersionRange accepts "*", exact versions, comparators (>=, >, <=, <,
+// =), wildcards ("2.x", "2.1.*"), caret ("^2.1") and tilde ("~2.1.0") forms.
+// The upper bound of a caret, tilde or wildcard form excludes prereleases of
+// that bound: "^1.2" does not match 2.0.0-rc.1.
+func parseVersionRange(s string) (versionRange, error) {
+	var out versionRange
+	for _, alt := range strings.Split(s, "||") {
+		if strings.TrimSpace(alt) == "" {
+			return nil, fmt.Errorf("jolt: bad version range %q: empty alternative", s)
+		}
+		var and []versionComparator
+		for _, term := range strings.Fields(alt) {
+			cs, err := parseVersionTerm(term)
+			if err != nil {
+				return nil, fmt.Errorf("jolt: bad version range %q: %w", s, err)
+			}
+			and = append(and, cs...)
+		}
+		out = append(out, and)
+	}
+	return out, nil
+}
+
+func parseVersionTerm(t string) ([]versionComparator, error) {
+	if t == "*" || t == "x" {
+		return nil, nil
+	}
+	for _, op := range []string{">=", "<=", ">", "<", "="} {
+		if strings.HasPrefix(t, op) {
+			v, err := parseSemver(t[len(op):])
+			if err != nil {
+				return nil, err
+			}
+			return []versionComparator{{op, v}}, nil
+		}
+	}
+	if strings.HasPrefix(t, "^") || strings.HasPrefix(t, "~") {
+		lo, err := parseSemver(t[1:])
+		if err != nil {
+			return nil, err
+		}
+		parts := len(strings.Split(strings.TrimPrefix(t[1:], "v"), "."))
+		// ^ keeps the leftmost non-zero part given, ~ the minor if given.
+		hi := semver{major: lo.major + 1, pre: "0"}
+		switch {
+		case t[0] == '^' && lo.major == 0 && lo.minor == 0 && parts == 3:
+			hi = semver{patch: lo.patch + 1, pre: "0"}
+		case t[0] == '~' && parts > 1 || t[0] == '^' && lo.major == 0 && parts > 1:
+			hi = semver{major: lo.major, minor: lo.minor + 1, pre: "0"}
+		}
+		return []versionComparator{{">=", lo}, {"<", hi}}, nil
+	}
+	parts := strings.Split(strings.TrimPrefix(t, "v"), ".")
+	for i, p := range parts {
+		if p == "x" || p == "X" || p == "*" {
+			lo, err := parseSemver(strings.Join(parts[:i], "."))
+			if i == 0 || err != nil {
+				return nil, fmt.Errorf("bad wildcard %q", t)
+			}
+			hi := semver{major: lo.major + 1, pre: "0"}
+			if i == 2 {
+				hi = semver{major: lo.major, minor: lo.minor + 1, pre: "0"}
+			}
+			return []versionComparator{{">=", lo}, {"<", hi}}, nil
+		}
+	}
+	v, err := parseSemver(t)
+	if err != nil {
+		return nil, err
+	}
+	return []versionComparator{{"=", v}}, nil
+}
+
+func (r versionRange) match(v semver) bool {
+	for _, and := range r {
+		ok := true
+		for _, c := range and {
+			if !c.match(v) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// overlaps reports whether some version matches both r and o. It takes
+// versions to be dense, so it may report ranges that meet only between two
+// adjacent prereleases as overlapping.
+func (r versionRange) overlaps(o versionRange) bool {
+	for _, a := range r {
+		for _, b := range o {
+			if satisfiable(append(a[:len(a):len(a)], b...)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// satisfiable narrows the AND-ed comparators to their tightest lower and
+// upper bounds and reports whether any version lies between them.
+func satisfiable(and []versionComparator) bool {
+	var lo, hi *versionComparator
+	// tighten keeps the narrower of cur and c; at an equal version the
+	// exclusive bound is narrower. d is 1 for lower bounds, -1 for upper.
+	tighten := func(cur **versionComparator, c versionComparator, d int) {
+		if *cur == nil {
+			*cur = &c
+			return
+		}
+		if cmp := c.v.compare((*cur).v) * d; cmp > 0 || cmp == 0 && len(c.op) == 1 {
+			*cur = &c
+		}
+	}
+	for _, c := range and {
+		switch c.op {
+		case ">", ">=":
+			tighten(&lo, c, 1)
+		case "<", "<=":
+			tighten(&hi, c, -1)
+		case "=":
+			tighten(&lo, versionComparator{">=", c.v}, 1)
+			tighten(&hi, versionComparator{"<=", c.v}, -1)
+		}
+	}
+	if lo == nil || hi == nil {
+		return true
+	}
+	d := lo.v.compare(hi.v)
+	return d < 0 || d == 0 && lo.op == ">=" && hi.op == "<="
+}
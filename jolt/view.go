@@ -0,0 +1,268 @@
+package jolt
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var errTruncated = errors.New("jolt: truncated JOLT-B")
+
+// View is a lazily decoded JOLT-B value. NewView checks the structure once;
+// after that, fields and elements are located by skipping over their
+// siblings' encodings, and only values asked for with Decode are
+// materialized. This lets routing and indexing code read a few fields of a
+// stored document without decoding all of it.
+type View struct{ b []byte }
+
+// NewView wraps the JOLT-B encoding of exactly one value. b is not copied and
+// must not change while the view is in use.
+func NewView(b []byte) (View, error) {
+	end, err := skipValue(b, 0, 0)
+	if err != nil {
+		return View{}, err
+	}
+	if end != len(b) {
+		return View{}, fmt.Errorf("jolt: %d trailing bytes after value", len(b)-end)
+	}
+	return View{b: b}, nil
+}
+
+// Raw returns the encoding the view covers.
+func (v View) Raw() []byte { return v.b }
+
+// Decode decodes the value the view covers.
+func (v View) Decode() (any, error) { return DecodeBinary(v.b) }
+
+// IsNull reports whether the value is null.
+func (v View) IsNull() bool { return len(v.b) > 0 && v.b[0] == tagNull }
+
+// Len returns the number of elements of an array or Set, fields of an object
+// or entries of a Map, and 0 for anything else.
+func (v View) Len() int {
+	switch v.tag() {
+	case tagArr, tagSet, tagObj, tagMap:
+		n, _, _ := uvarintAt(v.b, 1)
+		return int(n)
+	}
+	return 0
+}
+
+// Field returns an object field, the entry of a Map under a string key, or
+// the "$meta" or "$body" of an envelope.
+func (v View) Field(name string) (View, bool) {
+	switch v.tag() {
+	case tagObj:
+		for _, f := range v.fields() {
+			if f.name == name {
+				return f.val, true
+			}
+		}
+	case tagMap:
+		want, _ := EncodeBinary(name)
+		for _, e := range v.entries() {
+			if string(e[0].b) == string(want) {
+				return e[1], true
+			}
+		}
+	case tagEnv:
+		meta, body := v.envelope()
+		switch name {
+		case "$meta":
+			return meta, true
+		case "$body":
+			return body, true
+		}
+	}
+	return View{}, false
+}
+
+// Index returns element i of an array.
+func (v View) Index(i int) (View, bool) {
+	if v.tag() != tagArr {
+		return View{}, false
+	}
+	items := v.items()
+	if i < 0 || i >= len(items) {
+		return View{}, false
+	}
+	return items[i], true
+}
+
+func (v View) tag() byte {
+	if len(v.b) == 0 {
+		return tagNull
+	}
+	return v.b[0]
+}
+
+type viewField struct {
+	name string
+	val  View
+}
+
+// items returns the elements of an array or Set.
+func (v View) items() []View {
+	n, pos, _ := uvarintAt(v.b, 1)
+	out := make([]View, 0, n)
+	for i := uint64(0); i < n; i++ {
+		end, _ := skipValue(v.b, pos, 0)
+		out = append(out, View{b: v.b[pos:end]})
+		pos = end
+	}
+	return out
+}
+
+// fields returns the fields of an object in encoded (sorted) order, leaving
+// out "$comment" unless PreserveComments is set, as DecodeBinary does.
+func (v View) fields() []viewField {
+	n, pos, _ := uvarintAt(v.b, 1)
+	out := make([]viewField, 0, n)
+	for i := uint64(0); i < n; i++ {
+		kl, p, _ := uvarintAt(v.b, pos)
+		name := string(v.b[p : p+int(kl)])
+		pos = p + int(kl)
+		end, _ := skipValue(v.b, pos, 0)
+		if name != "$comment" || PreserveComments {
+			out = append(out, viewField{name, View{b: v.b[pos:end]}})
+		}
+		pos = end
+	}
+	return out
+}
+
+// entries returns the key and value views of a Map.
+func (v View) entries() [][2]View {
+	n, pos, _ := uvarintAt(v.b, 1)
+	out := make([][2]View, 0, n)
+	for i := uint64(0); i < n; i++ {
+		kend, _ := skipValue(v.b, pos, 0)
+		vend, _ := skipValue(v.b, kend, 0)
+		out = append(out, [2]View{{b: v.b[pos:kend]}, {b: v.b[kend:vend]}})
+		pos = vend
+	}
+	return out
+}
+
+func (v View) envelope() (meta, body View) {
+	mend, _ := skipValue(v.b, 1, 0)
+	return View{b: v.b[1:mend]}, View{b: v.b[mend:]}
+}
+
+// memberSegment and keySegment are MemberSegment and KeySegment computed
+// from the stored encoding.
+func (v View) memberSegment() string {
+	return memberPrefix + base64.RawURLEncoding.EncodeToString(v.b)
+}
+
+func (v View) keySegment() string {
+	if v.tag() == tagStr {
+		if s, err := v.Decode(); err == nil && !strings.HasPrefix(s.(string), keyPrefix) {
+			return s.(string)
+		}
+	}
+	return keyPrefix + base64.RawURLEncoding.EncodeToString(v.b)
+}
+
+func uvarintAt(b []byte, pos int) (uint64, int, error) {
+	if pos >= len(b) {
+		return 0, 0, errTruncated
+	}
+	x, n := binary.Uvarint(b[pos:])
+	if n <= 0 {
+		return 0, 0, fmt.Errorf("jolt: bad uvarint at offset %d", pos)
+	}
+	return x, pos + n, nil
+}
+
+// skipBytes moves past n bytes starting at pos.
+func skipBytes(b []byte, pos int, n uint64) (int, error) {
+	if n > uint64(len(b)-pos) {
+		return 0, errTruncated
+	}
+	return pos + int(n), nil
+}
+
+// skipValue returns the offset just past the value encoded at pos.
+func skipValue(b []byte, pos, depth int) (int, error) {
+	if depth > DefaultLimits.MaxDepth {
+		return 0, ErrTooDeep
+	}
+	if pos >= len(b) {
+		return 0, errTruncated
+	}
+	tag := b[pos]
+	pos++
+	switch tag {
+	case tagNull, tagF, tagT:
+		return pos, nil
+	case tagStr, tagTS, tagDate, tagTime, tagLink, tagAnnot, tagBin, tagInt:
+		n, p, err := uvarintAt(b, pos)
+		if err != nil {
+			return 0, err
+		}
+		return skipBytes(b, p, n)
+	case tagDec:
+		p, err := skipBytes(b, pos, 1)
+		if err != nil {
+			return 0, err
+		}
+		if _, p, err = uvarintAt(b, p); err != nil {
+			return 0, err
+		}
+		n, p, err := uvarintAt(b, p)
+		if err != nil {
+			return 0, err
+		}
+		return skipBytes(b, p, n)
+	case tagUUID:
+		return skipBytes(b, pos, 16)
+	case tagArr, tagSet, tagObj, tagMap:
+		n, p, err := uvarintAt(b, pos)
+		if err != nil {
+			return 0, err
+		}
+		for i := uint64(0); i < n; i++ {
+			if tag == tagObj {
+				kl, kp, err := uvarintAt(b, p)
+				if err != nil {
+					return 0, err
+				}
+				if p, err = skipBytes(b, kp, kl); err != nil {
+					return 0, err
+				}
+			}
+			if p, err = skipValue(b, p, depth+1); err != nil {
+				return 0, err
+			}
+			if tag == tagMap {
+				if p, err = skipValue(b, p, depth+1); err != nil {
+					return 0, err
+				}
+			}
+		}
+		return p, nil
+	case tagEnv:
+		if pos < len(b) && b[pos] != tagObj {
+			return 0, ErrBadEnvelope
+		}
+		p, err := skipValue(b, pos, depth+1)
+		if err != nil {
+			return 0, err
+		}
+		return skipValue(b, p, depth+1)
+	case tagExt:
+		_, p, err := uvarintAt(b, pos)
+		if err != nil {
+			return 0, err
+		}
+		n, p, err := uvarintAt(b, p)
+		if err != nil {
+			return 0, err
+		}
+		return skipBytes(b, p, n)
+	}
+	return 0, fmt.Errorf("%w: 0x%02x", ErrUnknownTag, tag)
+}
@@ -0,0 +1,393 @@
+package jolt
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Path locates a value during Walk and Transform, in the form Get takes:
+// field names (and "$meta"/"$body" of envelopes), int array indexes and Map
+// keys, plus MapKey and SetMember for keys and members themselves.
+type Path []any
+
+func (p Path) String() string { return formatPath(p) }
+
+// MapKey in a Path addresses a Map key itself rather than the value stored
+// under it.
+type MapKey struct{ Key any }
+
+// SetMember in a Path addresses a member of a Set.
+type SetMember struct{ Value any }
+
+var (
+	// SkipSubtree, returned by a WalkFunc, skips the children of the value
+	// just visited.
+	SkipSubtree = errors.New("jolt: skip subtree")
+	// Remove, returned by a TransformFunc, drops the value from its parent:
+	// an object field, array element, Set member or Map entry.
+	Remove = errors.New("jolt: remove value")
+)
+
+// WalkFunc is called by Walk for each value.
+type WalkFunc func(path Path, v any) error
+
+// TransformFunc is called by Transform and TransformBinary for each value and
+// returns its replacement.
+type TransformFunc func(path Path, v any) (any, error)
+
+// Walk calls fn for v and everything inside it, parents before children.
+// Objects are visited in key order, Sets in stored order and Maps in
+// canonical key order, each key (at MapKey) before its value; envelopes
+// have "$meta" (as an object) then "$body". A SkipSubtree from fn skips the
+// value's children; any other error stops the walk and is returned.
+func Walk(v any, fn WalkFunc) error {
+	err := walkValue(nil, v, fn)
+	if err == SkipSubtree {
+		return nil
+	}
+	return err
+}
+
+func walkValue(path Path, v any, fn WalkFunc) error {
+	if err := fn(path, v); err != nil {
+		return err
+	}
+	visit := func(seg, c any) error {
+		if err := walkValue(appendPath(path, seg), c, fn); err != nil && err != SkipSubtree {
+			return err
+		}
+		return nil
+	}
+	switch x := v.(type) {
+	case map[string]any:
+		for _, k := range sortedStringKeys(x) {
+			if err := visit(k, x[k]); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for i, it := range x {
+			if err := visit(i, it); err != nil {
+				return err
+			}
+		}
+	case Set:
+		for _, m := range x {
+			if err := visit(SetMember{m}, m); err != nil {
+				return err
+			}
+		}
+	case Map:
+		for _, k := range sortedCanonical(mapKeys(x)) {
+			if err := visit(MapKey{k}, k); err != nil {
+				return err
+			}
+			if err := visit(k, x[k]); err != nil {
+				return err
+			}
+		}
+	case Envelope:
+		if err := visit("$meta", x.Meta.ToMap()); err != nil {
+			return err
+		}
+		return visit("$body", x.Body)
+	}
+	return nil
+}
+
+// Transform rebuilds v bottom-up: each container is copied with its
+// children transformed, then passed to fn along with every scalar, and fn's
+// result takes its place. Returning Remove drops the value from its parent
+// (Transform returns nil if the root is removed); other errors stop the
+// rebuild. Map keys are transformed at their MapKey path; two keys, or two
+// Set members, becoming equal is an error. v itself is not modified.
+func Transform(v any, fn TransformFunc) (any, error) {
+	out, err := transformValue(nil, v, fn)
+	if err == Remove {
+		return nil, nil
+	}
+	return out, err
+}
+
+func transformValue(path Path, v any, fn TransformFunc) (any, error) {
+	child := func(seg, c any) (any, bool, error) {
+		out, err := transformValue(appendPath(path, seg), c, fn)
+		if err == Remove {
+			return nil, false, nil
+		}
+		return out, err == nil, err
+	}
+	switch x := v.(type) {
+	case map[string]any:
+		obj := make(map[string]any, len(x))
+		for _, k := range sortedStringKeys(x) {
+			c, keep, err := child(k, x[k])
+			if err != nil {
+				return nil, err
+			}
+			if keep {
+				obj[k] = c
+			}
+		}
+		v = obj
+	case []any:
+		arr := make([]any, 0, len(x))
+		for i, it := range x {
+			c, keep, err := child(i, it)
+			if err != nil {
+				return nil, err
+			}
+			if keep {
+				arr = append(arr, c)
+			}
+		}
+		v = arr
+	case Set:
+		set := make(Set, 0, len(x))
+		for _, m := range x {
+			c, keep, err := child(SetMember{m}, m)
+			if err != nil {
+				return nil, err
+			}
+			if !keep {
+				continue
+			}
+			if setHas(set, c) {
+				return nil, &PathError{Path: formatPath(appendPath(path, SetMember{m})), Err: fmt.Errorf("two set members became equal")}
+			}
+			set = append(set, c)
+		}
+		v = set
+	case Map:
+		m := make(Map, len(x))
+		for _, k := range sortedCanonical(mapKeys(x)) {
+			nk, keep, err := child(MapKey{k}, k)
+			if err != nil {
+				return nil, err
+			}
+			if !keep {
+				continue
+			}
+			c, keep, err := child(k, x[k])
+			if err != nil {
+				return nil, err
+			}
+			if !keep {
+				continue
+			}
+			if _, dup := mapFind(m, nk); dup {
+				return nil, &PathError{Path: formatPath(appendPath(path, MapKey{k})), Err: fmt.Errorf("key %v already present", nk)}
+			}
+			m[nk] = c
+		}
+		v = m
+	case Envelope:
+		mc, keep, err := child("$meta", x.Meta.ToMap())
+		if err != nil {
+			return nil, err
+		}
+		if keep {
+			mm, ok := mc.(map[string]any)
+			if !ok {
+				return nil, &PathError{Path: formatPath(appendPath(path, "$meta")), Err: fmt.Errorf("$meta must be an object, got %T", mc)}
+			}
+			if x.Meta, err = MetaFromMap(mm); err != nil {
+				return nil, err
+			}
+		} else {
+			x.Meta = Meta{}
+		}
+		body, _, err := child("$body", x.Body)
+		if err != nil {
+			return nil, err
+		}
+		x.Body = body
+		v = x
+	}
+	return fn(path, v)
+}
+
+// TransformBinary rewrites the JOLT-B value jb without decoding it as a
+// whole. fn sees containers as Views, top-down: returning the View unchanged
+// rewrites its children in turn, returning anything else replaces the whole
+// container, and Remove drops it. Scalars are decoded and passed as values;
+// ones fn returns unchanged keep their original bytes. Paths are as in
+// Transform, except that Set members and Map keys that are containers appear
+// as Views. Sets and Maps are re-sorted, so the output stays canonical.
+func TransformBinary(jb []byte, fn TransformFunc) ([]byte, error) {
+	v, err := NewView(jb)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := rewriteView(&buf, nil, v, fn); err != nil {
+		if err == Remove {
+			return []byte{tagNull}, nil
+		}
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// rewriteView writes the transformed encoding of v to buf; Remove means
+// nothing was written and the parent should drop v.
+func rewriteView(buf *bytes.Buffer, path Path, v View, fn TransformFunc) error {
+	var in any = v
+	container := isContainerTag(v.tag())
+	if !container {
+		d, err := v.Decode()
+		if err != nil {
+			return err
+		}
+		in = d
+	}
+	out, err := fn(path, in)
+	if err != nil {
+		return err
+	}
+	if ov, ok := out.(View); ok {
+		if container && bytes.Equal(ov.b, v.b) {
+			return rewriteChildren(buf, path, v, fn)
+		}
+		buf.Write(ov.b)
+		return nil
+	}
+	if !container && Equal(out, in) {
+		buf.Write(v.b) // unchanged scalar
+		return nil
+	}
+	return encodeAny(buf, out, len(path))
+}
+
+func isContainerTag(tag byte) bool {
+	switch tag {
+	case tagArr, tagObj, tagSet, tagMap, tagEnv:
+		return true
+	}
+	return false
+}
+
+// rewriteChildren writes container v with each child passed through
+// rewriteView.
+func rewriteChildren(buf *bytes.Buffer, path Path, v View, fn TransformFunc) error {
+	// each rewrites one child, reporting whether it was kept.
+	each := func(seg any, c View) ([]byte, bool, error) {
+		var b bytes.Buffer
+		err := rewriteView(&b, appendPath(path, seg), c, fn)
+		if err == Remove {
+			return nil, false, nil
+		}
+		return b.Bytes(), err == nil, err
+	}
+	var parts [][]byte
+	switch tag := v.tag(); tag {
+	case tagArr, tagSet:
+		for i, it := range v.items() {
+			var seg any = i
+			if tag == tagSet {
+				seg = SetMember{viewSegment(it)}
+			}
+			b, keep, err := each(seg, it)
+			if err != nil {
+				return err
+			}
+			if keep {
+				parts = append(parts, b)
+			}
+		}
+		if tag == tagSet {
+			sort.Slice(parts, func(i, j int) bool { return bytes.Compare(parts[i], parts[j]) < 0 })
+			for i := 1; i < len(parts); i++ {
+				if bytes.Equal(parts[i], parts[i-1]) {
+					return &PathError{Path: formatPath(path), Err: fmt.Errorf("two set members became equal")}
+				}
+			}
+		}
+	case tagObj:
+		for _, f := range v.fields() {
+			b, keep, err := each(f.name, f.val)
+			if err != nil {
+				return err
+			}
+			if keep {
+				var kb bytes.Buffer
+				writeString(&kb, f.name)
+				parts = append(parts, append(kb.Bytes(), b...))
+			}
+		}
+	case tagMap:
+		type kv struct{ k, v []byte }
+		var kvs []kv
+		for _, e := range v.entries() {
+			key := viewSegment(e[0])
+			kb, keep, err := each(MapKey{key}, e[0])
+			if err != nil {
+				return err
+			}
+			if !keep {
+				continue
+			}
+			vb, keep, err := each(key, e[1])
+			if err != nil {
+				return err
+			}
+			if keep {
+				kvs = append(kvs, kv{kb, vb})
+			}
+		}
+		sort.Slice(kvs, func(i, j int) bool { return bytes.Compare(kvs[i].k, kvs[j].k) < 0 })
+		for i, p := range kvs {
+			if i > 0 && bytes.Equal(p.k, kvs[i-1].k) {
+				return &PathError{Path: formatPath(path), Err: fmt.Errorf("two map keys became equal")}
+			}
+			parts = append(parts, append(p.k, p.v...))
+		}
+	case tagEnv:
+		meta, body := v.envelope()
+		mb, keep, err := each("$meta", meta)
+		if err != nil {
+			return err
+		}
+		if !keep {
+			mb = []byte{tagObj, 0}
+		} else if len(mb) == 0 || mb[0] != tagObj {
+			return &PathError{Path: formatPath(appendPath(path, "$meta")), Err: ErrBadEnvelope}
+		}
+		bb, keep, err := each("$body", body)
+		if err != nil {
+			return err
+		}
+		if !keep {
+			bb = []byte{tagNull}
+		}
+		buf.WriteByte(tagEnv)
+		buf.Write(mb)
+		buf.Write(bb)
+		return nil
+	}
+	buf.WriteByte(v.tag())
+	putUvarint(buf, uint64(len(parts)))
+	for _, p := range parts {
+		buf.Write(p)
+	}
+	return nil
+}
+
+// viewSegment is the Path element for a Set member or Map key read from a
+// View: the decoded value for scalars, the View for containers.
+func viewSegment(v View) any {
+	if isContainerTag(v.tag()) {
+		return v
+	}
+	d, err := v.Decode()
+	if err != nil {
+		return v
+	}
+	return d
+}
+
+func appendPath(p Path, seg any) Path {
+	return append(append(Path(nil), p...), seg)
+}
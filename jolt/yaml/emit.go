@@ -0,0 +1,409 @@
+package yaml
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/cockroachdb/apd/v3"
+
+	"github.com/chandan-cmd-dev/jolt-go/jolt"
+)
+
+// node is a value laid out for writing: a scalar (text already quoted as
+// needed), a literal block, a mapping or a sequence, each with an optional
+// tag.
+type node struct {
+	tag     string
+	text    string   // scalar
+	literal []string // literal block lines; text holds the header
+	keys    []string // mapping
+	vals    []*node
+	items   []*node // sequence
+	seq     bool
+}
+
+func (n *node) isMap() bool { return n.keys != nil }
+
+type emitter struct{ b bytes.Buffer }
+
+func (e *emitter) doc(v any) error {
+	n, err := toNode(v, 0)
+	if err != nil {
+		return err
+	}
+	switch {
+	case n.tag != "" && (n.isMap() && len(n.keys) > 0 || n.seq && len(n.items) > 0):
+		e.b.WriteString(n.tag + "\n")
+		e.collection(n, 0, false)
+	case n.isMap() && len(n.keys) > 0 || n.seq && len(n.items) > 0:
+		e.collection(n, 0, false)
+	default:
+		e.b.WriteString(strings.TrimPrefix(e.inline(n), " "))
+		e.block(n, 2)
+	}
+	return nil
+}
+
+// after writes the rest of the line following "key:" or "-" at column col,
+// and whatever nested lines the value needs.
+func (e *emitter) after(n *node, col int, item bool) {
+	full := n.isMap() && len(n.keys) > 0 || n.seq && len(n.items) > 0
+	switch {
+	case !full:
+		e.b.WriteString(e.inline(n))
+		e.block(n, col+2)
+	case n.tag != "":
+		e.b.WriteString(" " + n.tag + "\n")
+		e.collection(n, col+2, false)
+	case item:
+		e.b.WriteByte(' ')
+		e.collection(n, col+2, true)
+	default:
+		e.b.WriteByte('\n')
+		e.collection(n, col+2, false)
+	}
+}
+
+// inline renders a scalar, an empty collection or a literal header, with a
+// leading space and a trailing newline.
+func (e *emitter) inline(n *node) string {
+	var s string
+	switch {
+	case n.isMap():
+		s = "{}"
+	case n.seq:
+		s = "[]"
+	default:
+		s = n.text
+	}
+	if n.tag != "" {
+		s = n.tag + " " + s
+	}
+	return " " + s + "\n"
+}
+
+// block writes a literal scalar's lines.
+func (e *emitter) block(n *node, indent int) {
+	for _, l := range n.literal {
+		if l != "" {
+			e.b.WriteString(strings.Repeat(" ", indent) + l)
+		}
+		e.b.WriteByte('\n')
+	}
+}
+
+// collection writes entries at column col; with inline set, the first
+// entry continues the current line.
+func (e *emitter) collection(n *node, col int, inline bool) {
+	pad := strings.Repeat(" ", col)
+	if n.seq {
+		for i, it := range n.items {
+			if i > 0 || !inline {
+				e.b.WriteString(pad)
+			}
+			e.b.WriteByte('-')
+			e.after(it, col, true)
+		}
+		return
+	}
+	for i, k := range n.keys {
+		if i > 0 || !inline {
+			e.b.WriteString(pad)
+		}
+		if k == "<<" {
+			e.b.WriteString(quote(k) + ":")
+		} else {
+			e.b.WriteString(plainOrQuoted(k, true) + ":")
+		}
+		e.after(n.vals[i], col, false)
+	}
+}
+
+func toNode(v any, depth int) (*node, error) {
+	if depth > jolt.DefaultLimits.MaxDepth {
+		return nil, jolt.ErrTooDeep
+	}
+	switch x := v.(type) {
+	case nil:
+		return &node{text: "null"}, nil
+	case bool:
+		return &node{text: strconv.FormatBool(x)}, nil
+	case string:
+		return stringNode(x), nil
+	case jolt.Int:
+		if x.V == nil {
+			return &node{text: "0"}, nil
+		}
+		return &node{text: x.V.String()}, nil
+	case jolt.Decimal:
+		return decimalNode(x)
+	case jolt.Binary:
+		if len(x) == 0 {
+			return &node{tag: "!!binary", text: `""`}, nil
+		}
+		return &node{tag: "!!binary", text: base64.StdEncoding.EncodeToString(x)}, nil
+	case jolt.UUID:
+		return &node{tag: "!uuid", text: x.String()}, nil
+	case jolt.Timestamp:
+		return &node{tag: "!ts", text: plainOrQuoted(x.RFC3339, false)}, nil
+	case jolt.Date:
+		return &node{tag: "!date", text: plainOrQuoted(x.YYYYMMDD, false)}, nil
+	case jolt.Time:
+		return &node{tag: "!time", text: plainOrQuoted(x.HHMMSS, false)}, nil
+	case jolt.Link:
+		return &node{tag: "!link", text: plainOrQuoted(x.Ref, false)}, nil
+	case jolt.Annot:
+		return &node{tag: "!annot", text: plainOrQuoted(x.Note, false)}, nil
+	case []any:
+		n := &node{seq: true, items: []*node{}}
+		for _, it := range x {
+			c, err := toNode(it, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			n.items = append(n.items, c)
+		}
+		return n, nil
+	case map[string]any:
+		keys := make([]string, 0, len(x))
+		for k := range x {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		n := &node{keys: keys, vals: make([]*node, len(keys))}
+		for i, k := range keys {
+			c, err := toNode(x[k], depth+1)
+			if err != nil {
+				return nil, err
+			}
+			n.vals[i] = c
+		}
+		return n, nil
+	case jolt.Set:
+		items, err := canonical([]any(x))
+		if err != nil {
+			return nil, err
+		}
+		n, err := toNode(items, depth)
+		if err != nil {
+			return nil, err
+		}
+		n.tag = "!set"
+		return n, nil
+	case jolt.Map:
+		keys := make([]any, 0, len(x))
+		for k := range x {
+			keys = append(keys, k)
+		}
+		keys, err := canonical(keys)
+		if err != nil {
+			return nil, err
+		}
+		ents := make([]any, len(keys))
+		for i, k := range keys {
+			ents[i] = map[string]any{"key": k, "value": x[k]}
+		}
+		n, err := toNode(ents, depth)
+		if err != nil {
+			return nil, err
+		}
+		n.tag = "!map"
+		return n, nil
+	case jolt.Envelope:
+		return toNode(map[string]any{"$meta": x.Meta, "$body": x.Body}, depth)
+	case jolt.Extension:
+		n, err := toNode(map[string]any{"tag": jolt.BigInt(int64(x.Tag)), "value": base64.StdEncoding.EncodeToString(x.Raw)}, depth)
+		if err != nil {
+			return nil, err
+		}
+		n.tag = "!ext"
+		return n, nil
+	}
+	return otherNode(v, depth)
+}
+
+// otherNode handles Go numbers, then anything MarshalJSONCompat can write.
+func otherNode(v any, depth int) (*node, error) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &node{text: strconv.FormatInt(rv.Int(), 10)}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return &node{text: strconv.FormatUint(rv.Uint(), 10)}, nil
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		if math.IsInf(f, 0) || math.IsNaN(f) {
+			return nil, fmt.Errorf("jolt/yaml: cannot write %v", f)
+		}
+		d, err := jolt.DecFromString(strconv.FormatFloat(f, 'g', -1, rv.Type().Bits()))
+		if err != nil {
+			return nil, err
+		}
+		return decimalNode(d)
+	}
+	js, err := jolt.MarshalJSONCompat(v, false)
+	if err != nil {
+		return nil, fmt.Errorf("jolt/yaml: cannot write %T: %w", v, err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(js))
+	dec.UseNumber()
+	var raw any
+	if err := dec.Decode(&raw); err != nil {
+		return nil, err
+	}
+	lv, err := jolt.LiftTyped(raw)
+	if err != nil {
+		return nil, err
+	}
+	if reflect.TypeOf(lv) != reflect.TypeOf(v) {
+		return toNode(lv, depth)
+	}
+	// A registered extension lifts back to itself: tag its representation.
+	m, ok := raw.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("jolt/yaml: cannot write %T", v)
+	}
+	repr, err := jolt.LiftTyped(m["value"])
+	if err != nil {
+		return nil, err
+	}
+	n, err := toNode(repr, depth+1)
+	if err != nil {
+		return nil, err
+	}
+	if n.tag != "" {
+		return nil, fmt.Errorf("jolt/yaml: cannot write %T: its representation is already tagged", v)
+	}
+	n.tag = "!" + fmt.Sprint(m["@type"])
+	return n, nil
+}
+
+// canonical orders Set members and Map keys by their JOLT-B encoding.
+func canonical(items []any) ([]any, error) {
+	type enc struct {
+		b []byte
+		v any
+	}
+	es := make([]enc, len(items))
+	for i, it := range items {
+		b, err := jolt.EncodeBinary(it)
+		if err != nil {
+			return nil, err
+		}
+		es[i] = enc{b, it}
+	}
+	sort.Slice(es, func(i, j int) bool { return bytes.Compare(es[i].b, es[j].b) < 0 })
+	out := make([]any, len(es))
+	for i, e := range es {
+		out[i] = e.v
+	}
+	return out, nil
+}
+
+// decimalNode writes a Decimal plain when it reads back as the same
+// Decimal, else with !dec.
+func decimalNode(d jolt.Decimal) (*node, error) {
+	text := d.String()
+	if d.D.Form != apd.Finite {
+		return nil, fmt.Errorf("jolt/yaml: cannot write non-finite decimal %s", text)
+	}
+	if back, ok := resolvePlain(text).(jolt.Decimal); ok && back.String() == text {
+		return &node{text: text}, nil
+	}
+	return &node{tag: "!dec", text: text}, nil
+}
+
+// stringNode picks a plain, literal or double-quoted style.
+func stringNode(s string) *node {
+	if lines, header, ok := literalLines(s); ok {
+		return &node{text: header, literal: lines}
+	}
+	return &node{text: plainOrQuoted(s, true)}
+}
+
+// literalLines splits s for a literal block when its text survives one.
+func literalLines(s string) ([]string, string, bool) {
+	body := strings.TrimRight(s, "\n")
+	if !strings.Contains(body, "\n") || !utf8.ValidString(s) {
+		return nil, "", false
+	}
+	lines := strings.Split(body, "\n")
+	for _, l := range lines {
+		if l != "" && strings.Trim(l, " ") == "" {
+			return nil, "", false
+		}
+		for _, r := range l {
+			if r != '\t' && !unicode.IsPrint(r) && r != ' ' {
+				return nil, "", false
+			}
+		}
+	}
+	if first := strings.TrimLeft(body, "\n"); first[0] == ' ' || first[0] == '\t' {
+		return nil, "", false
+	}
+	header := "|"
+	switch trail := len(s) - len(body); {
+	case trail == 0:
+		header = "|-"
+	case trail > 1:
+		header = "|+"
+		lines = append(lines, make([]string, trail-1)...)
+	}
+	return lines, header, true
+}
+
+// plainOrQuoted writes s plain when a reader takes it back verbatim (and,
+// with resolves, as a string rather than a null, bool or number).
+func plainOrQuoted(s string, resolves bool) string {
+	if isPlainSafe(s) && (!resolves || resolvePlain(s) == s && !special(s)) {
+		return s
+	}
+	return quote(s)
+}
+
+func isPlainSafe(s string) bool {
+	if s == "" || strings.ContainsAny(s[:1], "-?:,[]{}#&*!|>'\"%@` \t") ||
+		strings.HasPrefix(s, "...") || strings.HasSuffix(s, " ") || strings.HasSuffix(s, ":") ||
+		strings.Contains(s, ": ") || strings.Contains(s, " #") || !utf8.ValidString(s) {
+		return false
+	}
+	for _, r := range s {
+		if r != ' ' && !unicode.IsPrint(r) {
+			return false
+		}
+	}
+	return true
+}
+
+var quoteEscapes = map[rune]string{
+	0: `\0`, '\a': `\a`, '\b': `\b`, '\t': `\t`, '\n': `\n`, '\v': `\v`, '\f': `\f`,
+	'\r': `\r`, 0x1b: `\e`, '"': `\"`, '\\': `\\`, 0x85: `\N`, 0x2028: `\L`, 0x2029: `\P`,
+}
+
+func quote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch esc, ok := quoteEscapes[r]; {
+		case ok:
+			b.WriteString(esc)
+		case r < 0x20 || r == 0x7f:
+			fmt.Fprintf(&b, `\x%02x`, r)
+		case r == utf8.RuneError || r == 0xfeff:
+			fmt.Fprintf(&b, `\u%04x`, r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
@@ -0,0 +1,852 @@
+package yaml
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/chandan-cmd-dev/jolt-go/jolt"
+)
+
+// maxAliasNodes bounds how many nodes aliases may repeat in one stream, so
+// a few lines of nested aliases cannot expand into gigabytes.
+const maxAliasNodes = 1 << 20
+
+// scalar is a scalar node before tag resolution.
+type scalar struct {
+	text  string
+	plain bool
+}
+
+type parser struct {
+	s       []byte
+	i       int
+	anchors map[string]any
+	aliased int
+}
+
+func (p *parser) errAt(off int, format string, args ...any) error {
+	line := 1 + bytes.Count(p.s[:off], []byte{'\n'})
+	col := off - (bytes.LastIndexByte(p.s[:off], '\n') + 1) + 1
+	return &jolt.SyntaxError{Line: line, Col: col, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) eof() bool { return p.i >= len(p.s) }
+
+func (p *parser) peek() byte { return p.at(0) }
+
+func (p *parser) at(k int) byte {
+	if p.i+k >= len(p.s) {
+		return 0
+	}
+	return p.s[p.i+k]
+}
+
+// col is the 0-based column of the cursor.
+func (p *parser) col() int { return p.i - (bytes.LastIndexByte(p.s[:p.i], '\n') + 1) }
+
+// isBlank reports whether c separates tokens; 0 stands for end of input.
+func isBlank(c byte) bool { return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == 0 }
+
+func isFlowIndicator(c byte) bool { return c == ',' || c == '[' || c == ']' || c == '{' || c == '}' }
+
+func (p *parser) skipInline() {
+	for p.peek() == ' ' || p.peek() == '\t' {
+		p.i++
+	}
+}
+
+// atLineEnd reports whether only a comment or a line break is left on the
+// line.
+func (p *parser) atLineEnd() bool {
+	c := p.peek()
+	return c == 0 || c == '\n' || c == '\r' || c == '#'
+}
+
+func (p *parser) skipLine() {
+	for !p.eof() && p.peek() != '\n' {
+		p.i++
+	}
+}
+
+// skipBlank moves to the next token across lines and comments.
+func (p *parser) skipBlank() error {
+	for !p.eof() {
+		switch p.peek() {
+		case ' ', '\t', '\n', '\r':
+			p.i++
+		case '#':
+			p.skipLine()
+		default:
+			start := bytes.LastIndexByte(p.s[:p.i], '\n') + 1
+			if len(bytes.Trim(p.s[start:p.i], " \t")) == 0 && bytes.IndexByte(p.s[start:p.i], '\t') >= 0 {
+				return p.errAt(p.i, "tabs are not allowed for indentation")
+			}
+			return nil
+		}
+	}
+	return nil
+}
+
+// docMarker returns "---" or "..." when the cursor is on a document marker.
+func (p *parser) docMarker() string {
+	if p.col() != 0 || p.i+3 > len(p.s) || !isBlank(p.at(3)) {
+		return ""
+	}
+	switch m := string(p.s[p.i : p.i+3]); m {
+	case "---", "...":
+		return m
+	}
+	return ""
+}
+
+func (p *parser) isDash() bool { return p.peek() == '-' && isBlank(p.at(1)) }
+
+func (p *parser) documents() ([]any, error) {
+	docs := []any{}
+	if bytes.HasPrefix(p.s, []byte("\xef\xbb\xbf")) {
+		p.i = 3
+	}
+	for {
+		if err := p.skipBlank(); err != nil {
+			return nil, err
+		}
+		directive := false
+		for !p.eof() && p.col() == 0 && p.peek() == '%' {
+			p.skipLine()
+			directive = true
+			if err := p.skipBlank(); err != nil {
+				return nil, err
+			}
+		}
+		switch p.docMarker() {
+		case "---":
+			p.i += 3
+		case "...":
+			p.i += 3
+			continue
+		default:
+			if p.eof() {
+				if directive {
+					return nil, p.errAt(p.i, "directive without a document")
+				}
+				return docs, nil
+			}
+		}
+		p.anchors = map[string]any{}
+		v, err := p.blockValue(-1, false, 0)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, v)
+		if err := p.skipBlank(); err != nil {
+			return nil, err
+		}
+		if !p.eof() && p.docMarker() == "" {
+			return nil, p.errAt(p.i, "unexpected content after the document")
+		}
+	}
+}
+
+// props reads a node's tag and anchor, in either order.
+func (p *parser) props() (tag, anchor string, err error) {
+	for {
+		switch p.peek() {
+		case '!':
+			if tag != "" {
+				return "", "", p.errAt(p.i, "node has two tags")
+			}
+			tag = p.token()
+		case '&':
+			if anchor != "" {
+				return "", "", p.errAt(p.i, "node has two anchors")
+			}
+			p.i++
+			if anchor = p.token(); anchor == "" {
+				return "", "", p.errAt(p.i, "anchor without a name")
+			}
+		default:
+			return tag, anchor, nil
+		}
+		p.skipInline()
+	}
+}
+
+// token reads up to a blank or a flow indicator.
+func (p *parser) token() string {
+	start := p.i
+	for !isBlank(p.peek()) && !isFlowIndicator(p.peek()) {
+		p.i++
+	}
+	return string(p.s[start:p.i])
+}
+
+func (p *parser) alias() (any, error) {
+	off := p.i
+	p.i++
+	name := p.token()
+	v, ok := p.anchors[name]
+	if !ok {
+		return nil, p.errAt(off, "unknown anchor %q", name)
+	}
+	if p.aliased += countNodes(v); p.aliased > maxAliasNodes {
+		return nil, p.errAt(off, "aliases expand to too many nodes")
+	}
+	return v, nil
+}
+
+func countNodes(v any) int {
+	n := 1
+	switch x := v.(type) {
+	case []any:
+		for _, e := range x {
+			n += countNodes(e)
+		}
+	case map[string]any:
+		for _, e := range x {
+			n += countNodes(e)
+		}
+	case jolt.Set:
+		n += len(x)
+	case jolt.Map:
+		n += 2 * len(x)
+	}
+	return n
+}
+
+// blockValue reads the node that follows a "key:" (afterKey), a "- " or the
+// start of a document. parent is the indentation of the enclosing
+// collection, -1 at the top.
+func (p *parser) blockValue(parent int, afterKey bool, depth int) (any, error) {
+	if depth > jolt.DefaultLimits.MaxDepth {
+		return nil, jolt.ErrTooDeep
+	}
+	p.skipInline()
+	off := p.i
+	tag, anchor, err := p.props()
+	if err != nil {
+		return nil, err
+	}
+	var v any = scalar{plain: true}
+	switch {
+	case p.atLineEnd():
+		if err := p.skipBlank(); err != nil {
+			return nil, err
+		}
+		if p.eof() || p.docMarker() != "" {
+			break
+		}
+		if c := p.col(); c > parent || c == parent && afterKey && p.isDash() {
+			if c == parent {
+				v, err = p.sequence(c, depth+1)
+			} else {
+				v, err = p.blockValue(parent, false, depth+1)
+			}
+		}
+	case p.peek() == '*':
+		if tag != "" || anchor != "" {
+			return nil, p.errAt(off, "an alias cannot have a tag or anchor")
+		}
+		if v, err = p.alias(); err != nil {
+			return nil, err
+		}
+		return v, p.endOfLine()
+	case !afterKey && p.isDash():
+		v, err = p.sequence(p.col(), depth+1)
+	default:
+		v, err = p.inlineNode(parent, !afterKey, depth)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if v, err = applyTag(tag, v); err != nil {
+		return nil, p.errAt(off, "%s", strings.TrimPrefix(err.Error(), "jolt/yaml: "))
+	}
+	if anchor != "" {
+		p.anchors[anchor] = v
+	}
+	return v, nil
+}
+
+// endOfLine rejects text after a value that ended mid-line.
+func (p *parser) endOfLine() error {
+	p.skipInline()
+	if !p.atLineEnd() {
+		return p.errAt(p.i, "unexpected %q after value", p.peek())
+	}
+	return nil
+}
+
+// inlineNode reads a node that starts on the current line.
+func (p *parser) inlineNode(parent int, allowKey bool, depth int) (any, error) {
+	switch c := p.peek(); {
+	case c == '|' || c == '>':
+		s, err := p.blockScalar(parent)
+		return scalar{text: s}, err
+	case c == '[' || c == '{':
+		v, err := p.flow(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if p.skipInline(); p.peek() == ':' {
+			return nil, p.errAt(p.i, "collections as mapping keys are not supported")
+		}
+		return v, p.endOfLine()
+	case c == '?' && isBlank(p.at(1)):
+		return nil, p.errAt(p.i, "complex mapping keys are not supported")
+	case allowKey && p.keyAhead():
+		return p.mapping(p.col(), depth+1)
+	case c == '"' || c == '\'':
+		s, err := p.quoted()
+		if err != nil {
+			return nil, err
+		}
+		return scalar{text: s}, p.endOfLine()
+	default:
+		if c == '@' || c == '`' || c == '%' || (c == '-' || c == ':') && isBlank(p.at(1)) {
+			return nil, p.errAt(p.i, "unexpected %q", c)
+		}
+		s := p.plain(parent, false)
+		return scalar{text: s, plain: true}, p.endOfLine()
+	}
+}
+
+// keyAhead reports whether the line from the cursor holds "key:".
+func (p *parser) keyAhead() bool {
+	j := p.i
+	if q := p.peek(); q == '"' || q == '\'' {
+		for j++; j < len(p.s) && p.s[j] != '\n'; j++ {
+			if q == '"' && p.s[j] == '\\' {
+				j++
+			} else if p.s[j] == q {
+				if q == '\'' && j+1 < len(p.s) && p.s[j+1] == '\'' {
+					j++
+					continue
+				}
+				break
+			}
+		}
+		for j++; j < len(p.s) && (p.s[j] == ' ' || p.s[j] == '\t'); j++ {
+		}
+		return j < len(p.s) && p.s[j] == ':' && (j+1 == len(p.s) || isBlank(p.s[j+1]))
+	}
+	for ; j < len(p.s) && p.s[j] != '\n' && p.s[j] != '\r'; j++ {
+		if p.s[j] == ':' && (j+1 == len(p.s) || isBlank(p.s[j+1])) {
+			return true
+		}
+		if p.s[j] == '#' && j > p.i && (p.s[j-1] == ' ' || p.s[j-1] == '\t') {
+			return false
+		}
+	}
+	return false
+}
+
+func (p *parser) sequence(col, depth int) (any, error) {
+	if depth > jolt.DefaultLimits.MaxDepth {
+		return nil, jolt.ErrTooDeep
+	}
+	out := []any{}
+	for {
+		p.i++ // the dash
+		v, err := p.blockValue(col, false, depth)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+		if err := p.skipBlank(); err != nil {
+			return nil, err
+		}
+		if p.eof() || p.docMarker() != "" || p.col() < col {
+			return out, nil
+		}
+		if p.col() > col {
+			return nil, p.errAt(p.i, "bad indentation of a sequence entry")
+		}
+		if !p.isDash() {
+			return out, nil
+		}
+	}
+}
+
+func (p *parser) mapping(col, depth int) (any, error) {
+	if depth > jolt.DefaultLimits.MaxDepth {
+		return nil, jolt.ErrTooDeep
+	}
+	out := map[string]any{}
+	var merges []any
+	for {
+		off := p.i
+		k, plain, err := p.key()
+		if err != nil {
+			return nil, err
+		}
+		v, err := p.blockValue(col, true, depth)
+		if err != nil {
+			return nil, err
+		}
+		if plain && k == "<<" {
+			merges = append(merges, v)
+		} else if _, dup := out[k]; dup {
+			return nil, p.errAt(off, "duplicate mapping key %q", k)
+		} else {
+			out[k] = v
+		}
+		if err := p.skipBlank(); err != nil {
+			return nil, err
+		}
+		if p.eof() || p.docMarker() != "" || p.col() < col {
+			break
+		}
+		if p.col() > col {
+			return nil, p.errAt(p.i, "bad indentation of a mapping entry")
+		}
+	}
+	if err := merge(out, merges); err != nil {
+		return nil, p.errAt(p.i, "%s", err)
+	}
+	return out, nil
+}
+
+// merge applies "<<" values: a mapping or a sequence of mappings, earlier
+// ones first, never over keys the mapping sets itself.
+func merge(out map[string]any, merges []any) error {
+	for _, m := range merges {
+		list, ok := m.([]any)
+		if !ok {
+			list = []any{m}
+		}
+		for _, e := range list {
+			src, ok := e.(map[string]any)
+			if !ok {
+				return fmt.Errorf("merge key needs a mapping or a sequence of mappings")
+			}
+			for k, v := range src {
+				if _, set := out[k]; !set {
+					out[k] = v
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// key reads a block mapping key and its ':'.
+func (p *parser) key() (k string, plain bool, err error) {
+	switch p.peek() {
+	case '"', '\'':
+		k, err = p.quoted()
+	case '?':
+		return "", false, p.errAt(p.i, "complex mapping keys are not supported")
+	default:
+		start := p.i
+		for !p.eof() && p.peek() != '\n' && !(p.peek() == ':' && isBlank(p.at(1))) {
+			p.i++
+		}
+		k, plain = strings.TrimRight(string(p.s[start:p.i]), " \t"), true
+	}
+	if err != nil {
+		return "", false, err
+	}
+	p.skipInline()
+	if p.peek() != ':' {
+		return "", false, p.errAt(p.i, "expected a mapping key")
+	}
+	p.i++
+	return k, plain, nil
+}
+
+// plain reads a plain scalar; outside flow collections it may continue on
+// lines indented past parent, and line breaks fold to spaces.
+func (p *parser) plain(parent int, flow bool) string {
+	var b strings.Builder
+	for {
+		start := p.i
+		for !p.eof() {
+			c := p.peek()
+			if c == '\n' || c == '\r' ||
+				c == ':' && (isBlank(p.at(1)) || flow && isFlowIndicator(p.at(1))) ||
+				c == '#' && p.i > start && (p.s[p.i-1] == ' ' || p.s[p.i-1] == '\t') ||
+				flow && isFlowIndicator(c) {
+				break
+			}
+			p.i++
+		}
+		b.WriteString(strings.TrimRight(string(p.s[start:p.i]), " \t"))
+		if flow || p.eof() || p.peek() == ':' || p.peek() == '#' {
+			return b.String()
+		}
+		// Look for a continuation line.
+		end, j := p.i, p.i
+		for j < len(p.s) && isBlank(p.s[j]) {
+			j++
+		}
+		breaks := countBreaks(p.s[end:j])
+		p.i = j
+		if j == len(p.s) || p.s[j] == '#' || p.col() <= parent || p.docMarker() != "" {
+			p.i = end
+			return b.String()
+		}
+		b.WriteString(folded(breaks))
+	}
+}
+
+// countBreaks counts the line breaks in b; CRLF and a lone CR count as
+// one, like LF.
+func countBreaks(b []byte) int {
+	n := 0
+	for i, c := range b {
+		if c == '\n' || c == '\r' && (i+1 == len(b) || b[i+1] != '\n') {
+			n++
+		}
+	}
+	return n
+}
+
+// folded is what a run of line breaks inside a flow scalar folds to: one
+// break is a space, and each further break a newline.
+func folded(breaks int) string {
+	if breaks <= 1 {
+		return " "
+	}
+	return strings.Repeat("\n", breaks-1)
+}
+
+// quoted reads a single- or double-quoted scalar, folding line breaks.
+func (p *parser) quoted() (string, error) {
+	q, off := p.peek(), p.i
+	p.i++
+	var b []byte
+	for {
+		if p.eof() {
+			return "", p.errAt(off, "unterminated quoted scalar")
+		}
+		c := p.peek()
+		switch {
+		case c == q && q == '\'' && p.at(1) == '\'':
+			b = append(b, '\'')
+			p.i += 2
+		case c == q:
+			p.i++
+			return string(b), nil
+		case c == '\n' || c == '\r':
+			b = bytes.TrimRight(b, " \t")
+			start := p.i
+			for !p.eof() && isBlank(p.peek()) {
+				p.i++
+			}
+			if p.docMarker() != "" {
+				return "", p.errAt(p.i, "document marker inside a quoted scalar")
+			}
+			b = append(b, folded(countBreaks(p.s[start:p.i]))...)
+		case c == '\\' && q == '"':
+			var err error
+			if b, err = p.escape(b); err != nil {
+				return "", err
+			}
+		default:
+			b = append(b, c)
+			p.i++
+		}
+	}
+}
+
+var escapes = map[byte]string{
+	'0': "\x00", 'a': "\a", 'b': "\b", 't': "\t", '\t': "\t", 'n': "\n", 'v': "\v",
+	'f': "\f", 'r': "\r", 'e': "\x1b", ' ': " ", '"': "\"", '/': "/", '\\': "\\",
+	'N': "\u0085", '_': "\u00a0", 'L': "\u2028", 'P': "\u2029",
+}
+
+func (p *parser) escape(b []byte) ([]byte, error) {
+	off := p.i
+	e := p.at(1)
+	p.i += 2
+	if s, ok := escapes[e]; ok {
+		return append(b, s...), nil
+	}
+	n := map[byte]int{'x': 2, 'u': 4, 'U': 8}[e]
+	switch {
+	case e == '\n' || e == '\r':
+		// An escaped line break joins the lines without a space.
+		if e == '\r' && p.peek() == '\n' {
+			p.i++
+		}
+		for p.peek() == ' ' || p.peek() == '\t' {
+			p.i++
+		}
+		return b, nil
+	case n > 0 && p.i+n <= len(p.s):
+		r, err := strconv.ParseUint(string(p.s[p.i:p.i+n]), 16, 32)
+		if err == nil && utf8.ValidRune(rune(r)) {
+			p.i += n
+			return utf8.AppendRune(b, rune(r)), nil
+		}
+	}
+	return nil, p.errAt(off, "bad escape in double-quoted scalar")
+}
+
+// blockScalar reads a literal (|) or folded (>) scalar with its chomping
+// and indentation indicators.
+func (p *parser) blockScalar(parent int) (string, error) {
+	literal := p.peek() == '|'
+	p.i++
+	var chomp byte
+	explicit := 0
+	for k := 0; k < 2; k++ {
+		switch c := p.peek(); {
+		case (c == '+' || c == '-') && chomp == 0:
+			chomp = c
+			p.i++
+		case c >= '1' && c <= '9' && explicit == 0:
+			explicit = int(c - '0')
+			p.i++
+		}
+	}
+	p.skipInline()
+	if p.peek() == '#' {
+		p.skipLine()
+	}
+	if !p.eof() && p.peek() != '\n' && p.peek() != '\r' {
+		return "", p.errAt(p.i, "unexpected text after block scalar header")
+	}
+	p.skipLine()
+	p.i++
+	indent := -1
+	if explicit > 0 {
+		indent = max(parent, 0) + explicit
+	}
+	var lines []string
+	for p.i < len(p.s) {
+		start := p.i
+		sp := 0
+		for p.at(sp) == ' ' {
+			sp++
+		}
+		end := bytes.IndexByte(p.s[p.i:], '\n')
+		if end < 0 {
+			end = len(p.s)
+		} else {
+			end += p.i
+		}
+		text := strings.TrimSuffix(string(p.s[p.i:end]), "\r")
+		if strings.Trim(text, " ") == "" {
+			extra := ""
+			if indent >= 0 && sp > indent {
+				extra = text[indent:]
+			}
+			lines = append(lines, extra)
+			p.i = end + 1
+			continue
+		}
+		if indent < 0 {
+			if sp <= parent {
+				p.i = start
+				break
+			}
+			indent = sp
+		}
+		if sp < indent || p.docMarker() != "" {
+			p.i = start
+			break
+		}
+		lines = append(lines, text[indent:])
+		p.i = end + 1
+	}
+	p.i = min(p.i, len(p.s))
+	trailing := 0
+	for trailing < len(lines) && lines[len(lines)-1-trailing] == "" {
+		trailing++
+	}
+	body := lines[:len(lines)-trailing]
+	var s string
+	if literal {
+		s = strings.Join(body, "\n")
+	} else {
+		s = fold(body)
+	}
+	switch {
+	case len(body) == 0:
+		if chomp == '+' {
+			return strings.Repeat("\n", trailing), nil
+		}
+		return "", nil
+	case chomp == '-':
+		return s, nil
+	case chomp == '+':
+		return s + "\n" + strings.Repeat("\n", trailing), nil
+	}
+	return s + "\n", nil
+}
+
+// fold joins folded-scalar lines: a single break between two lines
+// becomes a space, empty lines stand for breaks, and more-indented lines
+// keep theirs.
+func fold(lines []string) string {
+	var b strings.Builder
+	i := 0
+	for ; i < len(lines) && lines[i] == ""; i++ {
+		b.WriteByte('\n')
+	}
+	if i == len(lines) {
+		return b.String()
+	}
+	prev := lines[i]
+	b.WriteString(prev)
+	for i++; i < len(lines); i++ {
+		k := 0
+		for ; lines[i] == ""; i++ {
+			k++
+		}
+		l := lines[i]
+		switch {
+		case more(prev) || more(l):
+			b.WriteString(strings.Repeat("\n", k+1))
+		case k == 0:
+			b.WriteByte(' ')
+		default:
+			b.WriteString(strings.Repeat("\n", k))
+		}
+		b.WriteString(l)
+		prev = l
+	}
+	return b.String()
+}
+
+func more(l string) bool { return strings.HasPrefix(l, " ") || strings.HasPrefix(l, "\t") }
+
+// flow reads a [ ] or { } collection.
+func (p *parser) flow(depth int) (any, error) {
+	if depth > jolt.DefaultLimits.MaxDepth {
+		return nil, jolt.ErrTooDeep
+	}
+	open := p.peek()
+	off := p.i
+	p.i++
+	var seq []any
+	obj := map[string]any{}
+	for {
+		if err := p.skipFlowBlank(); err != nil {
+			return nil, err
+		}
+		switch p.peek() {
+		case 0:
+			return nil, p.errAt(off, "unterminated flow collection")
+		case ']', '}':
+			if c := p.peek(); c != open+2 {
+				return nil, p.errAt(p.i, "unexpected %q", c)
+			}
+			p.i++
+			if open == '[' {
+				if seq == nil {
+					seq = []any{}
+				}
+				return seq, nil
+			}
+			return obj, nil
+		}
+		if open == '[' {
+			v, err := p.flowNode(depth)
+			if err != nil {
+				return nil, err
+			}
+			seq = append(seq, v)
+		} else {
+			koff := p.i
+			var k string
+			var err error
+			if c := p.peek(); c == '"' || c == '\'' {
+				k, err = p.quoted()
+			} else {
+				k = p.plain(-1, true)
+			}
+			if err != nil {
+				return nil, err
+			}
+			if err := p.skipFlowBlank(); err != nil {
+				return nil, err
+			}
+			var v any
+			if p.peek() == ':' {
+				p.i++
+				if v, err = p.flowNode(depth); err != nil {
+					return nil, err
+				}
+			}
+			if _, dup := obj[k]; dup {
+				return nil, p.errAt(koff, "duplicate mapping key %q", k)
+			}
+			obj[k] = v
+		}
+		if err := p.skipFlowBlank(); err != nil {
+			return nil, err
+		}
+		switch p.peek() {
+		case ',':
+			p.i++
+		case ']', '}':
+		case 0:
+			return nil, p.errAt(off, "unterminated flow collection")
+		default:
+			return nil, p.errAt(p.i, "expected ',' or %q", open+2)
+		}
+	}
+}
+
+func (p *parser) skipFlowBlank() error {
+	for {
+		switch p.peek() {
+		case ' ', '\t', '\n', '\r':
+			p.i++
+		case '#':
+			p.skipLine()
+		default:
+			if p.docMarker() != "" {
+				return p.errAt(p.i, "document marker inside a flow collection")
+			}
+			return nil
+		}
+	}
+}
+
+func (p *parser) flowNode(depth int) (any, error) {
+	if err := p.skipFlowBlank(); err != nil {
+		return nil, err
+	}
+	off := p.i
+	tag, anchor, err := p.props()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.skipFlowBlank(); err != nil {
+		return nil, err
+	}
+	var v any
+	switch c := p.peek(); c {
+	case '[', '{':
+		v, err = p.flow(depth + 1)
+	case '*':
+		if tag != "" || anchor != "" {
+			return nil, p.errAt(off, "an alias cannot have a tag or anchor")
+		}
+		return p.alias()
+	case '"', '\'':
+		var s string
+		s, err = p.quoted()
+		v = scalar{text: s}
+	case ',', ']', '}':
+		v = scalar{plain: true}
+	default:
+		v = scalar{text: p.plain(-1, true), plain: true}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if v, err = applyTag(tag, v); err != nil {
+		return nil, p.errAt(off, "%s", strings.TrimPrefix(err.Error(), "jolt/yaml: "))
+	}
+	if anchor != "" {
+		p.anchors[anchor] = v
+	}
+	return v, nil
+}
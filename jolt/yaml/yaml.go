@@ -0,0 +1,317 @@
+// Package yaml reads YAML 1.2 into JOLT values and writes JOLT values as
+// YAML, so deployment descriptors and fixtures can enter the JOLT pipeline
+// with exact numbers:
+//
+//	replicas: 3              # Int
+//	cpu: 0.250               # Decimal, digits kept as written
+//	id: !uuid 73bca6bf-8d9d-4095-93f4-13e85485f2db
+//	since: !ts 2025-08-08T07:42:01Z
+//	zones: !set [eu-1, eu-2]
+//
+// Plain scalars follow the core schema: null, booleans, integers (decimal,
+// 0o octal, 0x hex) as Int, other numbers as Decimal (.inf and .nan are an
+// error, as JOLT decimals are finite), and everything else as strings. Rich
+// types use local tags:
+//
+//	!dec !int !uuid !ts !date !time !link !annot   on scalars
+//	!!binary (or !bin)                              base64 on a scalar
+//	!set                                            on a sequence
+//	!map                                            on a sequence of {key, value} mappings
+//	!ext                                            on a {tag, value} mapping (value in base64)
+//	!<name>                                         a registered extension's representation
+//
+// The reader takes block and flow collections, all scalar styles, comments,
+// anchors and aliases, "<<" merge keys and multi-document streams; complex
+// ("?") keys are not supported and mapping keys are always strings. The
+// writer sorts object keys and puts Set members and Map entries in
+// canonical order, so equal values produce equal text.
+package yaml
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/cockroachdb/apd/v3"
+
+	"github.com/chandan-cmd-dev/jolt-go/jolt"
+)
+
+// Unmarshal parses a stream holding at most one document; an empty stream
+// is nil.
+func Unmarshal(data []byte) (any, error) {
+	docs, err := UnmarshalAll(data)
+	if err != nil {
+		return nil, err
+	}
+	switch len(docs) {
+	case 0:
+		return nil, nil
+	case 1:
+		return docs[0], nil
+	}
+	return nil, fmt.Errorf("jolt/yaml: stream has %d documents; use UnmarshalAll", len(docs))
+}
+
+// UnmarshalAll parses every document of a stream. Anchors do not carry
+// over from one document to the next. Syntax errors are *jolt.SyntaxError.
+func UnmarshalAll(data []byte) ([]any, error) {
+	p := &parser{s: data}
+	return p.documents()
+}
+
+// Marshal writes v as a YAML document.
+func Marshal(v any) ([]byte, error) {
+	e := &emitter{}
+	if err := e.doc(v); err != nil {
+		return nil, err
+	}
+	return e.b.Bytes(), nil
+}
+
+// MarshalAll writes a stream with one document per value, each introduced
+// by "---".
+func MarshalAll(docs []any) ([]byte, error) {
+	e := &emitter{}
+	for _, v := range docs {
+		e.b.WriteString("---\n")
+		if err := e.doc(v); err != nil {
+			return nil, err
+		}
+	}
+	return e.b.Bytes(), nil
+}
+
+var (
+	intRe   = regexp.MustCompile(`^[-+]?[0-9]+$`)
+	octRe   = regexp.MustCompile(`^0o[0-7]+$`)
+	hexRe   = regexp.MustCompile(`^0x[0-9a-fA-F]+$`)
+	floatRe = regexp.MustCompile(`^[-+]?(\.[0-9]+|[0-9]+(\.[0-9]*)?)([eE][-+]?[0-9]+)?$`)
+)
+
+// resolvePlain gives an untagged plain scalar its core schema value.
+func resolvePlain(s string) any {
+	switch s {
+	case "", "~", "null", "Null", "NULL":
+		return nil
+	case "true", "True", "TRUE":
+		return true
+	case "false", "False", "FALSE":
+		return false
+	}
+	switch {
+	case intRe.MatchString(s):
+		v, err := jolt.IntFromString(strings.TrimPrefix(s, "+"))
+		if err == nil {
+			return v
+		}
+	case octRe.MatchString(s), hexRe.MatchString(s):
+		base := 8
+		if s[1] == 'x' {
+			base = 16
+		}
+		z, _ := new(big.Int).SetString(s[2:], base)
+		return jolt.Int{V: z}
+	case floatRe.MatchString(s):
+		if d, err := decimal(s); err == nil {
+			return d
+		}
+	}
+	return s
+}
+
+// special reports YAML's spellings of infinity and NaN, which have no JOLT
+// value: JOLT-B decimals are finite.
+func special(s string) bool {
+	switch s {
+	case ".inf", ".Inf", ".INF", "+.inf", "+.Inf", "+.INF", "-.inf", "-.Inf", "-.INF", ".nan", ".NaN", ".NAN":
+		return true
+	}
+	return false
+}
+
+// decimal parses a YAML float exactly; "5." is the Decimal 5.
+func decimal(s string) (jolt.Decimal, error) {
+	sign := ""
+	if s[0] == '+' || s[0] == '-' {
+		sign, s = strings.TrimPrefix(s[:1], "+"), s[1:]
+	}
+	mant, exp, hasExp := strings.Cut(s, "e")
+	if !hasExp {
+		mant, exp, hasExp = strings.Cut(s, "E")
+	}
+	if strings.HasPrefix(mant, ".") {
+		mant = "0" + mant
+	}
+	mant = strings.TrimSuffix(mant, ".")
+	if hasExp {
+		mant += "e" + exp
+	}
+	return jolt.DecFromString(sign + mant)
+}
+
+// applyTag gives a node its tagged value. Scalars arrive as scalar, with
+// empty nodes as an empty plain scalar.
+func applyTag(tag string, v any) (any, error) {
+	sc, isScalar := v.(scalar)
+	if tag == "" || tag == "!" {
+		if isScalar && (tag == "!" || !sc.plain) {
+			return sc.text, nil
+		}
+		if isScalar {
+			if special(sc.text) {
+				return nil, fmt.Errorf("jolt/yaml: %s has no JOLT value", sc.text)
+			}
+			return resolvePlain(sc.text), nil
+		}
+		return v, nil
+	}
+	if isScalar {
+		return scalarTag(tag, sc)
+	}
+	switch tag {
+	case "!!seq":
+		if _, ok := v.([]any); ok {
+			return v, nil
+		}
+	case "!!map":
+		if _, ok := v.(map[string]any); ok {
+			return v, nil
+		}
+	case "!set":
+		if arr, ok := v.([]any); ok {
+			return jolt.Set(arr), nil
+		}
+	case "!!set":
+		if m, ok := v.(map[string]any); ok {
+			out := make(jolt.Set, 0, len(m))
+			for k := range m {
+				out = append(out, k)
+			}
+			return out, nil
+		}
+	case "!map":
+		if arr, ok := v.([]any); ok {
+			return mapEntries(arr)
+		}
+	case "!ext":
+		if m, ok := v.(map[string]any); ok {
+			t, ok := m["tag"].(jolt.Int)
+			if !ok {
+				return nil, fmt.Errorf("jolt/yaml: !ext needs an integer tag")
+			}
+			return jolt.LiftTyped(map[string]any{"@type": "ext", "tag": json.Number(t.V.String()), "value": m["value"]})
+		}
+	default:
+		return extension(tag, v)
+	}
+	return nil, fmt.Errorf("jolt/yaml: %s does not apply to %s", tag, kindOf(v))
+}
+
+func kindOf(v any) string {
+	switch v.(type) {
+	case []any:
+		return "a sequence"
+	case map[string]any:
+		return "a mapping"
+	}
+	return "a scalar"
+}
+
+func mapEntries(arr []any) (any, error) {
+	out := make(jolt.Map, len(arr))
+	for _, it := range arr {
+		ent, ok := it.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("jolt/yaml: !map entries are {key, value} mappings")
+		}
+		k := ent["key"]
+		if k != nil && !reflect.TypeOf(k).Comparable() {
+			return nil, fmt.Errorf("jolt/yaml: map key of type %T is not usable as a key", k)
+		}
+		out[k] = ent["value"]
+	}
+	return out, nil
+}
+
+// extension lifts a registered extension's representation.
+func extension(tag string, repr any) (any, error) {
+	name, ok := strings.CutPrefix(tag, "!")
+	if ok && !strings.HasPrefix(name, "!") {
+		v, err := jolt.LiftTyped(map[string]any{"@type": name, "value": repr})
+		if err != nil {
+			return nil, err
+		}
+		if _, still := v.(map[string]any); !still {
+			return v, nil
+		}
+	}
+	return nil, fmt.Errorf("jolt/yaml: unknown tag %s", tag)
+}
+
+func scalarTag(tag string, sc scalar) (any, error) {
+	s := sc.text
+	switch tag {
+	case "!!str":
+		return s, nil
+	case "!!null":
+		if resolvePlain(s) == nil {
+			return nil, nil
+		}
+	case "!!bool":
+		if b, ok := resolvePlain(s).(bool); ok {
+			return b, nil
+		}
+	case "!!int", "!int":
+		if v, ok := resolvePlain(s).(jolt.Int); ok {
+			return v, nil
+		}
+	case "!!float", "!dec":
+		if floatRe.MatchString(s) {
+			return decimal(s)
+		}
+		if d, err := jolt.DecFromString(s); err == nil && d.D.Form == apd.Finite {
+			return d, nil
+		}
+	case "!!binary", "!bin":
+		b, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(s), ""))
+		if err != nil {
+			return nil, fmt.Errorf("jolt/yaml: bad base64 in %s: %w", tag, err)
+		}
+		return jolt.Binary(b), nil
+	case "!uuid":
+		return jolt.UUIDFromString(s)
+	case "!ts":
+		return jolt.Timestamp{RFC3339: s}, nil
+	case "!date":
+		return jolt.Date{YYYYMMDD: s}, nil
+	case "!time":
+		return jolt.Time{HHMMSS: s}, nil
+	case "!link":
+		return jolt.Link{Ref: s}, nil
+	case "!annot":
+		return jolt.Annot{Note: s}, nil
+	case "!set":
+		if s == "" && sc.plain {
+			return jolt.Set{}, nil
+		}
+	case "!map":
+		if s == "" && sc.plain {
+			return jolt.Map{}, nil
+		}
+	default:
+		return extension(tag, resolvePlainIf(sc))
+	}
+	return nil, fmt.Errorf("jolt/yaml: %q is not a valid %s", s, tag)
+}
+
+func resolvePlainIf(sc scalar) any {
+	if sc.plain {
+		return resolvePlain(sc.text)
+	}
+	return sc.text
+}
@@ -0,0 +1,133 @@
+package jolt_test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/chandan-cmd-dev/jolt-go/jolt"
+)
+
+func accessDoc() jolt.Envelope {
+	big, _ := jolt.IntFromString("123456789012345678901234567890")
+	return jolt.Envelope{
+		Meta: jolt.Meta{Type: "Order", Version: "1.0.0"},
+		Body: map[string]any{
+			"$id":   "SO-1",
+			"total": mustDec("42.50"),
+			"count": jolt.BigInt(300),
+			"huge":  big,
+			"ref":   "0f8fad5b-d9cb-469f-a165-70867728950e",
+			"tags":  jolt.Set{"gift"},
+			"stock": jolt.Map{jolt.BigInt(7): "A-1"},
+			"lines": []any{
+				map[string]any{"sku": "A-1", "qty": jolt.BigInt(1)},
+				map[string]any{"sku": "B-2", "qty": jolt.BigInt(3)},
+			},
+		},
+	}
+}
+
+func TestGet(t *testing.T) {
+	doc := accessDoc()
+	if id := jolt.MustGet[string](doc, "$body", "$id"); id != "SO-1" {
+		t.Fatalf("id = %q", id)
+	}
+	if typ := jolt.MustGet[string](doc, "$meta", "type"); typ != "Order" {
+		t.Fatalf("type = %q", typ)
+	}
+	if qty := jolt.MustGet[int64](doc, "$body", "lines", -1, "qty"); qty != 3 {
+		t.Fatalf("qty = %d", qty)
+	}
+	if n := jolt.MustGet[uint16](doc, "$body", "count"); n != 300 {
+		t.Fatalf("count = %d", n)
+	}
+	if d := jolt.MustGet[jolt.Decimal](doc, "$body", "count"); d.String() != "300" {
+		t.Fatalf("count as dec = %s", d)
+	}
+	if !jolt.Equal(jolt.MustGet[jolt.Decimal](doc, "$body", "total"), mustDec("42.50")) {
+		t.Fatal("total")
+	}
+	if f := jolt.MustGet[float64](doc, "$body", "total"); f != 42.5 {
+		t.Fatalf("total as float = %v", f)
+	}
+	if n := jolt.MustGet[*big.Int](doc, "$body", "huge"); n.String() != "123456789012345678901234567890" {
+		t.Fatalf("huge = %s", n)
+	}
+	if u := jolt.MustGet[jolt.UUID](doc, "$body", "ref"); u.String() != "0f8fad5b-d9cb-469f-a165-70867728950e" {
+		t.Fatalf("ref = %s", u)
+	}
+	if s := jolt.MustGet[string](doc, "$body", "stock", jolt.BigInt(7)); s != "A-1" {
+		t.Fatalf("stock[7] = %q", s)
+	}
+	if l := jolt.MustGet[[]any](doc, "$body", "tags"); len(l) != 1 {
+		t.Fatalf("tags = %v", l)
+	}
+
+	_, err := jolt.Get[int64](doc, "$body", "lines", 5, "qty")
+	var pe *jolt.PathError
+	if !errors.As(err, &pe) || pe.Path != "$.$body.lines[5]" || !errors.Is(err, jolt.ErrPathNotFound) {
+		t.Fatalf("missing index: %v", err)
+	}
+	if _, err := jolt.Get[int8](doc, "$body", "count"); err == nil || err.Error() != "jolt: $.$body.count: 300 overflows int8" {
+		t.Fatalf("overflow: %v", err)
+	}
+	if _, err := jolt.Get[int64](doc, "$body", "huge"); err == nil {
+		t.Fatal("huge should overflow int64")
+	}
+	if _, err := jolt.Get[int](doc, "$body", "total"); err == nil {
+		t.Fatal("42.50 is not an integer")
+	}
+	if _, err := jolt.Get[string](doc, "$body", "lines", 0, "qty"); err == nil || err.Error() != "jolt: $.$body.lines[0].qty: want string, got jolt.Int" {
+		t.Fatalf("type mismatch: %v", err)
+	}
+}
+
+func TestSetPathAndDelete(t *testing.T) {
+	var v any = accessDoc()
+	var err error
+	if v, err = jolt.SetPath(v, []any{"$body", "lines", 1, "qty"}, jolt.BigInt(4)); err != nil {
+		t.Fatal(err)
+	}
+	if v, err = jolt.SetPath(v, []any{"$body", "lines", 2}, map[string]any{"sku": "C-3"}); err != nil {
+		t.Fatal(err)
+	}
+	if v, err = jolt.SetPath(v, []any{"$body", "ship", "city"}, "Pune"); err != nil {
+		t.Fatal(err)
+	}
+	if v, err = jolt.SetPath(v, []any{"$meta", "version"}, "1.1.0"); err != nil {
+		t.Fatal(err)
+	}
+	if v, err = jolt.SetPath(v, []any{"$body", "stock", jolt.BigInt(9)}, "B-2"); err != nil {
+		t.Fatal(err)
+	}
+	if jolt.MustGet[int](v, "$body", "lines", 1, "qty") != 4 ||
+		jolt.MustGet[string](v, "$body", "lines", 2, "sku") != "C-3" ||
+		jolt.MustGet[string](v, "$body", "ship", "city") != "Pune" ||
+		v.(jolt.Envelope).Meta.Version != "1.1.0" ||
+		len(jolt.MustGet[jolt.Map](v, "$body", "stock")) != 2 {
+		t.Fatalf("after SetPath: %#v", v)
+	}
+	if _, err := jolt.SetPath(v, []any{"$body", "lines", 7, "qty"}, 1); err == nil {
+		t.Fatal("setting past the end of an array should fail")
+	}
+
+	if v, err = jolt.Delete(v, "$body", "lines", 0); err != nil {
+		t.Fatal(err)
+	}
+	if v, err = jolt.Delete(v, "$body", "stock", jolt.BigInt(7)); err != nil {
+		t.Fatal(err)
+	}
+	if v, err = jolt.Delete(v, "$meta", "version"); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(jolt.MustGet[[]any](v, "$body", "lines")); n != 2 {
+		t.Fatalf("%d lines left", n)
+	}
+	if jolt.MustGet[string](v, "$body", "lines", 0, "sku") != "B-2" || v.(jolt.Envelope).Meta.Version != "" {
+		t.Fatalf("after Delete: %#v", v)
+	}
+	if _, err := jolt.Delete(v, "$body", "nope"); !errors.Is(err, jolt.ErrPathNotFound) {
+		t.Fatalf("deleting a missing field: %v", err)
+	}
+}
@@ -0,0 +1,223 @@
+package jolt_test
+
+import (
+	"encoding/hex"
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/chandan-cmd-dev/jolt-go/jolt"
+	"github.com/chandan-cmd-dev/jolt-go/jolt/cbor"
+)
+
+func bigInt(s string) jolt.Int {
+	z, _ := new(big.Int).SetString(s, 10)
+	return jolt.Int{V: z}
+}
+
+// Examples from RFC 8949 appendix A (and RFC 8943 for dates) that ToCBOR
+// writes exactly.
+var cborVectors = []struct {
+	v   any
+	hex string
+}{
+	{jolt.BigInt(0), "00"},
+	{jolt.BigInt(23), "17"},
+	{jolt.BigInt(24), "1818"},
+	{jolt.BigInt(1000), "1903e8"},
+	{jolt.BigInt(1000000000000), "1b000000e8d4a51000"},
+	{bigInt("18446744073709551615"), "1bffffffffffffffff"},
+	{bigInt("18446744073709551616"), "c249010000000000000000"},
+	{bigInt("-18446744073709551616"), "3bffffffffffffffff"},
+	{bigInt("-18446744073709551617"), "c349010000000000000000"},
+	{jolt.BigInt(-1), "20"},
+	{jolt.BigInt(-1000), "3903e7"},
+	{0.0, "f90000"},
+	{math.Copysign(0, -1), "f98000"},
+	{1.1, "fb3ff199999999999a"},
+	{1.5, "f93e00"},
+	{65504.0, "f97bff"},
+	{100000.0, "fa47c35000"},
+	{3.4028234663852886e+38, "fa7f7fffff"},
+	{1.0e+300, "fb7e37e43c8800759c"},
+	{5.960464477539063e-8, "f90001"},
+	{0.00006103515625, "f90400"},
+	{-4.1, "fbc010666666666666"},
+	{math.Inf(1), "f97c00"},
+	{math.NaN(), "f97e00"},
+	{math.Inf(-1), "f9fc00"},
+	{false, "f4"},
+	{nil, "f6"},
+	{jolt.Timestamp{RFC3339: "2013-03-21T20:04:00Z"}, "c074323031332d30332d32315432303a30343a30305a"},
+	{jolt.Link{Ref: "http://www.example.com"}, "d82076687474703a2f2f7777772e6578616d706c652e636f6d"},
+	{jolt.Date{YYYYMMDD: "1940-10-09"}, "d903ec6a313934302d31302d3039"},
+	{mustDec("273.15"), "c48221196ab3"},
+	{jolt.Binary{1, 2, 3, 4}, "4401020304"},
+	{"\"\\", "62225c"},
+	{"水", "63e6b0b4"},
+	{[]any{}, "80"},
+	{[]any{jolt.BigInt(1), []any{jolt.BigInt(2), jolt.BigInt(3)}}, "8201820203"},
+	{map[string]any{"a": jolt.BigInt(1), "b": []any{jolt.BigInt(2), jolt.BigInt(3)}}, "a26161016162820203"},
+	{jolt.Map{jolt.BigInt(3): jolt.BigInt(4), jolt.BigInt(1): jolt.BigInt(2)}, "a201020304"},
+	// Deterministic order sorts encoded keys: "b" (6162) before "aa" (626161).
+	{map[string]any{"aa": jolt.BigInt(1), "b": jolt.BigInt(2)}, "a261620262616101"},
+	{jolt.Set{"b", jolt.BigInt(10), "a"}, "d90102830a61616162"},
+}
+
+func TestToCBOR(t *testing.T) {
+	for _, tc := range cborVectors {
+		got, err := cbor.ToCBOR(tc.v)
+		if err != nil {
+			t.Fatalf("%v: %v", tc.v, err)
+		}
+		if hex.EncodeToString(got) != tc.hex {
+			t.Errorf("%#v: got %x, want %s", tc.v, got, tc.hex)
+		}
+	}
+}
+
+func TestFromCBOR(t *testing.T) {
+	for _, tc := range []struct {
+		hex string
+		v   any
+	}{
+		{"1864", jolt.BigInt(100)},
+		{"c249010000000000000000", bigInt("18446744073709551616")},
+		{"3863", jolt.BigInt(-100)},
+		{"f93c00", mustDec("1")},
+		{"fbc010666666666666", mustDec("-4.1")},
+		{"c11a514b67b0", jolt.Timestamp{RFC3339: "2013-03-21T20:04:00Z"}},
+		{"c1fb41d452d9ec200000", jolt.Timestamp{RFC3339: "2013-03-21T20:04:00.5Z"}},
+		{"d8641819", jolt.Date{YYYYMMDD: "1970-01-26"}},
+		{"6449455446", "IETF"},
+		{"826161a161626163", []any{"a", map[string]any{"b": "c"}}},
+		{"5f42010243030405ff", jolt.Binary{1, 2, 3, 4, 5}},
+		{"7f657374726561646d696e67ff", "streaming"},
+		{"9f018202039f0405ffff", []any{jolt.BigInt(1), []any{jolt.BigInt(2), jolt.BigInt(3)}, []any{jolt.BigInt(4), jolt.BigInt(5)}}},
+		{"bf61610161629f0203ffff", map[string]any{"a": jolt.BigInt(1), "b": []any{jolt.BigInt(2), jolt.BigInt(3)}}},
+		{"d9d9f7f5", true},
+		{"83010203", []any{jolt.BigInt(1), jolt.BigInt(2), jolt.BigInt(3)}},
+		{"a3010203040506", jolt.Map{jolt.BigInt(1): jolt.BigInt(2), jolt.BigInt(3): jolt.BigInt(4), jolt.BigInt(5): jolt.BigInt(6)}},
+		{"c482200f", mustDec("1.5")},
+	} {
+		b, _ := hex.DecodeString(tc.hex)
+		got, err := cbor.FromCBOR(b)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.hex, err)
+		}
+		if !jolt.Equal(got, tc.v) {
+			t.Errorf("%s: got %#v, want %#v", tc.hex, got, tc.v)
+		}
+	}
+}
+
+func TestCBORRoundTrip(t *testing.T) {
+	u, _ := jolt.UUIDFromString("73bca6bf-8d9d-4095-93f4-13e85485f2db")
+	v := map[string]any{
+		"id":    u,
+		"qty":   bigInt("123456789012345678901234567890"),
+		"price": mustDec("-1999.950"),
+		"at":    jolt.Timestamp{RFC3339: "2025-08-08T07:42:01.344243Z"},
+		"on":    jolt.DateYMD(2025, 8, 8),
+		"open":  jolt.TimeHMS(9, 30, 0),
+		"note":  jolt.Annot{Note: "checked"},
+		"ext":   jolt.Extension{Tag: 0x99, Raw: []byte{5, 0x01, 'x'}},
+		"tags":  jolt.Set{"gift", jolt.BigInt(7)},
+		"grid":  jolt.Map{jolt.BigInt(1): "one", u: []any{nil, true}},
+		"link":  jolt.Link{Ref: "urn:jolt:order/1"},
+		"blob":  jolt.Binary("\x00\xff"),
+		"wrap":  map[string]any{"@type": "Order", "n": mustDec("5")},
+	}
+	b, err := cbor.ToCBOR(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := cbor.FromCBOR(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !jolt.Equal(got, v) {
+		t.Fatalf("got  %#v\nwant %#v", got, v)
+	}
+	again, _ := cbor.ToCBOR(got)
+	if string(again) != string(b) {
+		t.Fatalf("not deterministic:\n%x\n%x", b, again)
+	}
+}
+
+// Short items take one byte each, so counts must be checked against what is
+// left to read rather than the whole item.
+func TestCBORShortItemsRoundTrip(t *testing.T) {
+	for _, v := range []any{
+		[]any{jolt.BigInt(1), jolt.BigInt(2), jolt.BigInt(3)},
+		map[string]any{"a": jolt.BigInt(1), "b": jolt.BigInt(2), "c": nil},
+		jolt.Map{jolt.BigInt(1): jolt.BigInt(2), jolt.BigInt(3): jolt.BigInt(4)},
+		jolt.Set{jolt.BigInt(1), jolt.BigInt(2), jolt.BigInt(3)},
+		mustDec("1.5"),
+		mustDec("-0.5"),
+		mustDec("1E+23"),
+		mustDec("1E-30"),
+		[]any{mustDec("1.5"), mustDec("2.5")},
+	} {
+		b, err := cbor.ToCBOR(v)
+		if err != nil {
+			t.Fatalf("%v: %v", v, err)
+		}
+		got, err := cbor.FromCBOR(b)
+		if err != nil {
+			t.Fatalf("%v (%x): %v", v, b, err)
+		}
+		if !jolt.Equal(got, v) {
+			t.Errorf("%x: got %#v, want %#v", b, got, v)
+		}
+	}
+}
+
+func TestFromCBORErrors(t *testing.T) {
+	for _, h := range []string{"f7", "f0", "d74401020304", "d818456449455446", "a1800102", "fc"} {
+		b, _ := hex.DecodeString(h)
+		_, err := cbor.FromCBOR(b)
+		if err == nil {
+			t.Errorf("%s: no error", h)
+		}
+	}
+	for _, h := range []string{"f7", "f0", "d74401020304", "f97c00", "f97e00", "fbfff0000000000000"} {
+		b, _ := hex.DecodeString(h)
+		if _, err := cbor.FromCBOR(b); !errors.Is(err, cbor.ErrUnmappable) {
+			t.Errorf("%s: %v is not ErrUnmappable", h, err)
+		}
+	}
+	for _, h := range []string{"1b0000", "62e6", "0102", "9f01", "830102", "a2010203", "a2616101616102", "a201020103"} {
+		b, _ := hex.DecodeString(h)
+		if _, err := cbor.FromCBOR(b); err == nil || errors.Is(err, cbor.ErrUnmappable) {
+			t.Errorf("%s: want a malformed-input error, got %v", h, err)
+		}
+	}
+}
+
+func TestCBOREnvelope(t *testing.T) {
+	v, err := jolt.UnmarshalJSONTyped(orderJSON())
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err := jolt.AsEnvelope(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := cbor.ToCBOR(env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := cbor.FromCBOR(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	back, err := jolt.AsEnvelope(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !jolt.Equal(back, env) {
+		t.Fatalf("got  %#v\nwant %#v", back, env)
+	}
+}
@@ -0,0 +1,73 @@
+package jolt_test
+
+import (
+	"bytes"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/chandan-cmd-dev/jolt-go/jolt"
+)
+
+// buildJolt compiles cmd/jolt into a temporary directory.
+func buildJolt(t *testing.T) string {
+	t.Helper()
+	return buildCmd(t, "jolt")
+}
+
+// buildCmd compiles cmd/<name> into a temporary directory.
+func buildCmd(t *testing.T, name string) string {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("builds cmd/" + name)
+	}
+	bin := filepath.Join(t.TempDir(), name)
+	if out, err := exec.Command("go", "build", "-o", bin, "../cmd/"+name).CombinedOutput(); err != nil {
+		t.Fatalf("go build: %v\n%s", err, out)
+	}
+	return bin
+}
+
+func runJolt(t *testing.T, bin string, stdin []byte, args ...string) []byte {
+	t.Helper()
+	cmd := exec.Command(bin, args...)
+	cmd.Stdin = bytes.NewReader(stdin)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("jolt %v: %v\n%s", args, err, stderr.Bytes())
+	}
+	return out
+}
+
+// Strict JSON keeps the plain JSON reading; JSON5 is only the fallback, or
+// asked for with -format json5.
+func TestCLIEncodeJSONFallsBackToJSON5(t *testing.T) {
+	bin := buildJolt(t)
+	strict := []byte(`{"a":{"@type":"dec","value":"1.5"},"n":12}`)
+	loose := []byte(`{"a":{"@type":"dec","value":"1.5"},"n":12,}`)
+
+	var plain any
+	if err := jolt.UnmarshalJSONWithComments(strict, &plain); err != nil {
+		t.Fatal(err)
+	}
+	want, err := jolt.EncodeBinary(plain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := runJolt(t, bin, strict, "-mode", "encode"); !bytes.Equal(got, want) {
+		t.Fatalf("strict JSON: got %x, want %x", got, want)
+	}
+
+	typed, err := jolt.EncodeBinary(map[string]any{"a": mustDec("1.5"), "n": jolt.BigInt(12)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := runJolt(t, bin, loose, "-mode", "encode"); !bytes.Equal(got, typed) {
+		t.Fatalf("JSON5 fallback: got %x, want %x", got, typed)
+	}
+	if got := runJolt(t, bin, strict, "-mode", "encode", "-format", "json5"); !bytes.Equal(got, typed) {
+		t.Fatalf("-format json5: got %x, want %x", got, typed)
+	}
+}
@@ -0,0 +1,98 @@
+package jolt_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/chandan-cmd-dev/jolt-go/jolt"
+)
+
+func TestEnvelopeMetaRoundTrip(t *testing.T) {
+	env := jolt.Envelope{
+		Meta: jolt.Meta{
+			Type:     "urn:jolt:example/Order",
+			Schema:   "urn:jolt:schema/Order",
+			Version:  "2.1.0",
+			Created:  jolt.Ptr(jolt.Timestamp{RFC3339: "2025-08-08T07:42:01Z"}),
+			Features: []string{"dec"},
+			Sig:      map[string]any{"alg": "ed25519", "value": jolt.Binary{1, 2, 3}},
+			Extra:    map[string]any{"tenant": "acme", "received": jolt.Timestamp{RFC3339: "2025-08-08T07:42:02Z"}},
+		},
+		Body: map[string]any{"n": jolt.BigInt(1)},
+	}
+	bin, err := jolt.EncodeBinary(env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := jolt.DecodeBinary(bin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := out.(jolt.Envelope).Meta
+	if got.Sig == nil || got.Extra["tenant"] != "acme" || got.Extra["received"] != env.Meta.Extra["received"] {
+		t.Fatalf("meta lost fields: %+v", got)
+	}
+	again, _ := jolt.EncodeBinary(out)
+	if !bytes.Equal(bin, again) {
+		t.Fatal("envelope did not re-encode identically")
+	}
+
+	js, err := json.Marshal(env.Meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(js, []byte(`"tenant":"acme"`)) {
+		t.Fatalf("extra keys missing from JSON: %s", js)
+	}
+	var back jolt.Meta
+	if err := json.Unmarshal(js, &back); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(back.ToMap(), env.Meta.ToMap()) {
+		t.Fatalf("JSON round trip mismatch:\n%#v\n%#v", back.ToMap(), env.Meta.ToMap())
+	}
+}
+
+func TestMetaJSONSigUsesTypedForms(t *testing.T) {
+	sig := map[string]any{"key": orderID(7), "raw": jolt.Extension{Tag: 0x200, Raw: []byte{1, 2}}}
+	js, err := json.Marshal(jolt.Meta{Type: "t", Sig: sig, Extra: map[string]any{"x": sig}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(js, &raw); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(raw["sig"], raw["x"]) || !bytes.Contains(raw["sig"], []byte(`{"@type":"int","value":"7"}`)) {
+		t.Fatalf("sig not in @type form: %s", js)
+	}
+}
+
+func TestEnvelopeRejectsBadCreatedAt(t *testing.T) {
+	meta, _ := jolt.EncodeBinary(map[string]any{"type": "t", "createdAt": jolt.BigInt(5)})
+	body, _ := jolt.EncodeBinary(nil)
+	raw := append(append([]byte{0x11}, meta...), body...)
+	if _, err := jolt.DecodeBinary(raw); !errors.Is(err, jolt.ErrBadEnvelope) {
+		t.Fatalf("want ErrBadEnvelope, got %v", err)
+	}
+}
+
+func TestMetaFromMapJSONForms(t *testing.T) {
+	m, err := jolt.MetaFromMap(map[string]any{
+		"type":      "urn:jolt:demo/Echo",
+		"createdAt": map[string]any{"@type": "ts", "value": "2025-08-08T10:00:00Z"},
+		"userAgent": "curl/8",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Created == nil || m.Created.RFC3339 != "2025-08-08T10:00:00Z" || m.Extra["userAgent"] != "curl/8" {
+		t.Fatalf("parsed %+v", m)
+	}
+	if _, err := jolt.MetaFromMap(map[string]any{"createdAt": "yesterday"}); !errors.Is(err, jolt.ErrBadEnvelope) {
+		t.Fatalf("want ErrBadEnvelope for non-RFC 3339 string, got %v", err)
+	}
+}
@@ -0,0 +1,179 @@
+package jolt_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/chandan-cmd-dev/jolt-go/jolt"
+)
+
+type geoPoint struct{ Lat, Lon jolt.Decimal }
+
+const geoTag = 0x100
+
+func registerGeo(t *testing.T) {
+	t.Helper()
+	err := jolt.RegisterExtension(geoTag, reflect.TypeOf(geoPoint{}),
+		func(v any) (any, error) {
+			p := v.(geoPoint)
+			return []any{p.Lat, p.Lon}, nil
+		},
+		func(repr any) (any, error) {
+			arr, ok := repr.([]any)
+			if !ok || len(arr) != 2 {
+				return nil, fmt.Errorf("want [lat, lon], got %T", repr)
+			}
+			lat, ok1 := arr[0].(jolt.Decimal)
+			lon, ok2 := arr[1].(jolt.Decimal)
+			if !ok1 || !ok2 {
+				return nil, fmt.Errorf("lat/lon must be dec")
+			}
+			return geoPoint{Lat: lat, Lon: lon}, nil
+		},
+		"geo:point")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { jolt.UnregisterExtension(geoTag) })
+}
+
+func TestExtensionBinaryAndJSONRoundTrip(t *testing.T) {
+	registerGeo(t)
+	doc := map[string]any{"at": geoPoint{Lat: mustDec("52.52"), Lon: mustDec("13.405")}}
+
+	bin, err := jolt.EncodeBinary(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := jolt.DecodeBinary(bin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, ok := out.(map[string]any)["at"].(geoPoint)
+	if !ok || p.Lat.String() != "52.52" || p.Lon.String() != "13.405" {
+		t.Fatalf("decoded %#v", out)
+	}
+
+	js, err := jolt.MarshalJSONCompat(doc, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(js, []byte(`"@type":"geo:point"`)) {
+		t.Fatalf("missing @type in %s", js)
+	}
+	lifted, err := jolt.UnmarshalJSONTyped(js)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := jolt.EncodeBinary(lifted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(bin, again) {
+		t.Fatal("JSON round trip changed the binary form")
+	}
+}
+
+func TestUnregisteredExtensionIsOpaque(t *testing.T) {
+	registerGeo(t)
+	bin, err := jolt.EncodeBinary([]any{"x", geoPoint{Lat: mustDec("1.5"), Lon: mustDec("-2")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	jolt.UnregisterExtension(geoTag)
+
+	out, err := jolt.DecodeBinary(bin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ext, ok := out.([]any)[1].(jolt.Extension)
+	if !ok || ext.Tag != geoTag {
+		t.Fatalf("want opaque extension, got %#v", out.([]any)[1])
+	}
+	again, err := jolt.EncodeBinary(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(bin, again) {
+		t.Fatal("opaque extension did not re-encode byte-identically")
+	}
+
+	js, _ := jolt.MarshalJSONCompat(out, false)
+	lifted, err := jolt.UnmarshalJSONTyped(js)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := jolt.EncodeBinary(lifted); !bytes.Equal(bin, b) {
+		t.Fatalf("opaque extension lost through JSON: %s", js)
+	}
+}
+
+func TestRegisterExtensionCollisions(t *testing.T) {
+	registerGeo(t)
+	noop := func(v any) (any, error) { return v, nil }
+	type other struct{}
+
+	if err := jolt.RegisterExtension(0x10, reflect.TypeOf(other{}), noop, noop, "other"); !errors.Is(err, jolt.ErrReservedExtension) {
+		t.Fatalf("reserved tag accepted: %v", err)
+	}
+	cases := []struct {
+		tag  uint64
+		typ  reflect.Type
+		name string
+	}{
+		{geoTag, reflect.TypeOf(other{}), "other"},
+		{geoTag + 1, reflect.TypeOf(geoPoint{}), "other"},
+		{geoTag + 1, reflect.TypeOf(other{}), "geo:point"},
+		{geoTag + 1, reflect.TypeOf(other{}), "dec"},
+	}
+	for _, c := range cases {
+		if err := jolt.RegisterExtension(c.tag, c.typ, noop, noop, c.name); !errors.Is(err, jolt.ErrExtensionConflict) {
+			t.Errorf("RegisterExtension(%d, %s, %q) = %v, want conflict", c.tag, c.typ, c.name, err)
+		}
+	}
+}
+
+func TestUnmarshalJSONTypedLiftsWrappers(t *testing.T) {
+	v, err := jolt.UnmarshalJSONTyped(orderJSON())
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := v.(map[string]any)["$body"].(map[string]any)
+	if _, ok := body["price"].(jolt.Decimal); !ok {
+		t.Fatalf("price = %T", body["price"])
+	}
+	if _, ok := body["uuid"].(jolt.UUID); !ok {
+		t.Fatalf("uuid = %T", body["uuid"])
+	}
+	if _, ok := v.(map[string]any)["$meta"].(map[string]any)["createdAt"].(jolt.Timestamp); !ok {
+		t.Fatal("createdAt not lifted")
+	}
+}
+
+// Sets and Maps get their "@type" form from MarshalJSONCompat only; plain
+// encoding/json output is unchanged.
+func TestSetMapJSONCompat(t *testing.T) {
+	v := map[string]any{
+		"s": jolt.Set{"a"},
+		"m": jolt.Map{jolt.BigInt(2): "b", jolt.BigInt(1): "a"},
+	}
+	got, err := jolt.MarshalJSONCompat(v, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"m":{"@type":"map","value":[{"key":{"@type":"int","value":"1"},"value":"a"},{"key":{"@type":"int","value":"2"},"value":"b"}]},"s":{"@type":"set","value":["a"]}}`
+	if string(got) != want {
+		t.Fatalf("got  %s\nwant %s", got, want)
+	}
+	back, err := jolt.UnmarshalJSONTyped(got)
+	if err != nil || !jolt.Equal(back, v) {
+		t.Fatalf("round trip: %#v, %v", back, err)
+	}
+	if plain, err := json.Marshal(jolt.Set{"a"}); err != nil || string(plain) != `["a"]` {
+		t.Fatalf("json.Marshal(Set) = %s, %v", plain, err)
+	}
+}
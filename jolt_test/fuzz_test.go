@@ -0,0 +1,29 @@
+package jolt_test
+
+import (
+	"testing"
+
+	"github.com/chandan-cmd-dev/jolt-go/jolt"
+)
+
+// FuzzDecodeBinary feeds arbitrary bytes to the decoder, which must return
+// an error rather than panic; whatever decodes must encode again.
+func FuzzDecodeBinary(f *testing.F) {
+	jb, err := jolt.EncodeBinary(walkDoc())
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add(jb)
+	f.Add([]byte{0x0D, 0x01, 0x07, 0x00, 0x00})       // Map keyed by an array
+	f.Add([]byte{0x0D, 0x01, 0x08, 0x00, 0x00})       // Map keyed by an object
+	f.Add([]byte{0x0D, 0x01, 0x06, 0x01, 0xff, 0x00}) // Map keyed by binary
+	f.Fuzz(func(t *testing.T, data []byte) {
+		v, err := jolt.DecodeBinary(data)
+		if err != nil {
+			return
+		}
+		if _, err := jolt.EncodeBinary(v); err != nil {
+			t.Fatalf("decoded %#v but cannot encode it: %v", v, err)
+		}
+	})
+}
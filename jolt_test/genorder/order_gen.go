@@ -0,0 +1,273 @@
+// Code generated by joltgen from Order.jsonc. DO NOT EDIT.
+
+package genorder
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/chandan-cmd-dev/jolt-go/jolt"
+	"github.com/chandan-cmd-dev/jolt-go/joltschema"
+)
+
+var orderSchema = joltschema.MustParse([]byte(`{"additional":false,"defs":{"Line":{"fields":{"attrs":{"keys":{"type":"string"},"optional":true,"type":"map","values":{"type":"any"}},"qty":{"min":{"@type":"int","value":"1"},"type":"int"},"sku":{"minLen":{"@type":"int","value":"1"},"type":"string"}},"type":"object"}},"fields":{"$id":{"pattern":"^order:","type":"string"},"lines":{"items":{"ref":"Line"},"optional":true,"type":"array"},"number":{"pattern":"^SO-[0-9]+$","type":"string"},"price":{"min":{"@type":"int","value":"0"},"precision":{"@type":"int","value":"10"},"scale":{"@type":"int","value":"2"},"type":"dec"},"qty":{"max":{"@type":"int","value":"1000"},"min":{"@type":"int","value":"1"},"type":"int"},"status":{"default":"new","enum":["new","paid","shipped"],"type":"string"},"tags":{"items":{"type":"string"},"optional":true,"type":"array"},"uuid":{"type":"uuid"}},"id":"urn:jolt:schema/Order","type":"object"}`))
+
+// Order is generated from urn:jolt:schema/Order.
+type Order struct {
+	ID     string       `jolt:"$id"`
+	Lines  []Line       `jolt:"lines,omitempty"`
+	Number string       `jolt:"number"`
+	Price  jolt.Decimal `jolt:"price"`
+	Qty    int64        `jolt:"qty"`
+	Status string       `jolt:"status"`
+	Tags   []string     `jolt:"tags,omitempty"`
+	UUID   jolt.UUID    `jolt:"uuid"`
+}
+
+// Allowed values of Order fields.
+const (
+	OrderStatusNew     = "new"
+	OrderStatusPaid    = "paid"
+	OrderStatusShipped = "shipped"
+)
+
+// NewOrder returns an Order with the schema's defaults filled in.
+func NewOrder() *Order {
+	o := &Order{}
+	if err := o.applyDefaults(); err != nil {
+		panic(err)
+	}
+	return o
+}
+
+func (o *Order) applyDefaults() error {
+	{
+		x1, err := jolt.AsString(orderSchema.Fields["status"].Default)
+		if err != nil {
+			return fmt.Errorf("jolt: %s: %w", "$.status", err)
+		}
+		o.Status = x1
+	}
+	return nil
+}
+
+// Validate checks o against its schema and reports every violation.
+func (o *Order) Validate() error {
+	return joltschema.Validate(orderSchema, o.toJOLT()).Err()
+}
+
+// MarshalJOLT implements jolt.Marshaler.
+func (o Order) MarshalJOLT() (any, error) { return o.toJOLT(), nil }
+
+func (o Order) toJOLT() map[string]any {
+	obj := make(map[string]any, 8)
+	obj["$id"] = o.ID
+	if o.Lines != nil {
+		{
+			l1 := make([]any, len(o.Lines))
+			for i1 := range o.Lines {
+				l1[i1] = o.Lines[i1].toJOLT()
+			}
+			obj["lines"] = l1
+		}
+	}
+	obj["number"] = o.Number
+	obj["price"] = o.Price
+	obj["qty"] = jolt.BigInt(o.Qty)
+	obj["status"] = o.Status
+	if o.Tags != nil {
+		{
+			l1 := make([]any, len(o.Tags))
+			for i1 := range o.Tags {
+				l1[i1] = o.Tags[i1]
+			}
+			obj["tags"] = l1
+		}
+	}
+	obj["uuid"] = o.UUID
+	return obj
+}
+
+// UnmarshalJOLT implements jolt.Unmarshaler. Absent fields take their
+// schema default, if any, and are otherwise left unchanged.
+func (o *Order) UnmarshalJOLT(v any) error { return o.unmarshalJOLT(v, "$") }
+
+func (o *Order) unmarshalJOLT(v any, path string) error {
+	obj, err := jolt.AsObject(v)
+	if err != nil {
+		return fmt.Errorf("jolt: %s: %w", path, err)
+	}
+	if fv, ok := obj["$id"]; ok {
+		{
+			x1, err := jolt.AsString(fv)
+			if err != nil {
+				return fmt.Errorf("jolt: %s: %w", path+".$id", err)
+			}
+			o.ID = x1
+		}
+	}
+	if fv, ok := obj["lines"]; ok {
+		if fv != nil {
+			{
+				l1, err := jolt.AsList(fv)
+				if err != nil {
+					return fmt.Errorf("jolt: %s: %w", path+".lines", err)
+				}
+				s1 := make([]Line, len(l1))
+				for i1, x1 := range l1 {
+					if err := s1[i1].unmarshalJOLT(x1, path+".lines"+"["+strconv.Itoa(i1)+"]"); err != nil {
+						return err
+					}
+				}
+				o.Lines = s1
+			}
+		}
+	}
+	if fv, ok := obj["number"]; ok {
+		{
+			x1, err := jolt.AsString(fv)
+			if err != nil {
+				return fmt.Errorf("jolt: %s: %w", path+".number", err)
+			}
+			o.Number = x1
+		}
+	}
+	if fv, ok := obj["price"]; ok {
+		{
+			x1, err := jolt.AsDecimal(fv)
+			if err != nil {
+				return fmt.Errorf("jolt: %s: %w", path+".price", err)
+			}
+			o.Price = x1
+		}
+	}
+	if fv, ok := obj["qty"]; ok {
+		{
+			x1, err := jolt.AsInt64(fv)
+			if err != nil {
+				return fmt.Errorf("jolt: %s: %w", path+".qty", err)
+			}
+			o.Qty = x1
+		}
+	}
+	if fv, ok := obj["status"]; ok {
+		{
+			x1, err := jolt.AsString(fv)
+			if err != nil {
+				return fmt.Errorf("jolt: %s: %w", path+".status", err)
+			}
+			o.Status = x1
+		}
+	} else {
+		{
+			x1, err := jolt.AsString(orderSchema.Fields["status"].Default)
+			if err != nil {
+				return fmt.Errorf("jolt: %s: %w", path+".status", err)
+			}
+			o.Status = x1
+		}
+	}
+	if fv, ok := obj["tags"]; ok {
+		if fv != nil {
+			{
+				l1, err := jolt.AsList(fv)
+				if err != nil {
+					return fmt.Errorf("jolt: %s: %w", path+".tags", err)
+				}
+				s1 := make([]string, len(l1))
+				for i1, x1 := range l1 {
+					{
+						x2, err := jolt.AsString(x1)
+						if err != nil {
+							return fmt.Errorf("jolt: %s: %w", path+".tags"+"["+strconv.Itoa(i1)+"]", err)
+						}
+						s1[i1] = x2
+					}
+				}
+				o.Tags = s1
+			}
+		}
+	}
+	if fv, ok := obj["uuid"]; ok {
+		{
+			x1, err := jolt.AsUUID(fv)
+			if err != nil {
+				return fmt.Errorf("jolt: %s: %w", path+".uuid", err)
+			}
+			o.UUID = x1
+		}
+	}
+	return nil
+}
+
+// Line is generated from its schema.
+type Line struct {
+	Attrs map[string]any `jolt:"attrs,omitempty"`
+	Qty   int64          `jolt:"qty"`
+	SKU   string         `jolt:"sku"`
+}
+
+// NewLine returns a Line with the schema's defaults filled in.
+func NewLine() *Line {
+	l := &Line{}
+	return l
+}
+
+// Validate checks l against its schema and reports every violation.
+func (l *Line) Validate() error {
+	return joltschema.Validate(orderSchema.Defs["Line"], l.toJOLT()).Err()
+}
+
+// MarshalJOLT implements jolt.Marshaler.
+func (l Line) MarshalJOLT() (any, error) { return l.toJOLT(), nil }
+
+func (l Line) toJOLT() map[string]any {
+	obj := make(map[string]any, 3)
+	if l.Attrs != nil {
+		obj["attrs"] = l.Attrs
+	}
+	obj["qty"] = jolt.BigInt(l.Qty)
+	obj["sku"] = l.SKU
+	return obj
+}
+
+// UnmarshalJOLT implements jolt.Unmarshaler. Absent fields take their
+// schema default, if any, and are otherwise left unchanged.
+func (l *Line) UnmarshalJOLT(v any) error { return l.unmarshalJOLT(v, "$") }
+
+func (l *Line) unmarshalJOLT(v any, path string) error {
+	obj, err := jolt.AsObject(v)
+	if err != nil {
+		return fmt.Errorf("jolt: %s: %w", path, err)
+	}
+	if fv, ok := obj["attrs"]; ok {
+		if fv != nil {
+			{
+				m1, err := jolt.AsStringMap(fv)
+				if err != nil {
+					return fmt.Errorf("jolt: %s: %w", path+".attrs", err)
+				}
+				l.Attrs = m1
+			}
+		}
+	}
+	if fv, ok := obj["qty"]; ok {
+		{
+			x1, err := jolt.AsInt64(fv)
+			if err != nil {
+				return fmt.Errorf("jolt: %s: %w", path+".qty", err)
+			}
+			l.Qty = x1
+		}
+	}
+	if fv, ok := obj["sku"]; ok {
+		{
+			x1, err := jolt.AsString(fv)
+			if err != nil {
+				return fmt.Errorf("jolt: %s: %w", path+".sku", err)
+			}
+			l.SKU = x1
+		}
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package jolt_test
+
+import (
+	"testing"
+
+	"github.com/chandan-cmd-dev/jolt-go/jolt"
+	"github.com/chandan-cmd-dev/jolt-go/joltschema"
+)
+
+func TestInferUnionsSamples(t *testing.T) {
+	var samples []any
+	for _, src := range []string{
+		`{"$meta":{"type":"urn:jolt:example/Order","version":"2.1.0"},
+		  "$body":{"number":"SO-1","qty":2,"price":10,"at":"2025-08-08T07:42:01Z","id":"73bca6bf-8d9d-4095-93f4-13e85485f2db","tags":["a"]}}`,
+		`{"number":"SO-2","qty":1,"price":19.99,"at":"2025-08-09T10:00:00+02:00","id":"0b6f6c1e-3f4a-4b8e-9d4c-2a7f1e5c9b10","note":null}`,
+		`{"number":"SO-3","qty":5,"price":{"@type":"dec","value":"5.50"},"at":"2025-08-10T00:00:00Z","id":"not-a-uuid","note":"rush"}`,
+	} {
+		v, err := jolt.UnmarshalJSONTyped([]byte(src))
+		if err != nil {
+			t.Fatal(err)
+		}
+		samples = append(samples, v)
+	}
+	s := joltschema.Infer(samples...)
+
+	if s.Type != joltschema.Object {
+		t.Fatalf("root type %s", s.Type)
+	}
+	f := s.Fields
+	if f["qty"].Type != joltschema.Int || f["price"].Type != joltschema.Dec {
+		t.Fatalf("qty %s, price %s", f["qty"].Type, f["price"].Type)
+	}
+	if f["at"].Type != joltschema.String || f["at"].Extra["format"] != "ts" {
+		t.Fatalf("at = %+v", f["at"])
+	}
+	if _, ok := f["id"].Extra["format"]; ok {
+		t.Fatal("id has a non-UUID sample and must not be marked uuid")
+	}
+	if f["number"].Optional || !f["tags"].Optional || f["tags"].Extra["x-presence"] != 0.33 {
+		t.Fatalf("optional flags wrong: number %+v tags %+v", f["number"], f["tags"])
+	}
+	if n := f["note"]; !n.Nullable || !n.Optional || n.Type != joltschema.String {
+		t.Fatalf("note = %+v", n)
+	}
+	if _, ok := f["$meta"]; ok {
+		t.Fatal("envelope meta leaked into the body schema")
+	}
+	for i, v := range samples {
+		if env, err := jolt.AsEnvelope(v); err == nil {
+			v = env.Body
+		}
+		if vs := joltschema.Validate(s, v); vs != nil {
+			t.Errorf("sample %d fails its inferred schema: %v", i, vs)
+		}
+	}
+
+	// The draft round-trips through the schema text form.
+	js, err := s.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := joltschema.Parse(js); err != nil {
+		t.Fatalf("inferred schema does not parse: %v\n%s", err, js)
+	}
+}
+
+func TestInferMixedKinds(t *testing.T) {
+	s := joltschema.Infer(
+		map[string]any{"v": "x", "when": jolt.Date{YYYYMMDD: "2025-01-02"}, "s": jolt.Set{"a"}},
+		map[string]any{"v": jolt.BigInt(1), "when": jolt.Date{YYYYMMDD: "2025-01-03"}, "s": []any{"b"}},
+	)
+	if v := s.Fields["v"]; v.Type != joltschema.Any || len(v.Extra["x-observed"].([]any)) != 2 {
+		t.Fatalf("v = %+v", v)
+	}
+	if s.Fields["when"].Type != joltschema.Date || s.Fields["s"].Type != joltschema.Array {
+		t.Fatalf("when %s, s %s", s.Fields["when"].Type, s.Fields["s"].Type)
+	}
+}
+
+// An object widened to a Map contributes its fields as entries.
+func TestInferObjectWidensToMap(t *testing.T) {
+	samples := []any{
+		map[string]any{"m": jolt.Map{jolt.BigInt(1): jolt.BigInt(10)}},
+		map[string]any{"m": map[string]any{"eu": mustDec("2.5")}},
+	}
+	m := joltschema.Infer(samples...).Fields["m"]
+	if m.Type != joltschema.Map || m.Keys.Type != joltschema.Any || m.Values.Type != joltschema.Dec {
+		t.Fatalf("m = %+v, keys %+v, values %+v", m, m.Keys, m.Values)
+	}
+	if obs := m.Keys.Extra["x-observed"].([]any); len(obs) != 2 {
+		t.Fatalf("keys observed %v", obs)
+	}
+	s := joltschema.Infer(samples...)
+	for _, v := range samples {
+		if vs := joltschema.Validate(s, v); len(vs) > 0 {
+			t.Errorf("%v: %v", v, vs)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package jolt_test
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/chandan-cmd-dev/jolt-go/jolt"
+)
+
+// The examples of RFC 8785 sections 3.2.2 and 3.2.3.
+func TestMarshalCanonicalJSONRFC(t *testing.T) {
+	for _, name := range []string{"values", "sorting"} {
+		in, err := os.ReadFile(filepath.Join("testdata", "jcs", name+".json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := os.ReadFile(filepath.Join("testdata", "jcs", name+".canon.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var v any
+		if err := json.Unmarshal(in, &v); err != nil {
+			t.Fatal(err)
+		}
+		got, err := jolt.MarshalCanonicalJSON(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != string(want) {
+			t.Errorf("%s:\ngot  %s\nwant %s", name, got, want)
+		}
+	}
+}
+
+// The IEEE 754 samples of RFC 8785 appendix B.
+func TestMarshalCanonicalJSONNumbers(t *testing.T) {
+	for bits, want := range map[uint64]string{
+		0x0000000000000000: "0",
+		0x8000000000000000: "0",
+		0x0000000000000001: "5e-324",
+		0x8000000000000001: "-5e-324",
+		0x7fefffffffffffff: "1.7976931348623157e+308",
+		0xffefffffffffffff: "-1.7976931348623157e+308",
+		0x4340000000000000: "9007199254740992",
+		0xc340000000000000: "-9007199254740992",
+		0x4430000000000000: "295147905179352830000",
+		0x44b52d02c7e14af5: "9.999999999999997e+22",
+		0x44b52d02c7e14af6: "1e+23",
+		0x44b52d02c7e14af7: "1.0000000000000001e+23",
+		0x444b1ae4d6e2ef4e: "999999999999999700000",
+		0x444b1ae4d6e2ef4f: "999999999999999900000",
+		0x444b1ae4d6e2ef50: "1e+21",
+		0x3eb0c6f7a0b5ed8c: "9.999999999999997e-7",
+		0x3eb0c6f7a0b5ed8d: "0.000001",
+		0x41b3de4355555553: "333333333.3333332",
+		0x41b3de4355555554: "333333333.33333325",
+		0x41b3de4355555555: "333333333.3333333",
+		0x41b3de4355555556: "333333333.3333334",
+		0x41b3de4355555557: "333333333.33333343",
+		0xbecbf647612f3696: "-0.0000033333333333333333",
+		0x43143ff3c1cb0959: "1424953923781206.2",
+	} {
+		got, err := jolt.MarshalCanonicalJSON(math.Float64frombits(bits))
+		if err != nil {
+			t.Fatalf("%016x: %v", bits, err)
+		}
+		if string(got) != want {
+			t.Errorf("%016x: got %s, want %s", bits, got, want)
+		}
+	}
+	for _, bits := range []uint64{0x7fffffffffffffff, 0x7ff0000000000000} {
+		if _, err := jolt.MarshalCanonicalJSON(map[string]any{"n": math.Float64frombits(bits)}); err == nil {
+			t.Errorf("%016x: no error", bits)
+		}
+	}
+}
+
+func TestMarshalCanonicalJSONRichTypes(t *testing.T) {
+	u, _ := jolt.UUIDFromString("73bca6bf-8d9d-4095-93f4-13e85485f2db")
+	v := map[string]any{
+		"qty":   jolt.BigInt(12345678901234567),
+		"price": mustDec("1999.950"),
+		"id":    u,
+		"tags":  jolt.Set{"b", "a", jolt.BigInt(1)},
+		"note":  "<&>\u2028",
+	}
+	got, err := jolt.MarshalCanonicalJSON(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"id":{"@type":"uuid","value":"73bca6bf-8d9d-4095-93f4-13e85485f2db"},"note":"<&>` + "\u2028" + `",` +
+		`"price":{"@type":"dec","value":"1999.950"},"qty":{"@type":"int","value":"12345678901234567"},` +
+		`"tags":{"@type":"set","value":[{"@type":"int","value":"1"},"a","b"]}}`
+	if string(got) != want {
+		t.Fatalf("got  %s\nwant %s", got, want)
+	}
+	// Set order does not depend on the order members were added in.
+	v["tags"] = jolt.Set{jolt.BigInt(1), "b", "a"}
+	if again, _ := jolt.MarshalCanonicalJSON(v); string(again) != want {
+		t.Fatalf("set order leaked: %s", again)
+	}
+}
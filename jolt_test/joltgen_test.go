@@ -0,0 +1,112 @@
+package jolt_test
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chandan-cmd-dev/jolt-go/jolt"
+	"github.com/chandan-cmd-dev/jolt-go/jolt_test/genorder"
+	"github.com/chandan-cmd-dev/jolt-go/joltschema"
+)
+
+// genorder is joltgen's output for testdata/Order.jsonc; regenerate with
+//
+//	go run ./cmd/joltgen -schema jolt_test/testdata/Order.jsonc -pkg genorder -o jolt_test/genorder/order_gen.go
+func TestGenerateGoMatchesCheckedIn(t *testing.T) {
+	src, err := joltschema.GenerateGo(loadOrderSchema(t), joltschema.GoOptions{Package: "genorder", Source: "Order.jsonc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := os.ReadFile(filepath.Join("genorder", "order_gen.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(src, want) {
+		t.Fatal("genorder/order_gen.go is stale; regenerate it with cmd/joltgen")
+	}
+}
+
+func TestGeneratedRoundTrip(t *testing.T) {
+	body := orderBody(t)
+	body["lines"] = []any{map[string]any{"sku": "A-1", "qty": jolt.BigInt(2), "attrs": map[string]any{"gift": true}}}
+
+	var o genorder.Order
+	if err := jolt.DecodeInto(body, &o); err != nil {
+		t.Fatal(err)
+	}
+	if o.Number != "SO-12988" || o.Qty != 2 || o.Price.String() != "1999.95" || len(o.Lines) != 1 || o.Lines[0].SKU != "A-1" {
+		t.Fatalf("decoded %+v", o)
+	}
+	if o.Status != genorder.OrderStatusNew {
+		t.Fatalf("status default not applied: %q", o.Status)
+	}
+	if err := o.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := jolt.EncodeBinary(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body["status"] = "new"
+	want, _ := jolt.EncodeBinary(body)
+	if !bytes.Equal(got, want) {
+		t.Fatal("generated MarshalJOLT does not reproduce the input")
+	}
+}
+
+func TestGeneratedErrorsAndValidation(t *testing.T) {
+	body := orderBody(t)
+	body["lines"] = []any{map[string]any{"sku": "A-1", "qty": "two"}}
+	var o genorder.Order
+	err := o.UnmarshalJOLT(body)
+	if err == nil || !strings.Contains(err.Error(), "$.lines[0].qty") {
+		t.Fatalf("want error at $.lines[0].qty, got %v", err)
+	}
+
+	n := genorder.NewOrder()
+	n.Number = "PO-1"
+	err = n.Validate()
+	if err == nil || !strings.Contains(err.Error(), "$.number") || !strings.Contains(err.Error(), "$.qty") {
+		t.Fatalf("want number and qty violations, got %v", err)
+	}
+}
+
+func TestGenerateGoNestedAndNullable(t *testing.T) {
+	s, err := joltschema.Parse([]byte(`{
+		"id": "urn:jolt:schema/Invoice",
+		"type": "object",
+		"fields": {
+			"customer": { "type": "object", "optional": true, "fields": { "name": { "type": "string" } } },
+			"dueOn":    { "type": "date", "nullable": true },
+			"amounts":  { "type": "map", "values": { "type": "dec" } },
+			"codes":    { "type": "set", "items": { "type": "int", "nullable": true } }
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := joltschema.GenerateGo(s, joltschema.GoOptions{Package: "invoices"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), "invoice_gen.go", src, 0); err != nil {
+		t.Fatalf("generated code does not parse: %v\n%s", err, src)
+	}
+	for _, want := range []string{
+		"Customer *InvoiceCustomer",
+		"DueOn    *jolt.Date",
+		"Amounts  map[string]jolt.Decimal",
+		"Codes    []*int64",
+		"type InvoiceCustomer struct",
+	} {
+		if !bytes.Contains(src, []byte(want)) {
+			t.Errorf("generated code lacks %q", want)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package jolt_test
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/chandan-cmd-dev/jolt-go/jolt"
+)
+
+func TestJSON5(t *testing.T) {
+	data, err := os.ReadFile("testdata/json5/ops.json5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := jolt.UnmarshalJSON5(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := jolt.UnmarshalJSONTyped([]byte(`{
+		"service": "wh-1",
+		"retry-after": 30,
+		"mask": 255,
+		"ratio": 0.75,
+		"burst": 10,
+		"scale": 2E0,
+		"motd": "It's fine Aé",
+		"queues": ["orders-eu", "orders-us"],
+		"limits": {"maxLines": 500, "price": {"@type": "dec", "value": "1999.95"}}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !jolt.Equal(v, want) {
+		t.Fatalf("got  %#v\nwant %#v", v, want)
+	}
+	if d := jolt.MustGet[jolt.Decimal](v, "scale"); d.String() != "2" {
+		t.Fatalf("2. = %s", d)
+	}
+}
+
+func TestJSON5Errors(t *testing.T) {
+	cases := []struct{ in, err string }{
+		{"{a: 1,\n  b: 'x\n'}", "jolt: line 2, col 8: newline in string (escape it with \\)"},
+		{"{a: 12px}", "jolt: line 1, col 5: bad number 12px"},
+		{"{a: 1 b: 2}", `jolt: line 1, col 7: unexpected 'b', want "," or "}"`},
+		{"[1, 2", `jolt: line 1, col 6: unexpected end of input, want "," or "]"`},
+		{"{'a': 0x}", "jolt: line 1, col 7: bad hex number 0x"},
+		{"{a: -}", "jolt: line 1, col 5: bad number -"},
+		{"\n\n  /* never closed", "jolt: line 3, col 3: unterminated comment"},
+	}
+	for _, c := range cases {
+		_, err := jolt.UnmarshalJSON5([]byte(c.in))
+		var se *jolt.SyntaxError
+		if !errors.As(err, &se) || err.Error() != c.err {
+			t.Errorf("UnmarshalJSON5(%q) = %v, want %s", c.in, err, c.err)
+		}
+	}
+}
+
+// Infinity and NaN parse, but JOLT-B decimals are finite.
+func TestJSON5NonFinite(t *testing.T) {
+	v, err := jolt.UnmarshalJSON5([]byte("{a: Infinity, b: +Infinity, c: -Infinity, d: NaN, e: [1, NaN]}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for field, s := range map[string]string{"a": "Infinity", "b": "Infinity", "c": "-Infinity", "d": "NaN"} {
+		if d := jolt.MustGet[jolt.Decimal](v, field); d.String() != s {
+			t.Errorf("%s = %s, want %s", field, d, s)
+		}
+	}
+	if d := jolt.MustGet[jolt.Decimal](v, "e", 1); d.String() != "NaN" {
+		t.Errorf("e[1] = %s, want NaN", d)
+	}
+	if _, err := jolt.EncodeBinary(v); err == nil || !strings.Contains(err.Error(), "non-finite decimal") {
+		t.Fatalf("EncodeBinary: %v, want a non-finite decimal error", err)
+	}
+}
+
+func TestEncodeBinaryNonFinite(t *testing.T) {
+	for _, s := range []string{"Infinity", "-Infinity", "NaN"} {
+		if _, err := jolt.EncodeBinary(mustDec(s)); err == nil {
+			t.Errorf("EncodeBinary(%s): no error", s)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package jolt_test
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/chandan-cmd-dev/jolt-go/jolt"
+)
+
+func TestJSONCCommentsAttached(t *testing.T) {
+	data, err := os.ReadFile("testdata/jsonc/config.jsonc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := jolt.UnmarshalJSONC(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	comment := func(path ...any) string {
+		t.Helper()
+		s, err := jolt.Get[string](v, path...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+	if c := comment("$comment", "", "before"); c != "// Warehouse service configuration.\n// Reloaded on SIGHUP." {
+		t.Errorf("root before = %q", c)
+	}
+	if c := comment("$comment", "/queues", "before"); c != "/* Where orders come from.\n * Both queues are polled. */" {
+		t.Errorf("queues before = %q", c)
+	}
+	if c := comment("$comment", "/queues/0", "after"); c != "// primary" {
+		t.Errorf("queues[0] after = %q", c)
+	}
+	if c := comment("$comment", "/queues/1", "before"); c != "// fallback, drained nightly" {
+		t.Errorf("queues[1] before = %q", c)
+	}
+	if c := comment("limits", "$comment", "/price", "after"); c != "// ceiling, EUR" {
+		t.Errorf("price after = %q", c)
+	}
+	if c := comment("limits", "$comment", "", "end"); c != "// more limits to come" {
+		t.Errorf("limits end = %q", c)
+	}
+	if !jolt.Equal(jolt.MustGet[any](v, "limits", "price"), mustDec("1999.95")) {
+		t.Error("typed wrappers should still be lifted")
+	}
+}
+
+func TestJSONCRoundTripThroughBinary(t *testing.T) {
+	data, err := os.ReadFile("testdata/jsonc/config.jsonc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	jolt.PreserveComments = true
+	defer func() { jolt.PreserveComments = false }()
+
+	v, err := jolt.UnmarshalJSONC(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jb, err := jolt.EncodeBinary(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	back, err := jolt.DecodeBinary(jb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := jolt.MarshalJSONCompat(back, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `// Warehouse service configuration.
+// Reloaded on SIGHUP.
+{
+  "limits": {
+    "maxLines": {
+      "@type": "int",
+      "value": "500"
+    }, // per order
+    "price": {
+      "@type": "dec",
+      "value": "1999.95"
+    } // ceiling, EUR
+    // more limits to come
+  },
+  "name": "wh-1",
+  /* Where orders come from.
+   * Both queues are polled. */
+  "queues": [
+    "orders-eu", // primary
+    // fallback, drained nightly
+    "orders-us"
+  ]
+}
+// end of file`
+	if string(out) != want {
+		t.Fatalf("got\n%s\nwant\n%s", out, want)
+	}
+
+	// The output reads back to the same document.
+	again, err := jolt.UnmarshalJSONC(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !jolt.Equal(again, back) {
+		t.Fatalf("second pass differs: %#v", again)
+	}
+	if out2, _ := jolt.MarshalJSONCompat(again, true); string(out2) != want {
+		t.Fatalf("second pass output:\n%s", out2)
+	}
+}
+
+func TestJSONCWithoutComments(t *testing.T) {
+	v, err := jolt.UnmarshalJSONC([]byte(`{"a": [1, 2.5], "b": {"$comment": "explicit note"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := jolt.UnmarshalJSONTyped([]byte(`{"a": [1, 2.5], "b": {"$comment": "explicit note"}}`))
+	if !jolt.Equal(v, want) {
+		t.Fatalf("got %#v", v)
+	}
+	if _, err := jolt.UnmarshalJSONC([]byte("{\n  \"a\": 1\n  \"b\": 2\n}")); err == nil {
+		t.Fatal("missing comma accepted")
+	} else if se := (*jolt.SyntaxError)(nil); !errors.As(err, &se) || se.Line != 3 || se.Col != 3 {
+		t.Fatalf("error = %v", err)
+	}
+}
@@ -0,0 +1,130 @@
+package jolt_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/chandan-cmd-dev/jolt-go/jolt"
+	"github.com/chandan-cmd-dev/jolt-go/joltschema"
+)
+
+func TestJSONSchemaRoundTrip(t *testing.T) {
+	s := loadOrderSchema(t)
+	js, report, err := joltschema.ToJSONSchema(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report) != 0 {
+		t.Fatalf("unexpected report: %v", report)
+	}
+	for _, want := range []string{`"$schema": "https://json-schema.org/draft/2020-12/schema"`, `"format": "uuid"`, `"x-jolt-type": "dec"`, `"$ref": "#/$defs/Line"`, `"multipleOf": 0.01`} {
+		if !bytes.Contains(js, []byte(want)) {
+			t.Errorf("JSON Schema lacks %s", want)
+		}
+	}
+
+	back, report, err := joltschema.FromJSONSchema(js)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report) != 0 {
+		t.Fatalf("unexpected report: %v", report)
+	}
+	want, _ := jolt.EncodeBinary(s.ToValue())
+	got, _ := jolt.EncodeBinary(back.ToValue())
+	if !bytes.Equal(got, want) {
+		bj, _ := back.MarshalJSON()
+		sj, _ := s.MarshalJSON()
+		t.Fatalf("round trip changed the schema:\n got %s\nwant %s", bj, sj)
+	}
+	if vs := joltschema.Validate(back, orderBody(t)); vs != nil {
+		t.Fatal(vs)
+	}
+}
+
+func TestFromJSONSchemaMapsTypes(t *testing.T) {
+	s, report, err := joltschema.FromJSONSchema([]byte(`{
+	  "$schema": "https://json-schema.org/draft/2020-12/schema",
+	  "type": "object",
+	  "properties": {
+	    "id":      { "type": "string", "format": "uuid" },
+	    "at":      { "type": "string", "format": "date-time" },
+	    "day":     { "type": ["string", "null"], "format": "date" },
+	    "blob":    { "type": "string", "format": "byte" },
+	    "total":   { "type": "string", "x-jolt-type": "dec" },
+	    "count":   { "type": "integer", "exclusiveMinimum": 0 },
+	    "labels":  { "type": "array", "items": { "type": "string" }, "uniqueItems": true, "default": ["a"] },
+	    "prices":  { "type": "object", "additionalProperties": { "type": "number" } },
+	    "kind":    { "const": "retail", "title": "Kind" },
+	    "email":   { "type": "string", "format": "email" },
+	    "choice":  { "oneOf": [ { "type": "string" }, { "type": "integer" } ] }
+	  },
+	  "required": ["id", "at", "count"],
+	  "dependentRequired": { "day": ["at"] }
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := s.Fields
+	for name, want := range map[string]joltschema.Type{
+		"id": joltschema.UUID, "at": joltschema.TS, "day": joltschema.Date, "blob": joltschema.Bin,
+		"total": joltschema.Dec, "count": joltschema.Int, "labels": joltschema.Set, "prices": joltschema.Map,
+		"email": joltschema.String,
+	} {
+		if f[name].Type != want {
+			t.Errorf("%s: type %s, want %s", name, f[name].Type, want)
+		}
+	}
+	if !f["day"].Nullable || !f["day"].Optional || f["id"].Optional {
+		t.Error("nullable/optional not mapped")
+	}
+	if f["count"].Min.(jolt.Int).V.Int64() != 1 {
+		t.Errorf("exclusiveMinimum 0 should become min 1, got %v", f["count"].Min)
+	}
+	if _, ok := f["labels"].Default.(jolt.Set); !ok {
+		t.Errorf("set default is %T", f["labels"].Default)
+	}
+	if f["email"].Extra["format"] != "email" || f["kind"].Extra["title"] != "Kind" {
+		t.Error("annotations should be kept in Extra")
+	}
+
+	joined := strings.Join(report, "\n")
+	for _, want := range []string{`#/properties/choice: keyword "oneOf" is not supported`, `#: keyword "dependentRequired" is not supported`} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("report lacks %q:\n%s", want, joined)
+		}
+	}
+	if strings.Contains(joined, "email") || strings.Contains(joined, "title") {
+		t.Errorf("annotations must not be reported:\n%s", joined)
+	}
+}
+
+func TestToJSONSchemaReportsLoss(t *testing.T) {
+	s, err := joltschema.Parse([]byte(`{
+	  "type": "object",
+	  "fields": {
+	    "from": { "type": "ts", "min": "2025-01-01T00:00:00Z" },
+	    "byId": { "type": "map", "keys": { "type": "int" }, "values": { "type": "string" } },
+	    "owner": { "ref": "Person", "nullable": true }
+	  },
+	  "defs": { "Person": { "type": "object", "fields": { "name": { "type": "string" } } } }
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	js, report, err := joltschema.ToJSONSchema(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report) != 2 {
+		t.Fatalf("want 2 report entries, got %v", report)
+	}
+	back, _, err := joltschema.FromJSONSchema(js)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f := back.Fields; f["from"].Min == nil || f["byId"].Keys.Type != joltschema.Int || !f["owner"].Nullable || f["owner"].Ref != "Person" {
+		t.Fatalf("x-jolt annotations not restored:\n%s", js)
+	}
+}
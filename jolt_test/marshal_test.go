@@ -0,0 +1,134 @@
+package jolt_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chandan-cmd-dev/jolt-go/jolt"
+)
+
+// orderID encodes natively as a JOLT Int.
+type orderID uint64
+
+func (id orderID) MarshalJOLT() (any, error) { return jolt.BigInt(int64(id)), nil }
+func (id *orderID) UnmarshalJOLT(v any) error {
+	n, ok := v.(jolt.Int)
+	if !ok || !n.V.IsUint64() {
+		return fmt.Errorf("order id must be a non-negative int, got %T", v)
+	}
+	*id = orderID(n.V.Uint64())
+	return nil
+}
+
+// sku only knows how to render itself as text.
+type sku struct{ Family, Code string }
+
+func (s sku) MarshalText() ([]byte, error) { return []byte(s.Family + "-" + s.Code), nil }
+func (s *sku) UnmarshalText(b []byte) error {
+	fam, code, ok := strings.Cut(string(b), "-")
+	if !ok {
+		return fmt.Errorf("bad sku %q", b)
+	}
+	*s = sku{fam, code}
+	return nil
+}
+
+type orderLine struct {
+	Sku   sku          `jolt:"sku"`
+	Qty   int32        `jolt:"qty"`
+	Price jolt.Decimal `jolt:"price"`
+}
+
+type order struct {
+	ID     orderID     `jolt:"$id"`
+	Number string      `json:"number"`
+	Lines  []orderLine `jolt:"lines"`
+	Tags   jolt.Set    `jolt:"tags,omitempty"`
+	Note   *string     `jolt:"note,omitempty"`
+	Secret string      `jolt:"-"`
+}
+
+func TestMarshalerStructRoundTrip(t *testing.T) {
+	in := order{
+		ID:     9001,
+		Number: "SO-12988",
+		Lines:  []orderLine{{Sku: sku{"TOY", "42"}, Qty: 2, Price: mustDec("19.99")}},
+		Tags:   jolt.Set{"gift"},
+		Secret: "dropped",
+	}
+	bin, err := jolt.EncodeBinary(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := jolt.EncodeBinary(map[string]any{
+		"$id":    jolt.BigInt(9001),
+		"number": "SO-12988",
+		"lines":  []any{map[string]any{"sku": "TOY-42", "qty": jolt.BigInt(2), "price": mustDec("19.99")}},
+		"tags":   jolt.Set{"gift"},
+	})
+	if !bytes.Equal(bin, want) {
+		t.Fatal("struct encoding differs from the equivalent generic document")
+	}
+
+	var out order
+	if err := jolt.Unmarshal(bin, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.ID != 9001 || out.Number != "SO-12988" || out.Secret != "" || out.Note != nil {
+		t.Fatalf("decoded %+v", out)
+	}
+	if len(out.Lines) != 1 || out.Lines[0].Sku != (sku{"TOY", "42"}) || out.Lines[0].Qty != 2 || out.Lines[0].Price.String() != "19.99" {
+		t.Fatalf("decoded lines %+v", out.Lines)
+	}
+}
+
+func TestMarshalTimeFieldRoundTrip(t *testing.T) {
+	type shipment struct {
+		At   time.Time  `jolt:"at"`
+		Due  *time.Time `jolt:"due"`
+		Note string     `jolt:"note"`
+	}
+	at := time.Date(2025, 8, 8, 7, 42, 1, 500, time.FixedZone("", 2*3600))
+	due := at.Add(48 * time.Hour).UTC()
+	bin, err := jolt.EncodeBinary(shipment{At: at, Due: &due, Note: "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := jolt.EncodeBinary(map[string]any{"at": jolt.TS(at), "due": jolt.TS(due), "note": "x"})
+	if !bytes.Equal(bin, want) {
+		t.Fatal("time.Time fields should encode as Timestamps")
+	}
+	var out shipment
+	if err := jolt.Unmarshal(bin, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !out.At.Equal(at) || out.Due == nil || !out.Due.Equal(due) || out.Note != "x" {
+		t.Fatalf("decoded %+v", out)
+	}
+}
+
+func TestDecodeIntoReportsPath(t *testing.T) {
+	doc := map[string]any{"lines": []any{map[string]any{"qty": jolt.BigInt(1 << 40)}}}
+	var out order
+	err := jolt.DecodeInto(doc, &out)
+	if err == nil || !strings.Contains(err.Error(), "$.lines[0].qty") {
+		t.Fatalf("want overflow error with path, got %v", err)
+	}
+	err = jolt.DecodeInto(map[string]any{"$id": "nope"}, &out)
+	if err == nil || !strings.Contains(err.Error(), "$.$id") {
+		t.Fatalf("want UnmarshalJOLT error with path, got %v", err)
+	}
+}
+
+func TestMarshalerInJSONCompat(t *testing.T) {
+	js, err := jolt.MarshalJSONCompat(map[string]any{"id": orderID(7)}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(js, []byte(`"@type":"int"`)) {
+		t.Fatalf("MarshalJOLT not used for JSON: %s", js)
+	}
+}
@@ -0,0 +1,111 @@
+package jolt_test
+
+import (
+	"testing"
+
+	"github.com/chandan-cmd-dev/jolt-go/jolt"
+)
+
+func mergeBase() map[string]any {
+	return map[string]any{
+		"$id":   "SO-1",
+		"price": mustDec("19.99"),
+		"note":  "call first",
+		"tags":  jolt.Set{"gift", "festival"},
+		"stock": jolt.Map{jolt.BigInt(7): "A-1", jolt.BigInt(9): "B-2"},
+		"lines": []any{
+			map[string]any{"sku": "A-1", "qty": jolt.BigInt(1)},
+			map[string]any{"sku": "B-2", "qty": jolt.BigInt(2)},
+		},
+	}
+}
+
+func TestMerge3Clean(t *testing.T) {
+	base, ours, theirs := mergeBase(), mergeBase(), mergeBase()
+
+	ours["price"] = mustDec("17.50")
+	ours["tags"] = jolt.Set{"gift", "vip"}
+	ours["stock"] = jolt.Map{jolt.BigInt(7): "A-1", jolt.BigInt(9): "B-2", jolt.BigInt(11): "C-3"}
+	ours["lines"] = []any{
+		map[string]any{"sku": "A-1", "qty": jolt.BigInt(3)},
+		map[string]any{"sku": "B-2", "qty": jolt.BigInt(2)},
+	}
+
+	delete(theirs, "note")
+	theirs["tags"] = jolt.Set{"gift", "festival", "rush"}
+	theirs["stock"] = jolt.Map{jolt.BigInt(7): "A-1"}
+	theirs["lines"] = []any{
+		map[string]any{"sku": "A-1", "qty": jolt.BigInt(1), "gift": true},
+		map[string]any{"sku": "D-4", "qty": jolt.BigInt(1)},
+	}
+
+	got, conflicts := jolt.Merge3(base, ours, theirs, jolt.MergeOptions{IDFields: []string{"sku"}})
+	if len(conflicts) != 0 {
+		t.Fatalf("conflicts: %v", conflicts)
+	}
+	want := map[string]any{
+		"$id":   "SO-1",
+		"price": mustDec("17.50"),
+		"tags":  jolt.Set{"gift", "vip", "rush"},
+		"stock": jolt.Map{jolt.BigInt(7): "A-1", jolt.BigInt(11): "C-3"},
+		"lines": []any{
+			map[string]any{"sku": "A-1", "qty": jolt.BigInt(3), "gift": true},
+			map[string]any{"sku": "D-4", "qty": jolt.BigInt(1)},
+		},
+	}
+	if !jolt.Equal(got, want) {
+		t.Fatalf("got %#v", got)
+	}
+	if !jolt.Equal(base, mergeBase()) {
+		t.Fatal("Merge3 modified its input")
+	}
+}
+
+func TestMerge3Conflicts(t *testing.T) {
+	base, ours, theirs := mergeBase(), mergeBase(), mergeBase()
+	ours["price"] = mustDec("17.50")
+	theirs["price"] = mustDec("17.5") // same number, different value
+	ours["lines"].([]any)[1].(map[string]any)["qty"] = jolt.BigInt(5)
+	theirs["lines"] = theirs["lines"].([]any)[:1] // B-2 deleted
+
+	got, conflicts := jolt.Merge3(base, ours, theirs, jolt.MergeOptions{IDFields: []string{"$id", "sku"}})
+	if len(conflicts) != 2 {
+		t.Fatalf("conflicts: %v", conflicts)
+	}
+	if c := conflicts[0]; c.Path != "/lines/1" || c.Theirs != nil {
+		t.Fatalf("delete/modify conflict: %v", c)
+	}
+	if c := conflicts[1]; c.Path != "/price" || !jolt.Equal(c.Base, mustDec("19.99")) || !jolt.Equal(c.Theirs, mustDec("17.5")) {
+		t.Fatalf("price conflict: %v", c)
+	}
+	// Ours wins at conflicting paths.
+	if !jolt.Equal(got, ours) {
+		t.Fatalf("got %#v", got)
+	}
+
+	// Without identity fields arrays are whole values.
+	_, conflicts = jolt.Merge3(base, ours, theirs, jolt.MergeOptions{})
+	if len(conflicts) != 2 || conflicts[0].Path != "/lines" {
+		t.Fatalf("conflicts: %v", conflicts)
+	}
+}
+
+func TestMerge3Envelope(t *testing.T) {
+	env := func(version string, body map[string]any) jolt.Envelope {
+		return jolt.Envelope{Meta: jolt.Meta{Type: "Order", Version: version}, Body: body}
+	}
+	ours := mergeBase()
+	ours["note"] = "leave at door"
+	theirs := mergeBase()
+	theirs["price"] = mustDec("18.00")
+
+	got, conflicts := jolt.Merge3(env("1.0.0", mergeBase()), env("1.0.0", ours), env("1.1.0", theirs), jolt.MergeOptions{})
+	if len(conflicts) != 0 {
+		t.Fatalf("conflicts: %v", conflicts)
+	}
+	out := got.(jolt.Envelope)
+	body := out.Body.(map[string]any)
+	if out.Meta.Version != "1.1.0" || body["note"] != "leave at door" || !jolt.Equal(body["price"], mustDec("18.00")) {
+		t.Fatalf("got %#v", got)
+	}
+}
@@ -0,0 +1,84 @@
+package jolt_test
+
+import (
+	"testing"
+
+	"github.com/chandan-cmd-dev/jolt-go/jolt"
+)
+
+func TestMergePatchTyped(t *testing.T) {
+	doc := patchDoc()
+	patch, err := jolt.UnmarshalJSONTyped([]byte(`{
+		// null deletes, objects merge, everything else replaces
+		"a/b~c": null,
+		"price": {"@type": "dec", "value": "17.50"},
+		"tags": {"$add": ["vip"], "$remove": ["festival"]},
+		"ship": {"city": "Pune"},
+		"lines": []
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := jolt.MergePatch(doc, patch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := patchDoc()
+	delete(want, "a/b~c")
+	want["price"] = mustDec("17.50")
+	want["tags"] = jolt.Set{"gift", "vip"}
+	want["ship"] = map[string]any{"city": "Pune"}
+	want["lines"] = []any{}
+	if !jolt.Equal(got, want) {
+		t.Fatalf("got %#v", got)
+	}
+	if !jolt.Equal(doc, patchDoc()) {
+		t.Fatal("MergePatch modified its input")
+	}
+
+	// Decimals are replaced with their scale, not compared numerically.
+	got, _ = jolt.MergePatch(map[string]any{"p": mustDec("1.5")}, map[string]any{"p": mustDec("1.50")})
+	if jolt.Equal(got, map[string]any{"p": mustDec("1.5")}) {
+		t.Fatal("1.50 should replace 1.5 exactly")
+	}
+}
+
+func TestMergePatchMapAndEnvelope(t *testing.T) {
+	doc := patchDoc()
+	got, err := jolt.MergePatch(doc, map[string]any{
+		"stock": jolt.Map{jolt.BigInt(7): nil, jolt.BigInt(11): "C-3"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stock := got.(map[string]any)["stock"]
+	if !jolt.Equal(stock, jolt.Map{jolt.BigInt(9): "B-2", jolt.BigInt(11): "C-3"}) {
+		t.Fatalf("stock = %#v", stock)
+	}
+
+	env := jolt.Envelope{Meta: jolt.Meta{Type: "Order", Version: "1.0.0"}, Body: patchDoc()}
+	got, err = jolt.MergePatch(env, map[string]any{
+		"$meta": map[string]any{"version": "1.1.0"},
+		"$body": map[string]any{"number": "SO-2"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, ok := got.(jolt.Envelope)
+	if !ok || out.Meta.Type != "Order" || out.Meta.Version != "1.1.0" {
+		t.Fatalf("envelope = %#v", got)
+	}
+	if n := out.Body.(map[string]any)["number"]; n != "SO-2" {
+		t.Fatalf("number = %v", n)
+	}
+}
+
+func TestMergePatchErrors(t *testing.T) {
+	if _, err := jolt.MergePatch(patchDoc(), map[string]any{"number": map[string]any{"$add": []any{"x"}}}); err == nil {
+		t.Fatal("set markers on a string should fail")
+	}
+	env := jolt.Envelope{Meta: jolt.Meta{Type: "Order"}, Body: patchDoc()}
+	if _, err := jolt.MergePatch(env, map[string]any{"number": "SO-2"}); err == nil {
+		t.Fatal("envelope patches must go through $body")
+	}
+}
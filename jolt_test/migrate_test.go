@@ -0,0 +1,138 @@
+package jolt_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/chandan-cmd-dev/jolt-go/jolt"
+)
+
+func loadMigrations(t *testing.T) *jolt.MigrationRegistry {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "migrate", "Order.migrations.jsonc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ms, err := jolt.ParseMigrations(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reg := jolt.NewMigrationRegistry()
+	for _, m := range ms {
+		if err := reg.Register(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return reg
+}
+
+func loadEnvelope(t *testing.T, name string) jolt.Envelope {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "migrate", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := jolt.UnmarshalJSONTyped(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err := jolt.AsEnvelope(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return env
+}
+
+func TestMigrateGolden(t *testing.T) {
+	reg := loadMigrations(t)
+	in := loadEnvelope(t, "order-1.0.0.json")
+	before, _ := jolt.EncodeBinary(in)
+
+	out, err := reg.Migrate(in, "2.1.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := loadEnvelope(t, "order-2.1.0.golden.json")
+	gotB, _ := jolt.EncodeBinary(out)
+	wantB, _ := jolt.EncodeBinary(want)
+	if !bytes.Equal(gotB, wantB) {
+		js, _ := jolt.MarshalJSONCompat(out, true)
+		t.Fatalf("migrated order differs from golden:\n%s", js)
+	}
+	if after, _ := jolt.EncodeBinary(in); !bytes.Equal(before, after) {
+		t.Fatal("Migrate modified its input")
+	}
+}
+
+func TestMigrateNoPath(t *testing.T) {
+	reg := loadMigrations(t)
+	in := loadEnvelope(t, "order-1.0.0.json")
+	if _, err := reg.Migrate(in, "3.0.0"); !errors.Is(err, jolt.ErrNoMigrationPath) {
+		t.Fatalf("want ErrNoMigrationPath, got %v", err)
+	}
+	// Downgrades need their own declarations.
+	in.Meta.Version = "2.1.0"
+	if _, err := reg.Migrate(in, "2.0.0"); !errors.Is(err, jolt.ErrNoMigrationPath) {
+		t.Fatalf("want ErrNoMigrationPath, got %v", err)
+	}
+	if out, err := reg.Migrate(in, "2.1.0"); err != nil || out.Meta.Version != "2.1.0" {
+		t.Fatalf("same version should be a no-op: %v", err)
+	}
+}
+
+func TestMigrateSetsCanonicalVersion(t *testing.T) {
+	reg := loadMigrations(t)
+	out, err := reg.Migrate(loadEnvelope(t, "order-1.0.0.json"), "v2.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Meta.Version != "2.1.0" {
+		t.Fatalf("version = %q", out.Meta.Version)
+	}
+
+	// An empty chain still canonicalizes.
+	out.Meta.Version = "v2.1"
+	if again, err := reg.Migrate(out, "2.1.0"); err != nil || again.Meta.Version != "2.1.0" {
+		t.Fatalf("v2.1 -> 2.1.0: version = %q, %v", again.Meta.Version, err)
+	}
+}
+
+func TestMigrateFuncStep(t *testing.T) {
+	reg := loadMigrations(t)
+	err := reg.Register(jolt.Migration{
+		Type: "urn:jolt:example/Order", From: "2.1.0", To: "2.2.0",
+		Steps: []jolt.MigrationStep{{Op: jolt.MigrateFunc, Func: func(body any) (any, error) {
+			m := body.(map[string]any)
+			m["lineCount"] = jolt.BigInt(int64(len(m["lines"].([]any))))
+			return m, nil
+		}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := reg.Migrate(loadEnvelope(t, "order-1.0.0.json"), "2.2.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := out.Body.(map[string]any)["lineCount"].(jolt.Int); n.V.Int64() != 2 {
+		t.Fatalf("lineCount = %v", n.V)
+	}
+	if err := reg.Register(jolt.Migration{Type: "urn:jolt:example/Order", From: "2.1.0", To: "2.2.0"}); err == nil {
+		t.Fatal("duplicate migration accepted")
+	}
+}
+
+func TestParseMigrationsRejectsBadStep(t *testing.T) {
+	for _, src := range []string{
+		`{"type":"urn:x","from":"1.0.0","to":"1.1.0","steps":[{"op":"explode","path":"a"}]}`,
+		`{"type":"urn:x","from":"1.0.0","to":"1.1.0","steps":[{"op":"rename","path":"a"}]}`,
+		`{"type":"urn:x","from":"one","to":"1.1.0"}`,
+	} {
+		if _, err := jolt.ParseMigrations([]byte(src)); err == nil {
+			t.Errorf("accepted %s", src)
+		}
+	}
+}
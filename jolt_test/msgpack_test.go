@@ -0,0 +1,159 @@
+package jolt_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/chandan-cmd-dev/jolt-go/jolt"
+	"github.com/chandan-cmd-dev/jolt-go/jolt/msgpack"
+)
+
+func TestMsgPackOrderRoundTrip(t *testing.T) {
+	v, err := jolt.UnmarshalJSONTyped(orderJSON())
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err := jolt.AsEnvelope(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []any{v, env} {
+		want, err := jolt.EncodeBinary(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		mp, err := msgpack.ToMsgPack(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		back, err := msgpack.FromMsgPack(mp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := jolt.EncodeBinary(back)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Fatalf("%T: lossy round trip\ngot  %#v\nwant %#v", v, back, v)
+		}
+	}
+}
+
+func TestMsgPackRoundTrip(t *testing.T) {
+	u, _ := jolt.UUIDFromString("73bca6bf-8d9d-4095-93f4-13e85485f2db")
+	v := []any{
+		nil, true, false, "", "héllo",
+		jolt.BigInt(0), jolt.BigInt(-33), jolt.BigInt(1 << 40), bigInt("18446744073709551615"),
+		bigInt("-123456789012345678901234567890"),
+		mustDec("1999.950"), mustDec("1E+3"),
+		jolt.Timestamp{RFC3339: "2025-08-08T07:42:01.344243Z"},
+		jolt.Timestamp{RFC3339: "2025-08-08T07:42:01Z"},
+		jolt.Timestamp{RFC3339: "1969-07-20T20:17:40Z"},
+		jolt.Timestamp{RFC3339: "2025-08-08T09:42:01.500+02:00"},
+		jolt.DateYMD(2025, 8, 8), jolt.TimeHMS(9, 30, 0), u,
+		jolt.Link{Ref: "urn:jolt:order/1"}, jolt.Annot{Note: "checked"},
+		jolt.Binary{0, 1, 2},
+		jolt.Set{"b", jolt.BigInt(1)},
+		jolt.Map{jolt.BigInt(1): "one", "two": jolt.BigInt(2), u: []any{}},
+		jolt.Map{"only": "strings"},
+		jolt.Extension{Tag: 0x99, Raw: []byte{5, 1, 'x'}},
+		map[string]any{"nested": map[string]any{"k": []any{jolt.BigInt(1)}}},
+	}
+	mp, err := msgpack.ToMsgPack(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	back, err := msgpack.FromMsgPack(mp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := jolt.EncodeBinary(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := jolt.EncodeBinary(back)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("got  %#v\nwant %#v", back, v)
+	}
+	again, _ := msgpack.ToMsgPack(back)
+	if !bytes.Equal(again, mp) {
+		t.Fatalf("not deterministic:\n%x\n%x", mp, again)
+	}
+}
+
+func TestMsgPackFormat(t *testing.T) {
+	for _, tc := range []struct {
+		v   any
+		hex string
+	}{
+		{jolt.BigInt(-1), "ff"},
+		{jolt.BigInt(200), "ccc8"},
+		{jolt.BigInt(-200), "d1ff38"},
+		{map[string]any{"b": true, "a": nil}, "82a161c0a162c3"},
+		// The standard timestamp extension, 32- and 64-bit forms.
+		{jolt.Timestamp{RFC3339: "2025-01-01T00:00:00Z"}, "d6ff67748580"},
+		{jolt.Timestamp{RFC3339: "1970-01-01T00:00:00.5Z"}, "d7ff7735940000000000"},
+		{mustDec("1.5"), "c70304312e35"},
+		{jolt.Set{"b", "a"}, "c7050c92a161a162"},
+	} {
+		got, err := msgpack.ToMsgPack(tc.v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(got) != tc.hex {
+			t.Errorf("%#v: got %x, want %s", tc.v, got, tc.hex)
+		}
+	}
+	// A float written by another client reads as a Decimal.
+	b, _ := hex.DecodeString("cb3ff8000000000000")
+	if v, err := msgpack.FromMsgPack(b); err != nil || !jolt.Equal(v, mustDec("1.5")) {
+		t.Fatalf("float64: %#v, %v", v, err)
+	}
+}
+
+// Other clients write maps with non-string keys natively, not as extension 13.
+func TestMsgPackNativeMapKeys(t *testing.T) {
+	for _, tc := range []struct {
+		hex string
+		v   any
+	}{
+		{"820102a16103", jolt.Map{jolt.BigInt(1): jolt.BigInt(2), "a": jolt.BigInt(3)}},
+		{"81c0c3", jolt.Map{nil: true}},
+		{"81a161c3", map[string]any{"a": true}},
+	} {
+		b, _ := hex.DecodeString(tc.hex)
+		got, err := msgpack.FromMsgPack(b)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.hex, err)
+		}
+		if !jolt.Equal(got, tc.v) {
+			t.Errorf("%s: got %#v, want %#v", tc.hex, got, tc.v)
+		}
+	}
+}
+
+func TestMsgPackErrors(t *testing.T) {
+	for _, h := range []string{
+		"d40501",             // unknown extension type 5
+		"c1",                 // never used
+		"8190c0",             // map key that is not comparable
+		"82a161c0a161c3",     // duplicate object key
+		"820102010c",         // duplicate Map key
+		"c7010e00",           // short UUID
+		"92c0",               // truncated array
+		"c0c0",               // trailing data
+		"cb7ff0000000000000", // +Inf has no JOLT value
+		"ca7fc00000",         // nor has NaN
+		"c703044e614e",       // nor has a "NaN" decimal
+	} {
+		b, _ := hex.DecodeString(h)
+		if _, err := msgpack.FromMsgPack(b); err == nil {
+			t.Errorf("%s: no error", h)
+		}
+	}
+}
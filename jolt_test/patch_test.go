@@ -0,0 +1,155 @@
+package jolt_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/chandan-cmd-dev/jolt-go/jolt"
+)
+
+func patchDoc() map[string]any {
+	return map[string]any{
+		"number": "SO-1",
+		"price":  mustDec("19.99"),
+		"tags":   jolt.Set{"gift", "festival"},
+		"stock":  jolt.Map{jolt.BigInt(7): "A-1", jolt.BigInt(9): "B-2"},
+		"lines":  []any{map[string]any{"sku": "A-1", "qty": jolt.BigInt(1)}},
+		"a/b~c":  true,
+	}
+}
+
+func TestDiffApplyRoundTrip(t *testing.T) {
+	a := patchDoc()
+	b := patchDoc()
+	b["price"] = mustDec("17.50")
+	b["tags"] = jolt.Set{"gift", "vip"}
+	b["stock"] = jolt.Map{jolt.BigInt(7): "A-1", jolt.BigInt(11): "C-3"}
+	b["lines"] = []any{map[string]any{"sku": "A-1", "qty": jolt.BigInt(3)}, map[string]any{"sku": "B-2", "qty": jolt.BigInt(1)}}
+	delete(b, "a/b~c")
+	b["note"] = "rush"
+
+	p := jolt.Diff(a, b)
+	got, err := jolt.ApplyPatch(a, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !jolt.Equal(got, b) {
+		t.Fatalf("patched document differs from target; patch %+v", p)
+	}
+	if !jolt.Equal(a, patchDoc()) {
+		t.Fatal("ApplyPatch modified its input")
+	}
+	if len(jolt.Diff(b, b)) != 0 {
+		t.Fatal("diff of equal documents should be empty")
+	}
+
+	// The patch travels as a JOLT-B envelope.
+	jb, err := jolt.EncodeBinary(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, _ := jolt.DecodeBinary(jb)
+	if env, ok := v.(jolt.Envelope); !ok || env.Meta.Type != jolt.PatchType {
+		t.Fatalf("encoded patch is %T", v)
+	}
+	var back jolt.Patch
+	if err := jolt.Unmarshal(jb, &back); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := jolt.ApplyPatch(a, back); err != nil || !jolt.Equal(got, b) {
+		t.Fatalf("decoded patch does not reproduce target: %v", err)
+	}
+}
+
+func TestApplyPatchOps(t *testing.T) {
+	gift, _ := jolt.MemberSegment("gift")
+	seven, _ := jolt.KeySegment(jolt.BigInt(7))
+	p := jolt.Patch{
+		{Op: jolt.OpTest, Path: "/price", Value: mustDec("19.99")},
+		{Op: jolt.OpRemove, Path: "/tags/" + gift},
+		{Op: jolt.OpAdd, Path: "/tags/-", Value: "vip"},
+		{Op: jolt.OpReplace, Path: "/stock/" + seven, Value: "A-9"},
+		{Op: jolt.OpCopy, From: "/lines/0", Path: "/lines/-"},
+		{Op: jolt.OpMove, From: "/a~1b~0c", Path: "/flag"},
+		{Op: jolt.OpAdd, Path: "/lines/0", Value: map[string]any{"sku": "Z-0"}},
+	}
+	got, err := jolt.ApplyPatch(patchDoc(), p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := got.(map[string]any)
+	if !jolt.Equal(doc["tags"], jolt.Set{"festival", "vip"}) {
+		t.Errorf("tags = %v", doc["tags"])
+	}
+	if doc["stock"].(jolt.Map)[findKey(doc["stock"].(jolt.Map), 7)] != "A-9" {
+		t.Errorf("stock = %v", doc["stock"])
+	}
+	if lines := doc["lines"].([]any); len(lines) != 3 || lines[0].(map[string]any)["sku"] != "Z-0" {
+		t.Errorf("lines = %v", lines)
+	}
+	if doc["flag"] != true || doc["a/b~c"] != nil {
+		t.Errorf("move failed: %v", doc)
+	}
+}
+
+func findKey(m jolt.Map, n int64) any {
+	for k := range m {
+		if i, ok := k.(jolt.Int); ok && i.V.Int64() == n {
+			return k
+		}
+	}
+	return nil
+}
+
+func TestApplyPatchFailures(t *testing.T) {
+	for name, tc := range map[string]struct {
+		op   jolt.Operation
+		want error
+	}{
+		"decimal scale": {jolt.Operation{Op: jolt.OpTest, Path: "/price", Value: mustDec("19.990")}, jolt.ErrPatchTestFailed},
+		"missing field": {jolt.Operation{Op: jolt.OpReplace, Path: "/nope", Value: 1}, jolt.ErrPathNotFound},
+		"set member":    {jolt.Operation{Op: jolt.OpRemove, Path: "/tags/$m:AAAA"}, jolt.ErrPathNotFound},
+		"index":         {jolt.Operation{Op: jolt.OpRemove, Path: "/lines/5"}, jolt.ErrPathNotFound},
+	} {
+		if _, err := jolt.ApplyPatch(patchDoc(), jolt.Patch{tc.op}); !errors.Is(err, tc.want) {
+			t.Errorf("%s: want %v, got %v", name, tc.want, err)
+		}
+	}
+}
+
+func TestApplyPatchInsideSetMember(t *testing.T) {
+	a := map[string]any{"name": "a", "qty": jolt.BigInt(1)}
+	doc := map[string]any{"s": jolt.Set{a, map[string]any{"name": "b"}}}
+	seg, _ := jolt.MemberSegment(a)
+	got, err := jolt.ApplyPatch(doc, jolt.Patch{
+		{Op: jolt.OpReplace, Path: "/s/" + seg + "/name", Value: "c"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{"s": jolt.Set{map[string]any{"name": "c", "qty": jolt.BigInt(1)}, map[string]any{"name": "b"}}}
+	if !jolt.Equal(got, want) {
+		t.Fatalf("got %#v", got)
+	}
+	pair := jolt.Set{map[string]any{"name": "a"}, map[string]any{"name": "b"}}
+	seg, _ = jolt.MemberSegment(pair[0])
+	_, err = jolt.ApplyPatch(pair, jolt.Patch{{Op: jolt.OpReplace, Path: "/" + seg + "/name", Value: "b"}})
+	if err == nil || !strings.Contains(err.Error(), "became equal") {
+		t.Fatalf("an edit that makes two members equal should fail, got %v", err)
+	}
+}
+
+func TestDiffEnvelope(t *testing.T) {
+	a := jolt.Envelope{Meta: jolt.Meta{Type: "urn:jolt:example/Order", Version: "2.1.0"}, Body: patchDoc()}
+	b := jolt.Envelope{Meta: jolt.Meta{Type: "urn:jolt:example/Order", Version: "2.2.0"}, Body: patchDoc()}
+	b.Body.(map[string]any)["number"] = "SO-2"
+	p := jolt.Diff(a, b)
+	if len(p) != 2 || p[1].Path != "/$body/number" {
+		t.Fatalf("patch = %+v", p)
+	}
+	got, err := jolt.ApplyPatch(a, p)
+	if err != nil || !jolt.Equal(got, b) {
+		t.Fatalf("envelope patch failed: %v", err)
+	}
+}
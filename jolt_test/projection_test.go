@@ -0,0 +1,157 @@
+package jolt_test
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/chandan-cmd-dev/jolt-go/jolt"
+	"github.com/chandan-cmd-dev/jolt-go/jolt/projection"
+)
+
+func loadViews(t *testing.T) *projection.Registry {
+	t.Helper()
+	data, err := os.ReadFile("testdata/projection/orders.views.jsonc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	vs, err := projection.ParseViews(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reg, err := projection.NewRegistry(vs...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return reg
+}
+
+func projectionDoc() jolt.Envelope {
+	return jolt.Envelope{
+		Meta: jolt.Meta{Type: "Order", Version: "1.0.0"},
+		Body: map[string]any{
+			"$id":    "SO-1",
+			"status": "paid",
+			"total":  mustDec("42.50"),
+			"pii":    map[string]any{"email": "ana@example.com"},
+			"ship":   map[string]any{"city": "Pune"},
+			"stock":  jolt.Map{jolt.BigInt(7): "A-1"},
+			"lines": []any{
+				map[string]any{"sku": "A-1", "price": mustDec("10.00")},
+				map[string]any{"sku": "B-2", "price": mustDec("32.50")},
+			},
+		},
+	}
+}
+
+func TestProjectionViews(t *testing.T) {
+	reg := loadViews(t)
+	jb, err := jolt.EncodeBinary(projectionDoc())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]map[string]any{
+		"support": {
+			"$id":    "SO-1",
+			"status": "paid",
+			"pii":    map[string]any{"email": "****example.com"},
+			"ship":   map[string]any{"city": "Pune"},
+		},
+		"finance": {
+			"$id":   "SO-1",
+			"total": mustDec("42.50"),
+			"stock": jolt.Map{jolt.BigInt(7): "A-1"},
+			"lines": []any{
+				map[string]any{"price": mustDec("10.00")},
+				map[string]any{"price": mustDec("32.50")},
+			},
+		},
+		"partner": {"$id": "SO-1", "status": "paid"},
+	}
+	for role, body := range want {
+		v, err := reg.For(role)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out, err := v.ProjectBinary(jb)
+		if err != nil {
+			t.Fatalf("%s: %v", role, err)
+		}
+		got, err := jolt.DecodeBinary(out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b := jolt.MustGet[any](got, "$body"); !jolt.Equal(b, body) {
+			t.Errorf("%s sees %#v", role, b)
+		}
+		if view := jolt.MustGet[string](got, "$meta", projection.MetaKey); view != role {
+			t.Errorf("%s: $meta.view = %q", role, view)
+		}
+
+		// The decoded path gives the same result.
+		dv, err := v.Project(projectionDoc())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !jolt.Equal(dv, got) {
+			t.Errorf("%s: Project = %#v, ProjectBinary = %#v", role, dv, got)
+		}
+	}
+}
+
+func TestProjectionCheckMergePatch(t *testing.T) {
+	reg := loadViews(t)
+	support, _ := reg.For("support")
+	for _, tc := range []struct {
+		patch map[string]any
+		ok    bool
+	}{
+		{map[string]any{"$body": map[string]any{"status": "shipped", "pii": map[string]any{"email": nil}}}, true},
+		{map[string]any{"$body": map[string]any{"ship": map[string]any{"city": "Goa"}}}, true},
+		{map[string]any{"$body": map[string]any{"total": mustDec("1")}}, false},
+		{map[string]any{"$body": map[string]any{"status": "x", "lines": nil}}, false},
+		{map[string]any{"$body": "replaced"}, false},
+		{map[string]any{"$meta": map[string]any{"version": "9.0.0"}}, false},
+	} {
+		err := support.CheckMergePatch(tc.patch)
+		if tc.ok != (err == nil) || err != nil && !errors.Is(err, projection.ErrNotAllowed) {
+			t.Errorf("%v: %v", tc.patch, err)
+		}
+	}
+}
+
+func TestProjectionRoles(t *testing.T) {
+	reg := loadViews(t)
+	jb, _ := jolt.EncodeBinary(projectionDoc())
+
+	r := httptest.NewRequest("GET", "/orders/SO-1", nil)
+	out, err := reg.ProjectRequest(r, jb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := jolt.DecodeBinary(out)
+	if jolt.MustGet[string](got, "$meta", projection.MetaKey) != "partner" {
+		t.Fatalf("no role should get the default view: %#v", got)
+	}
+
+	r = r.WithContext(projection.WithRole(context.Background(), "finance"))
+	if projection.RoleFrom(r.Context()) != "finance" {
+		t.Fatal("role lost")
+	}
+	out, _ = reg.ProjectRequest(r, jb)
+	got, _ = jolt.DecodeBinary(out)
+	if _, err := jolt.Get[any](got, "$body", "status"); err == nil {
+		t.Fatal("finance should not see status")
+	}
+
+	r = r.WithContext(projection.WithRole(context.Background(), "auditor"))
+	if _, err := reg.ProjectRequest(r, jb); !errors.Is(err, projection.ErrNoView) {
+		t.Fatalf("unknown role: %v", err)
+	}
+
+	if _, err := projection.NewRegistry(&projection.View{Name: "a", Default: true}, &projection.View{Name: "b", Default: true}); err == nil {
+		t.Fatal("two default views should be rejected")
+	}
+}
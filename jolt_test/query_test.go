@@ -0,0 +1,137 @@
+package jolt_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/chandan-cmd-dev/jolt-go/jolt"
+)
+
+func queryDoc() jolt.Envelope {
+	return jolt.Envelope{
+		Meta: jolt.Meta{Type: "Order", Version: "2.1.0"},
+		Body: map[string]any{
+			"$id":     "SO-1",
+			"placed":  jolt.Timestamp{RFC3339: "2025-03-01T10:00:00+01:00"},
+			"tags":    jolt.Set{"gift", "vip"},
+			"stock":   jolt.Map{jolt.BigInt(7): "A-1", "main": "B-2"},
+			"ship-to": map[string]any{"city": "Pune", "price": mustDec("5.00")},
+			"lines": []any{
+				map[string]any{"sku": "A-1", "qty": jolt.BigInt(1), "price": mustDec("19.99")},
+				map[string]any{"sku": "B-2", "qty": jolt.BigInt(3), "price": mustDec("2.50"), "tags": jolt.Set{"fragile"}},
+				map[string]any{"sku": "C-3", "qty": jolt.BigInt(5), "price": mustDec("100")},
+			},
+		},
+	}
+}
+
+func paths(ms []jolt.Match) []string {
+	out := []string{}
+	for _, m := range ms {
+		out = append(out, m.Path)
+	}
+	return out
+}
+
+func TestQueryTreeAndBinary(t *testing.T) {
+	doc := queryDoc()
+	jb, err := jolt.EncodeBinary(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		expr string
+		want []string
+	}{
+		{"$body.lines[?(@.qty > 2)].price", []string{"/$body/lines/1/price", "/$body/lines/2/price"}},
+		{"$.$body.lines[-1].sku", []string{"/$body/lines/2/sku"}},
+		{"$body['ship-to'].city", []string{"/$body/ship-to/city"}},
+		{"$meta.type", []string{"/$meta/type"}},
+		{"$body.lines[*].sku", []string{"/$body/lines/0/sku", "/$body/lines/1/sku", "/$body/lines/2/sku"}},
+		{"..price", []string{"/$body/lines/0/price", "/$body/lines/1/price", "/$body/lines/2/price", "/$body/ship-to/price"}},
+		{"$body.lines[?(@.price == dec('19.990'))].sku", []string{"/$body/lines/0/sku"}},
+		{"$body.lines[?(@.price >= 20)].sku", []string{"/$body/lines/2/sku"}},
+		{"$body.lines[?(@.tags && !(@.qty < 3))].sku", []string{"/$body/lines/1/sku"}},
+		{"$body.lines[?('fragile' in @.tags || @.sku == 'A-1')].sku", []string{"/$body/lines/0/sku", "/$body/lines/1/sku"}},
+		{"$[?('vip' in @.tags)].$id", []string{"/$body/$id"}},
+		{"$[?(@.placed > ts('2025-03-01T09:30:00Z'))]", []string{}},
+		{"$[?(@.placed < ts('2025-03-01T09:30:00Z'))].placed", []string{"/$body/placed"}},
+		{"$body.stock.main", []string{"/$body/stock/main"}},
+		{"$body.nope[0]", []string{}},
+	}
+	for _, c := range cases {
+		tree, err := jolt.Query(doc, c.expr)
+		if err != nil {
+			t.Fatalf("%s: %v", c.expr, err)
+		}
+		if got := paths(tree); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s on tree: got %v, want %v", c.expr, got, c.want)
+		}
+		bin, err := jolt.QueryBinary(jb, c.expr)
+		if err != nil {
+			t.Fatalf("%s: %v", c.expr, err)
+		}
+		if got := paths(bin); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s on view: got %v, want %v", c.expr, got, c.want)
+		}
+		for i, m := range bin {
+			v, err := m.Value.(jolt.View).Decode()
+			if err != nil || !jolt.Equal(v, tree[i].Value) {
+				t.Errorf("%s: view match %s decodes to %v", c.expr, m.Path, v)
+			}
+		}
+	}
+}
+
+func TestQueryPathsResolve(t *testing.T) {
+	doc := queryDoc()
+	ms, err := jolt.Query(doc, "$body.*[*]")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ms) != 9 { // 3 lines, 2 ship-to fields, 2 stock entries, 2 tags
+		t.Fatalf("got %v", paths(ms))
+	}
+	// Every match path is a pointer ApplyPatch understands.
+	for _, m := range ms {
+		p := jolt.Patch{{Op: jolt.OpTest, Path: m.Path, Value: m.Value}}
+		if _, err := jolt.ApplyPatch(doc, p); err != nil {
+			t.Errorf("%s: %v", m.Path, err)
+		}
+	}
+}
+
+func TestQueryErrors(t *testing.T) {
+	for _, expr := range []string{"lines[", "lines[?(@.qty > )]", "lines[?(@.qty > 2]", "a..", "lines['x", "x[?(dec(1) == 1)]"} {
+		if _, err := jolt.ParsePath(expr); err == nil {
+			t.Errorf("%q: expected an error", expr)
+		}
+	}
+	if _, err := jolt.NewView([]byte{0x07, 0x02, 0x00}); err == nil {
+		t.Error("truncated array should not make a view")
+	}
+}
+
+func TestView(t *testing.T) {
+	jb, _ := jolt.EncodeBinary(queryDoc())
+	v, err := jolt.NewView(jb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, ok := v.Field("$body")
+	if !ok || body.Len() != 6 {
+		t.Fatalf("body: %v %d", ok, body.Len())
+	}
+	lines, _ := body.Field("lines")
+	second, ok := lines.Index(1)
+	if !ok {
+		t.Fatal("no lines[1]")
+	}
+	sku, _ := second.Field("sku")
+	if s, _ := sku.Decode(); s != "B-2" {
+		t.Fatalf("sku = %v", s)
+	}
+	if _, ok := lines.Index(3); ok {
+		t.Fatal("index past the end")
+	}
+}
@@ -0,0 +1,147 @@
+package jolt_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/chandan-cmd-dev/jolt-go/jolt"
+	"github.com/chandan-cmd-dev/jolt-go/jolt/redact"
+	"github.com/chandan-cmd-dev/jolt-go/joltschema"
+)
+
+func loadPolicy(t *testing.T) *redact.Policy {
+	t.Helper()
+	data, err := os.ReadFile("testdata/redact/orders.policy.jsonc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := redact.ParsePolicy(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Key = []byte("test-key")
+	p.Schema = joltschema.MustParse([]byte(`{
+		"type": "object",
+		"fields": {
+			"contacts": { "type": "map", "keys": { "type": "string" }, "values": { "ref": "Contact" } }
+		},
+		"defs": { "Contact": { "type": "string", "pii": true } }
+	}`))
+	return p
+}
+
+func redactDoc() jolt.Envelope {
+	return jolt.Envelope{
+		Meta: jolt.Meta{Type: "Order", Version: "1.0.0"},
+		Body: map[string]any{
+			"$id": "SO-1",
+			"pii": map[string]any{
+				"email":  "ana@example.com",
+				"phones": jolt.Set{"+4912345", "+4967890"},
+			},
+			"ship":     map[string]any{"street": "Main St 1", "city": "Pune"},
+			"notes":    []any{"leave at the door", "ok"},
+			"contacts": jolt.Map{"billing": "Ana Lima"},
+		},
+	}
+}
+
+func TestRedactPolicy(t *testing.T) {
+	p := loadPolicy(t)
+	doc := redactDoc()
+	got, err := p.Apply(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := got.(jolt.Envelope)
+	if env.Meta.Extra[redact.MetaKey] != "support-2025-03" {
+		t.Fatalf("meta = %#v", env.Meta)
+	}
+	email := jolt.MustGet[string](env, "$body", "pii", "email")
+	if !strings.HasPrefix(email, redact.HashPrefix) || strings.Contains(email, "ana") {
+		t.Fatalf("email = %q", email)
+	}
+	phones := jolt.MustGet[jolt.Set](env, "$body", "pii", "phones")
+	if !jolt.Equal(phones, jolt.Set{"******45", "******90"}) {
+		t.Fatalf("phones = %v", phones)
+	}
+	if n := jolt.MustGet[string](env, "$body", "notes", 0); n != "leave" {
+		t.Fatalf("notes[0] = %q", n)
+	}
+	if _, err := jolt.Get[any](env, "$body", "ship", "street"); err == nil {
+		t.Fatal("street should be dropped")
+	}
+	if c := jolt.MustGet[string](env, "$body", "contacts", "billing"); c != "********" {
+		t.Fatalf("contact = %q", c)
+	}
+	if !jolt.Equal(doc, redactDoc()) {
+		t.Fatal("Apply modified its input")
+	}
+
+	// Stored JOLT-B redacts to the same bytes.
+	jb, _ := jolt.EncodeBinary(doc)
+	out, err := p.ApplyBinary(jb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, _ := jolt.EncodeBinary(got); string(out) != string(want) {
+		t.Fatal("ApplyBinary differs from Apply")
+	}
+
+	// Hashes are keyed.
+	p.Key = []byte("other-key")
+	again, _ := p.Apply(doc)
+	if jolt.MustGet[string](again, "$body", "pii", "email") == email {
+		t.Fatal("hash ignores the key")
+	}
+}
+
+func TestRedactMetaRules(t *testing.T) {
+	doc := jolt.Envelope{
+		Meta: jolt.Meta{Type: "Order", Schema: "urn:jolt:schema/Internal", Extra: map[string]any{"tenant": "acme"}},
+		Body: map[string]any{"$id": "SO-1"},
+	}
+	jb, _ := jolt.EncodeBinary(doc)
+	for _, version := range []string{"v1", ""} {
+		p, err := redact.ParsePolicy([]byte(`{"version": "` + version + `", "rules": [
+			{"path": "$meta.schema", "action": "drop"},
+			{"path": "$meta.tenant", "action": "mask"}
+		]}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := p.Apply(doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		meta := got.(jolt.Envelope).Meta
+		if meta.Schema != "" || meta.Extra["tenant"] != "****" {
+			t.Fatalf("%q: meta = %#v", version, meta)
+		}
+		out, err := p.ApplyBinary(jb)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want, _ := jolt.EncodeBinary(got); string(out) != string(want) {
+			back, _ := jolt.DecodeBinary(out)
+			t.Fatalf("%q: ApplyBinary differs from Apply: %#v", version, back)
+		}
+	}
+}
+
+func TestRedactPolicyErrors(t *testing.T) {
+	for _, src := range []string{
+		`{"version": "1", "rules": [{"action": "mask"}]}`,
+		`{"version": "1", "rules": [{"field": "a", "path": "b", "action": "mask"}]}`,
+		`{"version": "1", "rules": [{"field": "a", "action": "shred"}]}`,
+	} {
+		if _, err := redact.ParsePolicy([]byte(src)); err == nil {
+			t.Errorf("%s: expected an error", src)
+		}
+	}
+	p, _ := redact.ParsePolicy([]byte(`{"version": "1", "rules": [{"path": "**.email", "action": "hash"}]}`))
+	if _, err := p.Apply(map[string]any{"email": "x"}); err == nil {
+		t.Fatal("hashing without a key should fail")
+	}
+}
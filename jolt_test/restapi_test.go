@@ -0,0 +1,149 @@
+package jolt_test
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os/exec"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chandan-cmd-dev/jolt-go/jolt"
+)
+
+// startRestAPI runs cmd/restapi with args on a free local port and returns
+// its base URL.
+func startRestAPI(t *testing.T, args ...string) string {
+	t.Helper()
+	bin := buildCmd(t, "restapi")
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+	cmd := exec.Command(bin, append([]string{"-addr", addr}, args...)...)
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { cmd.Process.Kill(); cmd.Wait() })
+	for i := 0; i < 100; i++ {
+		if c, err := net.Dial("tcp", addr); err == nil {
+			c.Close()
+			return "http://" + addr
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatal("restapi did not start")
+	return ""
+}
+
+func TestRestAPIConcurrentPatches(t *testing.T) {
+	base := startRestAPI(t)
+	// A large order keeps each merge busy long enough for patches to overlap.
+	lines := strings.Repeat(`{"sku":"TOY-42","qty":2,"price":{"@type":"dec","value":"19.99"}},`, 5000)
+	order := `{"$body":{"$id":"o1","lines":[` + strings.TrimSuffix(lines, ",") + `]}}`
+	res, err := http.Post(base+"/orders", "application/json", strings.NewReader(order))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		t.Fatalf("create: %s", res.Status)
+	}
+
+	const n = 32
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			req, _ := http.NewRequest(http.MethodPatch, base+"/orders/o1", strings.NewReader(fmt.Sprintf(`{"$body":{"k%d":%d}}`, i, i)))
+			req.Header.Set("Content-Type", "application/merge-patch+json")
+			res, err := http.DefaultClient.Do(req)
+			if err != nil {
+				errs <- err
+				return
+			}
+			res.Body.Close()
+			if res.StatusCode != http.StatusOK {
+				errs <- fmt.Errorf("patch %d: %s", i, res.Status)
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, base+"/orders/o1", nil)
+	req.Header.Set("Accept", "application/jolt")
+	res, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jb, _ := io.ReadAll(res.Body)
+	res.Body.Close()
+	v, err := jolt.DecodeBinary(jb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := v.(map[string]any)["$body"].(map[string]any)
+	for i := 0; i < n; i++ {
+		if _, ok := body[fmt.Sprintf("k%d", i)]; !ok {
+			t.Errorf("patch k%d was lost", i)
+		}
+	}
+}
+
+func TestRestAPIPatchRespectsViews(t *testing.T) {
+	base := startRestAPI(t, "-views", "testdata/projection/orders.views.jsonc")
+	res, err := http.Post(base+"/orders", "application/json", strings.NewReader(`{"$body":{"$id":"o1","status":"paid","total":{"@type":"dec","value":"42.50"}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+
+	patch := func(role, body string) int {
+		req, _ := http.NewRequest(http.MethodPatch, base+"/orders/o1", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/merge-patch+json")
+		req.Header.Set("X-Role", role)
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		return res.StatusCode
+	}
+	if code := patch("auditor", `{"$body":{"status":"void"}}`); code != http.StatusForbidden {
+		t.Fatalf("role without a view: %d", code)
+	}
+	if code := patch("partner", `{"$body":{"total":{"@type":"dec","value":"0"}}}`); code != http.StatusForbidden {
+		t.Fatalf("field outside the view: %d", code)
+	}
+	if code := patch("partner", `{"$body":{"status":"shipped"}}`); code != http.StatusOK {
+		t.Fatalf("allowed field: %d", code)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, base+"/orders/o1", nil)
+	req.Header.Set("Accept", "application/jolt")
+	req.Header.Set("X-Role", "finance")
+	res, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jb, _ := io.ReadAll(res.Body)
+	res.Body.Close()
+	v, err := jolt.DecodeBinary(jb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total := jolt.MustGet[jolt.Decimal](v, "$body", "total"); total.String() != "42.50" {
+		t.Fatalf("denied patch was stored: total = %s", total)
+	}
+}
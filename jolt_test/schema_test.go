@@ -0,0 +1,148 @@
+package jolt_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/chandan-cmd-dev/jolt-go/jolt"
+	"github.com/chandan-cmd-dev/jolt-go/joltschema"
+)
+
+func loadOrderSchema(t *testing.T) *joltschema.Schema {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "Order.jsonc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := joltschema.Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func orderBody(t *testing.T) map[string]any {
+	t.Helper()
+	v, err := jolt.UnmarshalJSONTyped(orderJSON())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v.(map[string]any)["$body"].(map[string]any)
+}
+
+func TestSchemaValidatesFixture(t *testing.T) {
+	if vs := joltschema.Validate(loadOrderSchema(t), orderBody(t)); vs != nil {
+		t.Fatalf("fixture should validate: %v", vs)
+	}
+}
+
+func TestSchemaReportsAllViolations(t *testing.T) {
+	body := orderBody(t)
+	body["number"] = "PO-1"
+	body["price"] = mustDec("123456789.999")
+	body["qty"] = jolt.BigInt(0)
+	body["status"] = "lost"
+	body["extra"] = true
+	body["lines"] = []any{map[string]any{"sku": "", "qty": jolt.BigInt(1), "attrs": jolt.Map{jolt.BigInt(1): "x"}}}
+	delete(body, "uuid")
+
+	vs := joltschema.Validate(loadOrderSchema(t), body)
+	var paths []string
+	for _, v := range vs {
+		paths = append(paths, v.Path)
+	}
+	sort.Strings(paths)
+	want := []string{"$.extra", "$.lines[0].attrs[1]", "$.lines[0].sku", "$.number", "$.price", "$.price", "$.qty", "$.status", "$.uuid"}
+	if strings.Join(paths, " ") != strings.Join(want, " ") {
+		t.Fatalf("violation paths\n got %v\nwant %v\n%v", paths, want, vs)
+	}
+}
+
+func TestSchemaTypesAndSets(t *testing.T) {
+	s, err := joltschema.Parse([]byte(`{
+		"type": "object",
+		"fields": {
+			"flags": { "type": "set", "items": { "type": "string" }, "maxLen": 3 },
+			"at":    { "type": "ts", "min": "2025-01-01T00:00:00Z" },
+			"owner": { "type": "uuid", "nullable": true }
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ok := map[string]any{"flags": jolt.Set{"a", "b"}, "at": jolt.Timestamp{RFC3339: "2025-08-08T10:00:00+02:00"}, "owner": nil}
+	if vs := joltschema.Validate(s, ok); vs != nil {
+		t.Fatal(vs)
+	}
+	bad := map[string]any{"flags": jolt.Set{"a", "a", jolt.BigInt(1), "c"}, "at": jolt.Timestamp{RFC3339: "2024-12-31T23:59:59Z"}, "owner": "not-a-uuid"}
+	if vs := joltschema.Validate(s, bad); len(vs) != 5 {
+		t.Fatalf("want 5 violations, got %v", vs)
+	}
+}
+
+func TestSchemaRoundTripsAsJOLT(t *testing.T) {
+	s := loadOrderSchema(t)
+	bin, err := jolt.EncodeBinary(s.ToValue())
+	if err != nil {
+		t.Fatal(err)
+	}
+	back, err := joltschema.ParseBinary(bin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, _ := jolt.EncodeBinary(back.ToValue())
+	if string(again) != string(bin) {
+		t.Fatal("schema changed through JOLT-B")
+	}
+}
+
+func TestValidateEnvelopeWithLoader(t *testing.T) {
+	l := &joltschema.DirLoader{Dir: "testdata"}
+	v, _ := jolt.UnmarshalJSONTyped(orderJSON())
+	env := jolt.Envelope{Meta: jolt.Meta{Type: "urn:jolt:example/Order", Schema: "urn:jolt:schema/Order"}, Body: v.(map[string]any)["$body"]}
+	vs, err := joltschema.ValidateEnvelope(env, l)
+	if err != nil || vs != nil {
+		t.Fatalf("vs=%v err=%v", vs, err)
+	}
+	env.Body.(map[string]any)["qty"] = jolt.BigInt(-1)
+	vs, _ = joltschema.ValidateEnvelope(env, l)
+	if len(vs) != 1 || vs[0].Path != "$body.qty" {
+		t.Fatalf("got %v", vs)
+	}
+	env.Meta.Schema = ""
+	if _, err := joltschema.ValidateEnvelope(env, l); !errors.Is(err, joltschema.ErrNoSchema) {
+		t.Fatalf("want ErrNoSchema, got %v", err)
+	}
+}
+
+func TestSchemaRefKeepsOwnConstraints(t *testing.T) {
+	s, err := joltschema.Parse([]byte(`{
+		"type": "object",
+		"defs": { "L": { "type": "string" } },
+		"fields": {
+			"a": { "ref": "L", "nullable": true },
+			"b": { "ref": "L", "enum": ["x", "y"] },
+			"c": { "ref": "L" }
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ok := map[string]any{"a": nil, "b": "x", "c": "z"}
+	if vs := joltschema.Validate(s, ok); vs != nil {
+		t.Fatal(vs)
+	}
+	vs := joltschema.Validate(s, map[string]any{"a": jolt.BigInt(1), "b": "z", "c": nil})
+	var got []string
+	for _, v := range vs {
+		got = append(got, v.String())
+	}
+	sort.Strings(got)
+	if len(got) != 3 || !strings.HasPrefix(got[0], "$.a: ") || !strings.HasPrefix(got[1], "$.b: ") || got[2] != "$.c: null is not allowed for string" {
+		t.Fatalf("got %q", got)
+	}
+}
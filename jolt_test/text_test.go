@@ -0,0 +1,120 @@
+package jolt_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/chandan-cmd-dev/jolt-go/jolt"
+)
+
+func TestTextMatchesTypedJSON(t *testing.T) {
+	data, err := os.ReadFile("testdata/text/order.jolt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := jolt.ParseText(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	js, err := jolt.UnmarshalJSONTyped(orderJSON())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := jolt.AsEnvelope(js)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !jolt.Equal(got, want) {
+		t.Fatalf("text = %#v\njson = %#v", got, want)
+	}
+}
+
+func TestTextRoundTrip(t *testing.T) {
+	registerGeo(t)
+	for _, name := range []string{"order.jolt", "kitchen.jolt"} {
+		data, err := os.ReadFile("testdata/text/" + name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		v, err := jolt.ParseText(data)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		jb, err := jolt.EncodeBinary(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		text, err := jolt.FormatTextBinary(jb)
+		if err != nil {
+			t.Fatal(err)
+		}
+		back, err := jolt.ParseText(text)
+		if err != nil {
+			t.Fatalf("%s: reparsing\n%s\n%v", name, text, err)
+		}
+		jb2, err := jolt.EncodeBinary(back)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(jb, jb2) {
+			t.Fatalf("%s: round trip changed the encoding:\n%s", name, text)
+		}
+	}
+
+	doc := map[string]any{"at": geoPoint{Lat: mustDec("52.52"), Lon: mustDec("13.405")}, "x": jolt.Extension{Tag: 0x200, Raw: []byte{1, 2}}}
+	text, err := jolt.FormatText(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(text), "geo:point([") || !strings.Contains(string(text), `ext(512, b64"AQI=")`) {
+		t.Fatalf("extensions:\n%s", text)
+	}
+	back, err := jolt.ParseText(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !jolt.Equal(back, doc) {
+		t.Fatalf("extensions round trip: %#v", back)
+	}
+}
+
+func TestTextLiterals(t *testing.T) {
+	v, err := jolt.ParseText([]byte(`{a: 123456789012345678901n, b: 19.99d, c: 2, d: 0.10, e: #{1, 1.0}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	big, _ := jolt.IntFromString("123456789012345678901")
+	want := map[string]any{"a": big, "b": mustDec("19.99"), "c": jolt.BigInt(2), "d": mustDec("0.10"), "e": jolt.Set{jolt.BigInt(1), mustDec("1.0")}}
+	if !jolt.Equal(v, want) {
+		t.Fatalf("got %#v", v)
+	}
+	text, _ := jolt.FormatText(mustDec("0.10"))
+	if string(text) != "0.10d\n" {
+		t.Fatalf("decimal formats as %q", text)
+	}
+}
+
+func TestTextSyntaxErrors(t *testing.T) {
+	cases := []struct{ in, err string }{
+		{"{a: 1,\n b: }", "jolt: line 2, col 5: unexpected '}', want a value"},
+		{"[1 2]", `jolt: line 1, col 4: unexpected '2', want "," or ']'`},
+		{"{a: 1, a: 2}", `jolt: line 1, col 8: duplicate key "a"`},
+		{"uuid\"nope\"", `jolt: line 1, col 1: bad uuid "nope"`},
+		{"1.5n", "jolt: line 1, col 1: 1.5 is not an integer"},
+		{"map{[1] => 2}", "jolt: line 1, col 5: map key of type []interface {} is not usable as a key"},
+		{"\"abc", "jolt: line 1, col 1: unterminated string"},
+		{"/* open", "jolt: line 1, col 1: unterminated comment"},
+		{"nul", `jolt: line 1, col 1: unknown word "nul"`},
+		{"{} {}", "jolt: line 1, col 4: unexpected '{' after value"},
+	}
+	for _, c := range cases {
+		_, err := jolt.ParseText([]byte(c.in))
+		var se *jolt.SyntaxError
+		if !errors.As(err, &se) || err.Error() != c.err {
+			t.Errorf("ParseText(%q) = %v, want %s", c.in, err, c.err)
+		}
+	}
+}
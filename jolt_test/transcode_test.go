@@ -0,0 +1,147 @@
+package jolt_test
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/chandan-cmd-dev/jolt-go/jolt"
+)
+
+const transcodeDoc = `{
+	"z": [1, 2.50, -3e2, true, false, null, "s<&>"],
+	"@type": "Order",
+	"dup": 1,
+	"a": {"qty": {"@type": "int", "value": 7}, "price": {"@type": "dec", "value": "19.90"}},
+	"$comment": "dropped",
+	"typed": [
+		{"@type": "uuid", "value": "73bca6bf-8d9d-4095-93f4-13e85485f2db"},
+		{"@type": "set", "value": [3, 1, {"@type": "ts", "value": "2025-01-02T03:04:05Z"}]},
+		{"@type": "map", "value": [{"key": 2, "value": "two"}, {"key": "k", "value": [1]}]},
+		{"@type": "ext", "tag": 200, "value": "AQI="},
+		{"@type": "annot", "label": "x", "value": {"n": 1}}
+	],
+	"dup": 2,
+	"empty": [{}, []]
+}`
+
+func TestTranscodeJSONToBinary(t *testing.T) {
+	for name, doc := range map[string][]byte{"doc": []byte(transcodeDoc), "order": orderJSON()} {
+		v, err := jolt.UnmarshalJSONTyped(doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := jolt.EncodeBinary(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// MaxBuffer 16 makes every non-trivial container spill.
+		for _, max := range []int{0, 16} {
+			dir := t.TempDir()
+			var got bytes.Buffer
+			err := jolt.TranscodeJSONToBinaryWith(&got, bytes.NewReader(doc), jolt.TranscodeOptions{MaxBuffer: max, TempDir: dir})
+			if err != nil {
+				t.Fatalf("%s/%d: %v", name, max, err)
+			}
+			if !bytes.Equal(got.Bytes(), want) {
+				t.Fatalf("%s/%d: got  %x\nwant %x", name, max, got.Bytes(), want)
+			}
+			if left, _ := os.ReadDir(dir); len(left) != 0 {
+				t.Fatalf("%s/%d: spill files left behind: %v", name, max, left)
+			}
+		}
+	}
+}
+
+func TestTranscodeBinaryToJSON(t *testing.T) {
+	v, err := jolt.UnmarshalJSONTyped(orderJSON())
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err := jolt.AsEnvelope(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []any{v, env, mustTyped(t, transcodeDoc)} {
+		b, err := jolt.EncodeBinary(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Compare with the decoded value: sets come back in canonical order
+		// and "$comment" is gone.
+		dv, err := jolt.DecodeBinary(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := jolt.MarshalJSONCompat(dv, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got bytes.Buffer
+		if err := jolt.TranscodeBinaryToJSON(&got, bytes.NewReader(b)); err != nil {
+			t.Fatal(err)
+		}
+		if got.String() != string(want) {
+			t.Fatalf("got  %s\nwant %s", got.String(), want)
+		}
+	}
+}
+
+func TestTranscodeNDJSON(t *testing.T) {
+	lines := []string{`{"id":1,"n":{"@type":"dec","value":"1.5"}}`, `[1,"two"]`, `"x"`, `{"id":2}`}
+	var frames bytes.Buffer
+	opts := jolt.TranscodeOptions{NDJSON: true}
+	if err := jolt.TranscodeJSONToBinaryWith(&frames, strings.NewReader(strings.Join(lines, "\n")+"\n\n"), opts); err != nil {
+		t.Fatal(err)
+	}
+	r := bufio.NewReader(bytes.NewReader(frames.Bytes()))
+	for _, line := range lines {
+		frame, err := jolt.ReadFrame(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, _ := jolt.EncodeBinary(mustTyped(t, line))
+		if !bytes.Equal(frame, want) {
+			t.Fatalf("frame for %s: got %x, want %x", line, frame, want)
+		}
+	}
+	var out, want bytes.Buffer
+	if err := jolt.TranscodeBinaryToJSONWith(&out, &frames, opts); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range lines {
+		js, _ := jolt.MarshalJSONCompat(mustTyped(t, line), false)
+		want.Write(append(js, '\n'))
+	}
+	if out.String() != want.String() {
+		t.Fatalf("got  %q\nwant %q", out.String(), want.String())
+	}
+}
+
+func TestTranscodeErrors(t *testing.T) {
+	for _, in := range []string{`{"a":1} 2`, `[1,`, `{"@type":"int","value":"x"}`} {
+		if err := jolt.TranscodeJSONToBinary(&bytes.Buffer{}, strings.NewReader(in)); err == nil {
+			t.Errorf("%s: no error", in)
+		}
+	}
+	b, _ := jolt.EncodeBinary([]any{"a"})
+	if err := jolt.TranscodeBinaryToJSON(&bytes.Buffer{}, bytes.NewReader(append(b, 0))); err == nil {
+		t.Error("trailing byte: no error")
+	}
+	deep := strings.Repeat("[", jolt.DefaultLimits.MaxDepth+2) + strings.Repeat("]", jolt.DefaultLimits.MaxDepth+2)
+	if err := jolt.TranscodeJSONToBinary(&bytes.Buffer{}, strings.NewReader(deep)); err != jolt.ErrTooDeep {
+		t.Errorf("deep: %v", err)
+	}
+}
+
+func mustTyped(t *testing.T, s string) any {
+	t.Helper()
+	v, err := jolt.UnmarshalJSONTyped([]byte(s))
+	if err != nil {
+		t.Fatal(fmt.Errorf("%s: %w", s, err))
+	}
+	return v
+}
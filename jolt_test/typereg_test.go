@@ -0,0 +1,113 @@
+package jolt_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/chandan-cmd-dev/jolt-go/jolt"
+)
+
+type orderV1 struct {
+	Number string `jolt:"number"`
+	Qty    int    `jolt:"qty"`
+}
+
+type orderV2 struct {
+	Number string       `jolt:"number"`
+	Qty    int          `jolt:"qty"`
+	Price  jolt.Decimal `jolt:"price"`
+}
+
+func newOrderRegistry(t *testing.T) *jolt.TypeRegistry {
+	t.Helper()
+	reg := jolt.NewTypeRegistry()
+	if err := reg.Register("urn:jolt:example/Order", "1.x", orderV1{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.Register("urn:jolt:example/Order", ">=2.0.0 <3.0.0", &orderV2{}); err != nil {
+		t.Fatal(err)
+	}
+	return reg
+}
+
+func TestTypeRegistryDecodeEnvelope(t *testing.T) {
+	reg := newOrderRegistry(t)
+	for _, tc := range []struct {
+		version string
+		check   func(any) bool
+	}{
+		{"1.4.0", func(b any) bool { o, ok := b.(*orderV1); return ok && o.Qty == 2 }},
+		{"2.1.0", func(b any) bool { o, ok := b.(*orderV2); return ok && o.Price.String() == "19.99" }},
+	} {
+		bin, err := jolt.EncodeBinary(jolt.Envelope{
+			Meta: jolt.Meta{Type: "urn:jolt:example/Order", Version: tc.version},
+			Body: map[string]any{"number": "SO-1", "qty": jolt.BigInt(2), "price": mustDec("19.99")},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		env, err := reg.DecodeEnvelope(bin)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.version, err)
+		}
+		if !tc.check(env.Body) {
+			t.Fatalf("%s: body %#v", tc.version, env.Body)
+		}
+	}
+}
+
+func TestTypeRegistryUnknown(t *testing.T) {
+	reg := newOrderRegistry(t)
+	for _, meta := range []jolt.Meta{
+		{Type: "urn:jolt:example/Invoice", Version: "1.0.0"},
+		{Type: "urn:jolt:example/Order", Version: "3.0.0"},
+	} {
+		bin, _ := jolt.EncodeBinary(jolt.Envelope{Meta: meta, Body: map[string]any{"x": "y"}})
+		if _, err := reg.DecodeEnvelope(bin); !errors.Is(err, jolt.ErrUnknownType) {
+			t.Fatalf("%s@%s: want ErrUnknownType, got %v", meta.Type, meta.Version, err)
+		}
+		reg.AllowUnknown = true
+		env, err := reg.DecodeEnvelope(bin)
+		reg.AllowUnknown = false
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := env.Body.(map[string]any); !ok {
+			t.Fatalf("fallback body %T", env.Body)
+		}
+	}
+	if err := reg.Register("urn:jolt:example/Order", "1.x", orderV1{}); !errors.Is(err, jolt.ErrTypeConflict) {
+		t.Fatalf("duplicate registration accepted: %v", err)
+	}
+}
+
+// Ranges written differently still conflict when some version matches both.
+func TestTypeRegistryOverlappingRanges(t *testing.T) {
+	for _, rng := range []string{"^2.1", "2.5.0", ">=2.9.9", "*", "<=1.0.0", "0.x || 2.0.x"} {
+		reg := newOrderRegistry(t)
+		if err := reg.Register("urn:jolt:example/Order", rng, orderV2{}); !errors.Is(err, jolt.ErrTypeConflict) {
+			t.Errorf("%q overlaps 1.x or >=2.0.0 <3.0.0: %v", rng, err)
+		}
+	}
+	for _, rng := range []string{"3.x", ">=3.0.0", "<1.0.0", "0.9.9 || ^4"} {
+		reg := newOrderRegistry(t)
+		if err := reg.Register("urn:jolt:example/Order", rng, orderV2{}); err != nil {
+			t.Errorf("%q: %v", rng, err)
+		}
+	}
+}
+
+func TestTypeRegistryJSONEnvelope(t *testing.T) {
+	reg := newOrderRegistry(t)
+	v, err := jolt.UnmarshalJSONTyped(orderJSON())
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err := reg.Decode(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o, ok := env.Body.(*orderV2); !ok || o.Number != "SO-12988" || o.Price.String() != "1999.95" {
+		t.Fatalf("body %#v", env.Body)
+	}
+}
@@ -0,0 +1,76 @@
+package jolt_test
+
+import (
+	"testing"
+
+	"github.com/chandan-cmd-dev/jolt-go/jolt"
+)
+
+func TestCompareVersions(t *testing.T) {
+	// Each version is lower than the next (semver §11).
+	order := []string{
+		"1.0.0-0", "1.0.0-2", "1.0.0-10", "1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta",
+		"1.0.0-beta", "1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0-rc.9", "1.0.0-rc.10",
+		"1.0.0", "1.0.1", "1.2.0", "2.0.0",
+	}
+	for i := 0; i+1 < len(order); i++ {
+		a, b := order[i], order[i+1]
+		if d, err := jolt.CompareVersions(a, b); err != nil || d != -1 {
+			t.Errorf("CompareVersions(%s, %s) = %d, %v", a, b, d, err)
+		}
+		if d, _ := jolt.CompareVersions(b, a); d != 1 {
+			t.Errorf("CompareVersions(%s, %s) = %d", b, a, d)
+		}
+	}
+	if d, err := jolt.CompareVersions("v1.2+build.5", "1.2.0"); err != nil || d != 0 {
+		t.Errorf("build metadata: %d, %v", d, err)
+	}
+	for _, bad := range []string{"", "1.2.3.4", "1.x", "1.0.0-", "1.0.0-rc..1"} {
+		if _, err := jolt.CompareVersions(bad, "1.0.0"); err == nil {
+			t.Errorf("%q: expected an error", bad)
+		}
+	}
+}
+
+func TestVersionRanges(t *testing.T) {
+	cases := []struct {
+		rng     string
+		match   []string
+		noMatch []string
+	}{
+		{"*", []string{"0.0.1", "9.9.9-rc.1"}, nil},
+		{"1.2.3", []string{"1.2.3"}, []string{"1.2.4", "1.2.3-rc.1"}},
+		{">=2.0.0 <3.0.0", []string{"2.0.0", "2.9.9"}, []string{"1.9.9", "3.0.0"}},
+		{"2.x", []string{"2.0.0", "2.7.1"}, []string{"1.9.9", "3.0.0-rc.1", "3.0.0"}},
+		{"2.1.*", []string{"2.1.0", "2.1.9"}, []string{"2.2.0-0", "2.2.0"}},
+		{"^1.2", []string{"1.2.0", "1.9.0"}, []string{"1.1.9", "2.0.0-rc.1", "2.0.0"}},
+		{"^0.2.3", []string{"0.2.3", "0.2.9"}, []string{"0.2.2", "0.3.0"}},
+		{"^0.0.3", []string{"0.0.3"}, []string{"0.0.2", "0.0.4", "0.1.0"}},
+		{"^0.0", []string{"0.0.0", "0.0.9"}, []string{"0.1.0"}},
+		{"^0", []string{"0.0.0", "0.9.9"}, []string{"1.0.0"}},
+		{"~1.2.3", []string{"1.2.3", "1.2.9"}, []string{"1.2.2", "1.3.0"}},
+		{"~1", []string{"1.0.0", "1.9.9"}, []string{"2.0.0"}},
+		{"1.x || >=3.0.0-rc.1", []string{"1.5.0", "3.0.0-rc.2", "4.0.0"}, []string{"2.0.0", "3.0.0-beta"}},
+	}
+	for _, c := range cases {
+		reg := jolt.NewTypeRegistry()
+		if err := reg.Register("urn:test", c.rng, struct{}{}); err != nil {
+			t.Fatalf("%q: %v", c.rng, err)
+		}
+		for _, v := range c.match {
+			if _, err := reg.Lookup("urn:test", v); err != nil {
+				t.Errorf("%q should match %s: %v", c.rng, v, err)
+			}
+		}
+		for _, v := range c.noMatch {
+			if _, err := reg.Lookup("urn:test", v); err == nil {
+				t.Errorf("%q should not match %s", c.rng, v)
+			}
+		}
+	}
+	for _, bad := range []string{"", " ", "1.x ||", "|| 2.x", "^", "x.1", ">=1.0.0-"} {
+		if err := jolt.NewTypeRegistry().Register("urn:test", bad, struct{}{}); err == nil {
+			t.Errorf("range %q: expected an error", bad)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package jolt_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/chandan-cmd-dev/jolt-go/jolt"
+)
+
+func walkDoc() jolt.Envelope {
+	return jolt.Envelope{
+		Meta: jolt.Meta{Type: "Order", Version: "1.0.0"},
+		Body: map[string]any{
+			"$comment": "imported",
+			"email":    "A@Example.com",
+			"tags":     jolt.Set{"Gift", "VIP"},
+			"stock":    jolt.Map{"Main": jolt.BigInt(7)},
+			"lines": []any{
+				map[string]any{"sku": "a-1", "price": mustDec("19.99")},
+				map[string]any{"sku": "b-2", "price": mustDec("2.50")},
+			},
+		},
+	}
+}
+
+func TestWalk(t *testing.T) {
+	var got []string
+	err := jolt.Walk(walkDoc(), func(p jolt.Path, v any) error {
+		got = append(got, p.String())
+		if len(p) > 0 && p[len(p)-1] == "lines" {
+			return jolt.SkipSubtree
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"$", "$.$meta", "$.$meta.features", "$.$meta.schema", "$.$meta.type", "$.$meta.version",
+		"$.$body", "$.$body.$comment", "$.$body.email", "$.$body.lines",
+		"$.$body.stock", "$.$body.stock[key Main]", "$.$body.stock.Main",
+		"$.$body.tags", "$.$body.tags[member Gift]", "$.$body.tags[member VIP]",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("visited\n%v\nwant\n%v", got, want)
+	}
+
+	// Paths work with Get, and errors stop the walk.
+	stop := errors.New("stop")
+	err = jolt.Walk(walkDoc(), func(p jolt.Path, v any) error {
+		if got, err := jolt.Get[any](walkDoc(), p...); err != nil || !jolt.Equal(got, v) {
+			t.Errorf("%s: Get = %v, %v", p, got, err)
+		}
+		if _, ok := v.(jolt.Decimal); ok {
+			return stop
+		}
+		return nil
+	})
+	if err != stop {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+// lowerStrings lowercases every string, Map key and Set member, and drops
+// comments.
+func lowerStrings(p jolt.Path, v any) (any, error) {
+	if len(p) > 0 && p[len(p)-1] == "$comment" {
+		return nil, jolt.Remove
+	}
+	if s, ok := v.(string); ok && (len(p) == 0 || p[0] != "$meta") {
+		return strings.ToLower(s), nil
+	}
+	return v, nil
+}
+
+func TestTransform(t *testing.T) {
+	doc := walkDoc()
+	got, err := jolt.Transform(doc, lowerStrings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := walkDoc().Body.(map[string]any)
+	delete(body, "$comment")
+	body["email"] = "a@example.com"
+	body["tags"] = jolt.Set{"gift", "vip"}
+	body["stock"] = jolt.Map{"main": jolt.BigInt(7)}
+	want := jolt.Envelope{Meta: walkDoc().Meta, Body: body}
+	if !jolt.Equal(got, want) {
+		t.Fatalf("got %#v", got)
+	}
+	if !jolt.Equal(doc, walkDoc()) {
+		t.Fatal("Transform modified its input")
+	}
+
+	// Removing an array element or set member shrinks the container.
+	got, _ = jolt.Transform([]any{"a", jolt.Set{"x", "y"}, "b"}, func(p jolt.Path, v any) (any, error) {
+		if v == "a" || v == "y" {
+			return nil, jolt.Remove
+		}
+		return v, nil
+	})
+	if !jolt.Equal(got, []any{jolt.Set{"x"}, "b"}) {
+		t.Fatalf("got %#v", got)
+	}
+
+	_, err = jolt.Transform(jolt.Map{"A": 1.0, "a": 2.0}, lowerStrings)
+	var pe *jolt.PathError
+	if !errors.As(err, &pe) {
+		t.Fatalf("colliding keys: %v", err)
+	}
+}
+
+func TestTransformBinary(t *testing.T) {
+	jb, err := jolt.EncodeBinary(walkDoc())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Returning every value unchanged reproduces the input byte for byte.
+	same, err := jolt.TransformBinary(jb, func(p jolt.Path, v any) (any, error) { return v, nil })
+	if err != nil || string(same) != string(jb) {
+		t.Fatalf("identity rewrite changed the encoding: %v", err)
+	}
+
+	// The binary rewrite matches the tree rewrite, canonical order included.
+	out, err := jolt.TransformBinary(jb, func(p jolt.Path, v any) (any, error) {
+		if _, ok := v.(jolt.View); ok && len(p) > 0 && p[len(p)-1] == "lines" {
+			return []any{}, nil // replace without descending
+		}
+		return lowerStrings(p, v)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tree, _ := jolt.Transform(walkDoc(), func(p jolt.Path, v any) (any, error) {
+		if len(p) > 0 && p[len(p)-1] == "lines" {
+			return []any{}, nil
+		}
+		return lowerStrings(p, v)
+	})
+	want, _ := jolt.EncodeBinary(tree)
+	if string(out) != string(want) {
+		t.Fatal("binary rewrite differs from tree rewrite")
+	}
+}
+
+// Set members, like Map keys, must stay distinct.
+func TestTransformSetMembersBecomeEqual(t *testing.T) {
+	doc := map[string]any{"tags": jolt.Set{"VIP", "vip", "gift"}}
+	var perr *jolt.PathError
+	if _, err := jolt.Transform(doc, lowerStrings); !errors.As(err, &perr) || !strings.Contains(err.Error(), "two set members became equal") {
+		t.Fatalf("Transform: %v", err)
+	}
+	jb, err := jolt.EncodeBinary(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := jolt.TransformBinary(jb, lowerStrings); !errors.As(err, &perr) || !strings.Contains(err.Error(), "two set members became equal") {
+		t.Fatalf("TransformBinary: %v", err)
+	}
+}
@@ -0,0 +1,192 @@
+package jolt_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/chandan-cmd-dev/jolt-go/jolt"
+	"github.com/chandan-cmd-dev/jolt-go/jolt/yaml"
+)
+
+func TestYAMLFixture(t *testing.T) {
+	src, err := os.ReadFile(filepath.Join("testdata", "yaml", "deploy.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	js, err := os.ReadFile(filepath.Join("testdata", "yaml", "deploy.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := jolt.UnmarshalJSONTyped(js)
+	if err != nil {
+		t.Fatal(err)
+	}
+	docs, err := yaml.UnmarshalAll(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !jolt.Equal(docs, want) {
+		t.Fatalf("got  %#v\nwant %#v", docs, want)
+	}
+	if _, err := yaml.Unmarshal(src); err == nil {
+		t.Fatal("Unmarshal took a two-document stream")
+	}
+}
+
+func TestYAMLRoundTrip(t *testing.T) {
+	u, _ := jolt.UUIDFromString("73bca6bf-8d9d-4095-93f4-13e85485f2db")
+	v := map[string]any{
+		"id":      u,
+		"qty":     bigInt("123456789012345678901234567890"),
+		"price":   mustDec("-1999.950"),
+		"whole":   mustDec("5"),
+		"at":      jolt.Timestamp{RFC3339: "2025-08-08T07:42:01.344243Z"},
+		"on":      jolt.DateYMD(2025, 8, 8),
+		"open":    jolt.TimeHMS(9, 30, 0),
+		"note":    jolt.Annot{Note: "checked: twice"},
+		"ext":     jolt.Extension{Tag: 0x99, Raw: []byte{5, 0x01, 'x'}},
+		"tags":    jolt.Set{"gift", jolt.BigInt(7)},
+		"grid":    jolt.Map{jolt.BigInt(1): "one", u: []any{nil, true}},
+		"link":    jolt.Link{Ref: "urn:jolt:order/1"},
+		"blob":    jolt.Binary("\x00\xff"),
+		"none":    jolt.Binary{},
+		"strings": []any{"", "true", "12", "- x", "a: b", "#c", "x #y", " pad", "...", ".inf", "line\r\n", "\u2028", "tab\t"},
+		"text":    []any{"one\ntwo\n", "one\n\ntwo", "keep\n\n", "  lead\nx"},
+		"nested":  []any{[]any{}, map[string]any{}, []any{map[string]any{"a": []any{jolt.BigInt(1)}}}},
+		"<<":      "not a merge",
+		"":        "empty key",
+	}
+	b, err := yaml.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := yaml.Unmarshal(b)
+	if err != nil {
+		t.Fatalf("%v\n%s", err, b)
+	}
+	if !jolt.Equal(got, v) {
+		t.Fatalf("got  %#v\nwant %#v\n%s", got, v, b)
+	}
+	again, _ := yaml.Marshal(got)
+	if !bytes.Equal(again, b) {
+		t.Fatalf("not deterministic:\n%s\n%s", b, again)
+	}
+}
+
+func TestYAMLEnvelope(t *testing.T) {
+	v, err := jolt.UnmarshalJSONTyped(orderJSON())
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err := jolt.AsEnvelope(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := yaml.MarshalAll([]any{env, env.Body})
+	if err != nil {
+		t.Fatal(err)
+	}
+	docs, err := yaml.UnmarshalAll(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	back, err := jolt.AsEnvelope(docs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(docs) != 2 || !jolt.Equal(back, env) || !jolt.Equal(docs[1], env.Body) {
+		t.Fatalf("got %#v\n%s", docs, b)
+	}
+}
+
+func TestYAMLMarshal(t *testing.T) {
+	v := map[string]any{
+		"b":    []any{jolt.BigInt(1), map[string]any{"x": "y", "z": jolt.Set{"b", "a"}}},
+		"a":    mustDec("0.50"),
+		"c":    "two\nlines",
+		"tags": jolt.Set{},
+	}
+	want := `a: 0.50
+b:
+  - 1
+  - x: y
+    z: !set
+      - a
+      - b
+c: |-
+  two
+  lines
+tags: !set []
+`
+	got, err := yaml.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != want {
+		t.Fatalf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestYAMLErrors(t *testing.T) {
+	for src, line := range map[string]int{
+		"a: 1\na: 2\n":             2, // duplicate key
+		"a:\n\t- 1\n":              2, // tab indentation
+		"a: *nope\n":               1, // unknown anchor
+		"a: [1, 2\n":               1, // unterminated flow
+		"a: \"open\n":              1, // unterminated quote
+		"a: !uuid nope\n":          1,
+		"a: !nosuch 1\n":           1,
+		"a:\n  - 1\n - 2\n":        3,
+		"? complex\n: key\n":       1,
+		"a: b: c\n":                1,
+		"x: 1\n---\ny: !dec abc\n": 3,
+		"a: .inf\n":                1, // JOLT decimals are finite
+		"a: [1, -.INF]\n":          1,
+		"a: !dec NaN\n":            1,
+	} {
+		_, err := yaml.UnmarshalAll([]byte(src))
+		var se *jolt.SyntaxError
+		if !errors.As(err, &se) {
+			t.Errorf("%q: want a SyntaxError, got %v", src, err)
+			continue
+		}
+		if se.Line != line {
+			t.Errorf("%q: %v, want line %d", src, err, line)
+		}
+	}
+	// Nested aliases must not expand without bound.
+	bomb := "a: &a [x, x, x, x, x, x, x, x, x, x]\n"
+	for i, prev := 0, "a"; i < 9; i++ {
+		name := string(rune('b' + i))
+		bomb += name + ": &" + name + " [*" + prev + ", *" + prev + ", *" + prev + ", *" + prev + ", *" + prev +
+			", *" + prev + ", *" + prev + ", *" + prev + ", *" + prev + ", *" + prev + "]\n"
+		prev = name
+	}
+	if _, err := yaml.Unmarshal([]byte(bomb)); err == nil {
+		t.Fatal("alias expansion not bounded")
+	}
+}
+
+func TestYAMLLineBreaks(t *testing.T) {
+	for src, want := range map[string]string{
+		"k: \"a\rb\"\n":           "a b",
+		"k: \"a\r\nb\"\r\n":       "a b",
+		"k: \"a\r\n\r\nb\"\r\n":   "a\nb",
+		"k: 'a\r\r  b'\n":         "a\nb",
+		"k: a\r  b\n":             "a b",
+		"k: a\r\n  b\r\n":         "a b",
+		"k: a\r\n\r\n  b\r\nj: 1": "a\nb",
+	} {
+		v, err := yaml.Unmarshal([]byte(src))
+		if err != nil {
+			t.Errorf("%q: %v", src, err)
+			continue
+		}
+		if got := v.(map[string]any)["k"]; got != want {
+			t.Errorf("%q: got %q, want %q", src, got, want)
+		}
+	}
+}
@@ -0,0 +1,638 @@
+package joltschema
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// GoOptions controls GenerateGo.
+type GoOptions struct {
+	Package string // package clause of the generated file
+	Root    string // name of the root type; defaults to the last segment of the schema ID
+	Source  string // named in the "Code generated" header, e.g. the schema file
+}
+
+// GenerateGo emits Go source with one struct per object schema: the root,
+// every object in Defs and objects nested inline. Each struct gets a NewX
+// constructor that applies defaults, Validate, and MarshalJOLT/UnmarshalJOLT
+// methods that convert field by field without reflection. The schema itself is
+// embedded so Validate checks exactly what the generator saw.
+func GenerateGo(s *Schema, opts GoOptions) ([]byte, error) {
+	if opts.Package == "" {
+		return nil, errors.New("joltschema: GenerateGo needs a package name")
+	}
+	root := opts.Root
+	if root == "" {
+		root = s.ID
+		if i := strings.LastIndexAny(root, "/:"); i >= 0 {
+			root = root[i+1:]
+		}
+	}
+	root = goName(root)
+	if root == "X" {
+		root = "Root"
+	}
+	g := &goGen{
+		root:     s,
+		rootVar:  lowerFirst(root) + "Schema",
+		types:    map[*Schema]string{},
+		structOf: map[*Schema]string{},
+		isStruct: map[string]bool{},
+		byDef:    map[string]string{},
+		taken:    map[string]bool{},
+	}
+	for _, name := range sortedKeys(s.Defs) {
+		if def := s.Defs[name]; def.Ref == "" && isStructSchema(def) {
+			tn := g.unique(goName(name))
+			g.byDef[name] = tn
+			g.structOf[def] = tn
+			g.isStruct[tn] = true
+		}
+	}
+	if isStructSchema(s) {
+		g.addStruct(g.unique(root), s, g.rootVar)
+	}
+	for _, name := range sortedKeys(s.Defs) {
+		if tn, ok := g.byDef[name]; ok {
+			g.addStruct(tn, s.Defs[name], g.rootVar+".Defs["+strconv.Quote(name)+"]")
+		}
+	}
+	if len(g.structs) == 0 {
+		return nil, errors.New("joltschema: schema has no object types to generate")
+	}
+	src, err := g.emit(s, opts)
+	if err != nil {
+		return nil, err
+	}
+	out, err := format.Source(src)
+	if err != nil {
+		return nil, fmt.Errorf("joltschema: formatting generated code: %w\n%s", err, src)
+	}
+	return out, nil
+}
+
+type goGen struct {
+	root    *Schema
+	rootVar string
+
+	types    map[*Schema]string // Go type of each visited schema, without pointer
+	structOf map[*Schema]string // object schema -> struct name
+	isStruct map[string]bool
+	byDef    map[string]string // def name -> struct name
+	taken    map[string]bool
+	structs  []*goStruct
+
+	usesStrconv bool
+	buf         bytes.Buffer
+}
+
+type goStruct struct {
+	name   string
+	s      *Schema
+	expr   string // Go expression for s in the generated file
+	fields []goField
+}
+
+type goField struct {
+	name string // Go field name
+	key  string // JOLT object key
+	s    *Schema
+	expr string
+	typ  string
+	ptr  bool
+}
+
+func isStructSchema(s *Schema) bool { return s.Type == Object && len(s.Fields) > 0 }
+
+func (g *goGen) unique(name string) string {
+	n := name
+	for i := 2; g.taken[n]; i++ {
+		n = name + strconv.Itoa(i)
+	}
+	g.taken[n] = true
+	return n
+}
+
+func (g *goGen) addStruct(name string, s *Schema, expr string) {
+	st := &goStruct{name: name, s: s, expr: expr}
+	g.structOf[s] = name
+	g.isStruct[name] = true
+	g.structs = append(g.structs, st)
+	used := map[string]bool{"Validate": true, "MarshalJOLT": true, "UnmarshalJOLT": true}
+	for _, key := range sortedKeys(s.Fields) {
+		fs := s.Fields[key]
+		fname := goName(key)
+		for base, i := fname, 2; used[fname]; i++ {
+			fname = base + strconv.Itoa(i)
+		}
+		used[fname] = true
+		fexpr := expr + ".Fields[" + strconv.Quote(key) + "]"
+		typ := g.typeOf(fs, fexpr, name+fname)
+		optional := fs.Optional && fs.Default == nil
+		st.fields = append(st.fields, goField{
+			name: fname, key: key, s: fs, expr: fexpr, typ: typ,
+			ptr: (optional || g.nullable(fs)) && isValueType(typ),
+		})
+	}
+}
+
+// typeOf returns the Go type for s, declaring structs for inline objects on
+// the way. hint names such a struct.
+func (g *goGen) typeOf(s *Schema, expr, hint string) string {
+	if t, ok := g.types[s]; ok {
+		return t
+	}
+	t := g.computeType(s, expr, hint)
+	g.types[s] = t
+	return t
+}
+
+func (g *goGen) computeType(s *Schema, expr, hint string) string {
+	if s.Ref != "" {
+		if tn, ok := g.byDef[s.Ref]; ok {
+			return tn
+		}
+		def := g.root.Defs[s.Ref]
+		if def == nil {
+			return "any"
+		}
+		return g.typeOf(def, g.rootVar+".Defs["+strconv.Quote(s.Ref)+"]", goName(s.Ref))
+	}
+	switch s.Type {
+	case Bool:
+		return "bool"
+	case String:
+		return "string"
+	case Int:
+		return "int64"
+	case Dec:
+		return "jolt.Decimal"
+	case Bin:
+		return "jolt.Binary"
+	case TS:
+		return "jolt.Timestamp"
+	case Date:
+		return "jolt.Date"
+	case Time:
+		return "jolt.Time"
+	case UUID:
+		return "jolt.UUID"
+	case Link:
+		return "jolt.Link"
+	case Annot:
+		return "jolt.Annot"
+	case Array, Set:
+		if s.Items == nil {
+			return "[]any"
+		}
+		it := g.typeOf(s.Items, expr+".Items", hint+"Item")
+		if g.nullable(s.Items) && isValueType(it) {
+			it = "*" + it
+		}
+		return "[]" + it
+	case Map:
+		if s.Keys != nil && s.Keys.Resolve().Type != String {
+			return "jolt.Map"
+		}
+		if s.Values == nil {
+			return "map[string]any"
+		}
+		vt := g.typeOf(s.Values, expr+".Values", hint+"Value")
+		if g.nullable(s.Values) && isValueType(vt) {
+			vt = "*" + vt
+		}
+		return "map[string]" + vt
+	case Object:
+		if tn, ok := g.structOf[s]; ok {
+			return tn
+		}
+		if len(s.Fields) == 0 {
+			return "map[string]any"
+		}
+		tn := g.unique(hint)
+		g.addStruct(tn, s, expr)
+		return tn
+	}
+	return "any"
+}
+
+func (g *goGen) nullable(s *Schema) bool { return s.Nullable || s.Resolve().Nullable }
+
+// isValueType reports whether the zero value of a Go type cannot stand for
+// "absent", so optional and nullable fields of that type need a pointer.
+func isValueType(t string) bool {
+	return t != "any" && t != "jolt.Binary" && t != "jolt.Map" &&
+		!strings.HasPrefix(t, "[]") && !strings.HasPrefix(t, "map[")
+}
+
+func (g *goGen) p(format string, a ...any) { fmt.Fprintf(&g.buf, format+"\n", a...) }
+
+func (g *goGen) emit(s *Schema, opts GoOptions) ([]byte, error) {
+	from := opts.Source
+	if from == "" {
+		from = s.ID
+	}
+	if from != "" {
+		from = " from " + from
+	}
+	schemaJSON, err := s.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	lit := "`" + string(schemaJSON) + "`"
+	if strings.Contains(string(schemaJSON), "`") {
+		lit = strconv.Quote(string(schemaJSON))
+	}
+
+	var body bytes.Buffer
+	g.buf.Reset()
+	g.p("var %s = joltschema.MustParse([]byte(%s))", g.rootVar, lit)
+	for _, st := range g.structs {
+		g.p("")
+		g.emitStruct(st)
+	}
+	body.Write(g.buf.Bytes())
+
+	g.buf.Reset()
+	g.p("// Code generated by joltgen%s. DO NOT EDIT.", from)
+	g.p("")
+	g.p("package %s", opts.Package)
+	g.p("")
+	g.p("import (")
+	g.p("\t\"fmt\"")
+	if g.usesStrconv {
+		g.p("\t\"strconv\"")
+	}
+	g.p("")
+	g.p("\t\"github.com/chandan-cmd-dev/jolt-go/jolt\"")
+	g.p("\t\"github.com/chandan-cmd-dev/jolt-go/joltschema\"")
+	g.p(")")
+	g.p("")
+	g.buf.Write(body.Bytes())
+	return g.buf.Bytes(), nil
+}
+
+func (g *goGen) emitStruct(st *goStruct) {
+	recv := strings.ToLower(st.name[:1])
+	if recv == "v" {
+		recv = "r"
+	}
+	doc := st.s.Doc
+	if doc == "" {
+		doc = "is generated from "
+		if st.s == g.root && g.root.ID != "" {
+			doc += g.root.ID
+		} else {
+			doc += "its schema"
+		}
+		doc += "."
+	}
+	g.comment("", st.name+" "+doc)
+	g.p("type %s struct {", st.name)
+	for _, f := range st.fields {
+		if f.s.Doc != "" {
+			g.comment("\t", f.s.Doc)
+		}
+		typ := f.typ
+		if f.ptr {
+			typ = "*" + typ
+		}
+		tag := f.key
+		if f.s.Optional {
+			tag += ",omitempty"
+		}
+		g.p("\t%s %s `jolt:%s`", f.name, typ, strconv.Quote(tag))
+	}
+	g.p("}")
+
+	var consts []string
+	for _, f := range st.fields {
+		r := f.s.Resolve()
+		if r.Type != String || len(r.Enum) == 0 {
+			continue
+		}
+		for _, e := range r.Enum {
+			if str, ok := e.(string); ok && str != "" {
+				consts = append(consts, fmt.Sprintf("\t%s%s%s = %s", st.name, f.name, goName(str), strconv.Quote(str)))
+			}
+		}
+	}
+	if len(consts) > 0 {
+		g.p("")
+		g.p("// Allowed values of %s fields.", st.name)
+		g.p("const (")
+		for _, c := range consts {
+			g.p("%s", c)
+		}
+		g.p(")")
+	}
+
+	hasDefaults := false
+	for _, f := range st.fields {
+		if f.s.Default != nil {
+			hasDefaults = true
+		}
+	}
+	g.p("")
+	g.p("// New%s returns %s with the schema's defaults filled in.", st.name, article(st.name))
+	g.p("func New%s() *%s {", st.name, st.name)
+	g.p("\t%s := &%s{}", recv, st.name)
+	if hasDefaults {
+		g.p("\tif err := %s.applyDefaults(); err != nil {", recv)
+		g.p("\t\tpanic(err)")
+		g.p("\t}")
+	}
+	g.p("\treturn %s", recv)
+	g.p("}")
+	if hasDefaults {
+		g.p("")
+		g.p("func (%s *%s) applyDefaults() error {", recv, st.name)
+		for _, f := range st.fields {
+			if f.s.Default != nil {
+				g.decodeSlot(recv+"."+f.name, f.expr+".Default", f.s, f.ptr, strconv.Quote("$."+f.key), 1)
+			}
+		}
+		g.p("\treturn nil")
+		g.p("}")
+	}
+
+	g.p("")
+	g.p("// Validate checks %s against its schema and reports every violation.", recv)
+	g.p("func (%s *%s) Validate() error {", recv, st.name)
+	g.p("\treturn joltschema.Validate(%s, %s.toJOLT()).Err()", st.expr, recv)
+	g.p("}")
+
+	g.p("")
+	g.p("// MarshalJOLT implements jolt.Marshaler.")
+	g.p("func (%s %s) MarshalJOLT() (any, error) { return %s.toJOLT(), nil }", recv, st.name, recv)
+	g.p("")
+	g.p("func (%s %s) toJOLT() map[string]any {", recv, st.name)
+	g.p("\tobj := make(map[string]any, %d)", len(st.fields))
+	for _, f := range st.fields {
+		src := recv + "." + f.name
+		dst := "obj[" + strconv.Quote(f.key) + "]"
+		switch {
+		case f.ptr:
+			g.p("\tif %s != nil {", src)
+			g.encode(dst, "*"+src, f.s, 1)
+			if !f.s.Optional {
+				g.p("\t} else {")
+				g.p("\t\t%s = nil", dst)
+			}
+			g.p("\t}")
+		case f.s.Optional && !isValueType(f.typ):
+			g.p("\tif %s != nil {", src)
+			g.encode(dst, src, f.s, 1)
+			g.p("\t}")
+		case g.nullable(f.s) && !isValueType(f.typ):
+			g.p("\tif %s != nil {", src)
+			g.encode(dst, src, f.s, 1)
+			g.p("\t} else {")
+			g.p("\t\t%s = nil", dst)
+			g.p("\t}")
+		default:
+			g.encode(dst, src, f.s, 1)
+		}
+	}
+	g.p("\treturn obj")
+	g.p("}")
+
+	g.p("")
+	g.p("// UnmarshalJOLT implements jolt.Unmarshaler. Absent fields take their")
+	g.p("// schema default, if any, and are otherwise left unchanged.")
+	g.p("func (%s *%s) UnmarshalJOLT(v any) error { return %s.unmarshalJOLT(v, \"$\") }", recv, st.name, recv)
+	g.p("")
+	g.p("func (%s *%s) unmarshalJOLT(v any, path string) error {", recv, st.name)
+	g.p("\tobj, err := jolt.AsObject(v)")
+	g.p("\tif err != nil {")
+	g.p("\t\treturn fmt.Errorf(\"jolt: %%s: %%w\", path, err)")
+	g.p("\t}")
+	for _, f := range st.fields {
+		g.p("\tif fv, ok := obj[%s]; ok {", strconv.Quote(f.key))
+		g.decodeSlot(recv+"."+f.name, "fv", f.s, f.ptr, "path + "+strconv.Quote("."+f.key), 1)
+		if f.s.Default != nil {
+			g.p("\t} else {")
+			g.decodeSlot(recv+"."+f.name, f.expr+".Default", f.s, f.ptr, "path + "+strconv.Quote("."+f.key), 1)
+		}
+		g.p("\t}")
+	}
+	g.p("\treturn nil")
+	g.p("}")
+}
+
+func (g *goGen) comment(indent, text string) {
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		g.p("%s// %s", indent, strings.TrimSpace(line))
+	}
+}
+
+// encode writes statements storing the JOLT form of the Go expression src,
+// typed as g.typeOf(s), into dst.
+func (g *goGen) encode(dst, src string, s *Schema, d int) {
+	t := g.types[s]
+	if g.isStruct[t] {
+		if strings.HasPrefix(src, "*") {
+			src = "(" + src + ")"
+		}
+		g.p("%s = %s.toJOLT()", dst, src)
+		return
+	}
+	r := s.Resolve()
+	switch {
+	case r.Type == Int:
+		g.p("%s = jolt.BigInt(%s)", dst, src)
+	case (r.Type == Array || r.Type == Set) && r.Items != nil:
+		l, i := fmt.Sprintf("l%d", d), fmt.Sprintf("i%d", d)
+		g.p("{")
+		g.p("%s := make([]any, len(%s))", l, src)
+		g.p("for %s := range %s {", i, src)
+		g.encodeSlot(l+"["+i+"]", src+"["+i+"]", r.Items, d+1)
+		g.p("}")
+		if r.Type == Set {
+			g.p("%s = jolt.Set(%s)", dst, l)
+		} else {
+			g.p("%s = %s", dst, l)
+		}
+		g.p("}")
+	case r.Type == Set:
+		g.p("%s = jolt.Set(%s)", dst, src)
+	case r.Type == Map && strings.HasPrefix(t, "map[string]") && t != "map[string]any":
+		m, k, v := fmt.Sprintf("m%d", d), fmt.Sprintf("k%d", d), fmt.Sprintf("v%d", d)
+		g.p("{")
+		g.p("%s := make(map[string]any, len(%s))", m, src)
+		g.p("for %s, %s := range %s {", k, v, src)
+		g.encodeSlot(m+"["+k+"]", v, r.Values, d+1)
+		g.p("}")
+		g.p("%s = %s", dst, m)
+		g.p("}")
+	default:
+		g.p("%s = %s", dst, src)
+	}
+}
+
+func (g *goGen) encodeSlot(dst, src string, s *Schema, d int) {
+	if g.nullable(s) && isValueType(g.types[s]) {
+		g.p("if %s != nil {", src)
+		g.encode(dst, "*"+src, s, d)
+		g.p("}")
+		return
+	}
+	g.encode(dst, src, s, d)
+}
+
+var asFuncs = map[Type]string{
+	String: "AsString", Bool: "AsBool", Int: "AsInt64", Dec: "AsDecimal", Bin: "AsBinary",
+	TS: "AsTimestamp", Date: "AsDate", Time: "AsTime", UUID: "AsUUID", Link: "AsLink", Annot: "AsAnnot",
+}
+
+// decode writes statements converting the JOLT value src into dst, which has
+// Go type g.typeOf(s). path is a Go string expression used in errors.
+func (g *goGen) decode(dst, src string, s *Schema, path string, d int) {
+	t := g.types[s]
+	if g.isStruct[t] {
+		g.p("if err := %s.unmarshalJOLT(%s, %s); err != nil {", dst, src, path)
+		g.p("return err")
+		g.p("}")
+		return
+	}
+	r := s.Resolve()
+	x := fmt.Sprintf("x%d", d)
+	check := func() {
+		g.p("if err != nil {")
+		g.p("return fmt.Errorf(\"jolt: %%s: %%w\", %s, err)", path)
+		g.p("}")
+	}
+	if fn, ok := asFuncs[r.Type]; ok {
+		g.p("{")
+		g.p("%s, err := jolt.%s(%s)", x, fn, src)
+		check()
+		g.p("%s = %s", dst, x)
+		g.p("}")
+		return
+	}
+	switch {
+	case r.Type == Array || r.Type == Set:
+		l := fmt.Sprintf("l%d", d)
+		g.p("{")
+		g.p("%s, err := jolt.AsList(%s)", l, src)
+		check()
+		if r.Items == nil {
+			g.p("%s = %s", dst, l)
+		} else {
+			g.usesStrconv = true
+			sl, i := fmt.Sprintf("s%d", d), fmt.Sprintf("i%d", d)
+			g.p("%s := make(%s, len(%s))", sl, t, l)
+			g.p("for %s, %s := range %s {", i, x, l)
+			g.decodeSlot(sl+"["+i+"]", x, r.Items, g.nullable(r.Items) && isValueType(g.types[r.Items]),
+				path+" + \"[\" + strconv.Itoa("+i+") + \"]\"", d+1)
+			g.p("}")
+			g.p("%s = %s", dst, sl)
+		}
+		g.p("}")
+	case r.Type == Map && t == "jolt.Map":
+		g.p("{")
+		g.p("%s, err := jolt.AsMap(%s)", x, src)
+		check()
+		g.p("%s = %s", dst, x)
+		g.p("}")
+	case r.Type == Map:
+		m := fmt.Sprintf("m%d", d)
+		g.p("{")
+		g.p("%s, err := jolt.AsStringMap(%s)", m, src)
+		check()
+		if t == "map[string]any" {
+			g.p("%s = %s", dst, m)
+		} else {
+			out, k, v := fmt.Sprintf("o%d", d), fmt.Sprintf("k%d", d), fmt.Sprintf("v%d", d)
+			g.p("%s := make(%s, len(%s))", out, t, m)
+			g.p("for %s, %s := range %s {", k, v, m)
+			g.p("var %s %s", x, strings.TrimPrefix(t, "map[string]"))
+			g.decodeSlot(x, v, r.Values, g.nullable(r.Values) && isValueType(g.types[r.Values]), path+" + \".\" + "+k, d+1)
+			g.p("%s[%s] = %s", out, k, x)
+			g.p("}")
+			g.p("%s = %s", dst, out)
+		}
+		g.p("}")
+	case r.Type == Object:
+		g.p("{")
+		g.p("%s, err := jolt.AsObject(%s)", x, src)
+		check()
+		g.p("%s = %s", dst, x)
+		g.p("}")
+	default:
+		g.p("%s = %s", dst, src)
+	}
+}
+
+// decodeSlot is decode for a destination that may hold null: a pointer is
+// set to nil, other nullable kinds are left at their zero value.
+func (g *goGen) decodeSlot(dst, src string, s *Schema, ptr bool, path string, d int) {
+	t := g.types[s]
+	switch {
+	case ptr:
+		p := fmt.Sprintf("p%d", d)
+		g.p("if %s == nil {", src)
+		g.p("%s = nil", dst)
+		g.p("} else {")
+		g.p("var %s %s", p, t)
+		g.decode(p, src, s, path, d)
+		g.p("%s = &%s", dst, p)
+		g.p("}")
+	case t != "any" && (g.nullable(s) || s.Optional):
+		g.p("if %s != nil {", src)
+		g.decode(dst, src, s, path, d)
+		g.p("}")
+	default:
+		g.decode(dst, src, s, path, d)
+	}
+}
+
+var initialisms = map[string]string{
+	"id": "ID", "uuid": "UUID", "url": "URL", "uri": "URI", "sku": "SKU", "api": "API",
+	"http": "HTTP", "json": "JSON", "ip": "IP", "vat": "VAT",
+}
+
+// goName turns a schema key into an exported Go identifier: "$id" -> "ID",
+// "unit_price" -> "UnitPrice", "createdAt" -> "CreatedAt".
+func goName(s string) string {
+	parts := strings.FieldsFunc(s, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+	var b strings.Builder
+	for _, part := range parts {
+		if up, ok := initialisms[strings.ToLower(part)]; ok {
+			b.WriteString(up)
+			continue
+		}
+		rs := []rune(part)
+		rs[0] = unicode.ToUpper(rs[0])
+		b.WriteString(string(rs))
+	}
+	name := b.String()
+	if name == "" || !unicode.IsLetter([]rune(name)[0]) {
+		name = "X" + name
+	}
+	return name
+}
+
+func article(name string) string {
+	if strings.ContainsRune("AEIOU", rune(name[0])) {
+		return "an " + name
+	}
+	return "a " + name
+}
+
+func lowerFirst(s string) string {
+	for i, r := range s {
+		if !unicode.IsUpper(r) {
+			if i > 1 {
+				i--
+			}
+			return strings.ToLower(s[:i]) + s[i:]
+		}
+	}
+	return strings.ToLower(s)
+}
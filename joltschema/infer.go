@@ -0,0 +1,212 @@
+package joltschema
+
+import (
+	"math"
+	"sort"
+
+	"github.com/chandan-cmd-dev/jolt-go/jolt"
+)
+
+// Infer drafts a schema that accepts every sample. Envelopes (and
+// {"$meta","$body"} objects) contribute their body. Observed kinds are unioned
+// per position: int and dec widen to dec, other mixes become any. Object
+// fields missing from some samples are optional, with the fraction of objects
+// that had them recorded as "x-presence". Strings that all look like UUIDs,
+// timestamps, dates or times keep type string and gain a "format" annotation;
+// promote them to the JOLT type once producers send typed values.
+func Infer(samples ...any) *Schema {
+	n := &inferNode{}
+	for _, v := range samples {
+		if env, err := jolt.AsEnvelope(v); err == nil {
+			v = env.Body
+		}
+		n.add(v)
+	}
+	s := n.schema()
+	_ = s.Link()
+	return s
+}
+
+type inferNode struct {
+	seen, nulls int
+	kinds       map[Type]int
+
+	strs    int
+	formats map[string]int
+
+	items        *inferNode // array, set
+	keys, values *inferNode // map
+
+	objects int
+	fields  map[string]*inferNode
+}
+
+func (n *inferNode) add(v any) {
+	n.seen++
+	if v == nil {
+		n.nulls++
+		return
+	}
+	k := KindOf(v)
+	if n.kinds == nil {
+		n.kinds = map[Type]int{}
+	}
+	n.kinds[k]++
+	switch x := v.(type) {
+	case string:
+		n.strs++
+		if f := stringFormat(x); f != "" {
+			if n.formats == nil {
+				n.formats = map[string]int{}
+			}
+			n.formats[f]++
+		}
+	case []any:
+		n.addItems(x)
+	case jolt.Set:
+		n.addItems(x)
+	case jolt.Map:
+		for mk, mv := range x {
+			n.addEntry(mk, mv)
+		}
+	case map[string]any:
+		n.objects++
+		if n.fields == nil {
+			n.fields = map[string]*inferNode{}
+		}
+		for fk, fv := range x {
+			f := n.fields[fk]
+			if f == nil {
+				f = &inferNode{}
+				n.fields[fk] = f
+			}
+			f.add(fv)
+			// Also as a Map entry, should Map and object samples mix.
+			n.addEntry(fk, fv)
+		}
+	}
+}
+
+func (n *inferNode) addEntry(k, v any) {
+	if n.keys == nil {
+		n.keys, n.values = &inferNode{}, &inferNode{}
+	}
+	n.keys.add(k)
+	n.values.add(v)
+}
+
+func (n *inferNode) addItems(items []any) {
+	if n.items == nil {
+		n.items = &inferNode{}
+	}
+	for _, it := range items {
+		n.items.add(it)
+	}
+}
+
+func (n *inferNode) schema() *Schema {
+	s := &Schema{Type: n.kind()}
+	s.Nullable = n.nulls > 0 && s.Type != Null && s.Type != Any
+	switch s.Type {
+	case String:
+		for f, c := range n.formats {
+			if c == n.strs {
+				s.Extra = map[string]any{"format": f}
+			}
+		}
+	case Array, Set:
+		if n.items != nil && n.items.seen > 0 {
+			s.Items = n.items.schema()
+		}
+	case Map:
+		if n.keys != nil && n.keys.seen > 0 {
+			s.Keys, s.Values = n.keys.schema(), n.values.schema()
+		}
+	case Object:
+		s.Fields = map[string]*Schema{}
+		for _, name := range sortedKeys(n.fields) {
+			f := n.fields[name]
+			fs := f.schema()
+			if present := f.seen; present < n.objects {
+				fs.Optional = true
+				if fs.Extra == nil {
+					fs.Extra = map[string]any{}
+				}
+				fs.Extra["x-presence"] = presence(present, n.objects)
+			}
+			s.Fields[name] = fs
+		}
+	case Any:
+		if len(n.kinds) > 1 {
+			var observed []any
+			for _, k := range sortedTypes(n.kinds) {
+				observed = append(observed, string(k))
+			}
+			s.Extra = map[string]any{"x-observed": observed}
+		}
+	}
+	return s
+}
+
+// kind unions the observed kinds into one schema type.
+func (n *inferNode) kind() Type {
+	switch len(n.kinds) {
+	case 0:
+		if n.nulls > 0 {
+			return Null
+		}
+		return Any
+	case 1:
+		for k := range n.kinds {
+			return k
+		}
+	case 2:
+		switch {
+		case n.kinds[Int] > 0 && n.kinds[Dec] > 0:
+			return Dec
+		case n.kinds[Array] > 0 && n.kinds[Set] > 0:
+			return Array
+		case n.kinds[Map] > 0 && n.kinds[Object] > 0:
+			return Map
+		}
+	}
+	return Any
+}
+
+func stringFormat(s string) string {
+	switch {
+	case len(s) == 36:
+		if _, err := jolt.UUIDFromString(s); err == nil {
+			return "uuid"
+		}
+	case len(s) == 10:
+		if _, err := parseTemporal(Date, s); err == nil {
+			return "date"
+		}
+	}
+	if len(s) >= 20 {
+		if _, err := parseTemporal(TS, s); err == nil {
+			return "ts"
+		}
+	}
+	if len(s) >= 8 && len(s) <= 18 {
+		if _, err := parseTemporal(Time, s); err == nil {
+			return "time"
+		}
+	}
+	return ""
+}
+
+// presence returns present/total rounded to two places.
+func presence(present, total int) float64 {
+	return math.Round(float64(present)*100/float64(total)) / 100
+}
+
+func sortedTypes(m map[Type]int) []Type {
+	ks := make([]Type, 0, len(m))
+	for k := range m {
+		ks = append(ks, k)
+	}
+	sort.Slice(ks, func(i, j int) bool { return ks[i] < ks[j] })
+	return ks
+}
@@ -0,0 +1,702 @@
+package joltschema
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/cockroachdb/apd/v3"
+
+	"github.com/chandan-cmd-dev/jolt-go/jolt"
+)
+
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// FromJSONSchema converts a JSON Schema (draft 2020-12) document. Plain JSON
+// types map onto JOLT ones: integer is int, number is dec, string formats
+// uuid, date-time, date and time (and byte or base64 content) become uuid, ts,
+// date, time and bin, uniqueItems arrays become sets and objects that only
+// constrain additionalProperties become maps. An "x-jolt-type" annotation
+// overrides the mapping.
+//
+// Keywords with no JOLT equivalent (allOf, if/then, patternProperties, ...)
+// are kept in Extra and listed in the returned report as "path: message";
+// the report is empty when the conversion is exact.
+func FromJSONSchema(data []byte) (*Schema, []string, error) {
+	v, err := jolt.UnmarshalJSONTyped(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("joltschema: %w", err)
+	}
+	c := &jsConv{}
+	val, err := c.fromJS(v, "#")
+	if err != nil {
+		return nil, c.report, err
+	}
+	s, err := FromValue(val)
+	if err != nil {
+		return nil, c.report, err
+	}
+	typeValues(s)
+	return s, c.report, nil
+}
+
+// ToJSONSchema writes s as a JSON Schema (draft 2020-12) document describing
+// the plain JSON form of the data. JOLT types JSON Schema cannot tell apart
+// are marked with "x-jolt-type", so FromJSONSchema restores them. Constraints
+// JSON Schema cannot express (temporal bounds, non-string map keys) are
+// written as x-jolt-* annotations and listed in the report.
+func ToJSONSchema(s *Schema) ([]byte, []string, error) {
+	c := &jsConv{}
+	m := c.toJS(s, "#")
+	m["$schema"] = jsonSchemaDialect
+	if s.ID != "" {
+		m["$id"] = s.ID
+	}
+	b, err := json.MarshalIndent(plainJSON(m), "", "  ")
+	if err != nil {
+		return nil, c.report, err
+	}
+	return append(b, '\n'), c.report, nil
+}
+
+type jsConv struct {
+	report []string
+}
+
+func (c *jsConv) note(path, format string, a ...any) {
+	c.report = append(c.report, path+": "+fmt.Sprintf(format, a...))
+}
+
+// jsAnnotations are JSON Schema keywords that carry no validation and are
+// kept in Extra without a report entry.
+var jsAnnotations = map[string]bool{
+	"title": true, "examples": true, "deprecated": true, "readOnly": true, "writeOnly": true,
+	"$comment": true, "$anchor": true, "contentMediaType": true, "format": true,
+}
+
+// joltKeywords are the keys FromValue interprets; unsupported JSON Schema
+// keywords with these names are renamed rather than misread.
+var joltKeywords = map[string]bool{
+	"id": true, "doc": true, "type": true, "ref": true, "nullable": true, "optional": true,
+	"closed": true, "additional": true, "default": true, "enum": true, "min": true, "max": true,
+	"minLen": true, "maxLen": true, "precision": true, "scale": true, "pattern": true,
+	"items": true, "keys": true, "values": true, "fields": true, "defs": true,
+}
+
+// fromJS translates one JSON Schema object into the JOLT schema value form
+// read by FromValue.
+func (c *jsConv) fromJS(v any, path string) (map[string]any, error) {
+	if b, ok := v.(bool); ok {
+		if !b {
+			c.note(path, "false schema is not supported; accepting anything")
+		}
+		return map[string]any{"type": string(Any)}, nil
+	}
+	m, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("joltschema: %s: schema must be an object or boolean, got %T", path, v)
+	}
+	m = mergeNullableUnion(m)
+	out := map[string]any{}
+	extra := func(k string) {
+		if joltKeywords[k] {
+			out["x-jsonschema-"+k] = m[k]
+		} else {
+			out[k] = m[k]
+		}
+		if !jsAnnotations[k] && !strings.HasPrefix(k, "x-") {
+			c.note(path, "keyword %q is not supported", k)
+		}
+	}
+
+	// Type, nullability and the JOLT type.
+	var types []string
+	switch t := m["type"].(type) {
+	case string:
+		types = []string{t}
+	case []any:
+		for _, it := range t {
+			if s, ok := it.(string); ok {
+				types = append(types, s)
+			}
+		}
+	}
+	nullable := m["nullable"] == true // OpenAPI 3.0
+	var nonNull []string
+	for _, t := range types {
+		if t == "null" {
+			nullable = true
+		} else {
+			nonNull = append(nonNull, t)
+		}
+	}
+	jt := Type("")
+	switch {
+	case m["x-jolt-type"] != nil:
+		s, _ := m["x-jolt-type"].(string)
+		if !knownTypes[Type(s)] {
+			return nil, fmt.Errorf("joltschema: %s: unknown x-jolt-type %v", path, m["x-jolt-type"])
+		}
+		jt = Type(s)
+	case len(nonNull) > 1:
+		c.note(path, "union of types %v is not supported; using any", nonNull)
+		jt = Any
+	case len(nonNull) == 1:
+		jt = jsTypeOf(nonNull[0], m)
+	case len(types) == 1: // only "null"
+		jt = Null
+	case m["properties"] != nil:
+		jt = Object
+	case m["items"] != nil:
+		jt = Array
+		if m["uniqueItems"] == true {
+			jt = Set
+		}
+	}
+	if jt == Object && m["properties"] == nil {
+		if _, isSchema := m["additionalProperties"].(map[string]any); isSchema {
+			jt = Map
+		}
+	}
+	if jt != "" {
+		out["type"] = string(jt)
+	}
+	if nullable && jt != Null {
+		out["nullable"] = true
+	}
+	if f, ok := m["format"].(string); ok && !formatImpliedBy(jt, f) {
+		out["format"] = f
+	}
+
+	for _, k := range sortedKeys(m) {
+		val := m[k]
+		switch k {
+		case "type", "nullable", "x-jolt-type", "format", "$schema", "contentEncoding":
+		case "$id":
+			out["id"] = val
+		case "description":
+			out["doc"] = val
+		case "$ref":
+			ref, _ := val.(string)
+			name, ok := strings.CutPrefix(ref, "#/$defs/")
+			if !ok {
+				name, ok = strings.CutPrefix(ref, "#/definitions/")
+			}
+			if !ok {
+				c.note(path, "$ref %q is not supported; using any", ref)
+				out["type"] = string(Any)
+				continue
+			}
+			out["ref"] = name
+			delete(out, "type")
+		case "default", "pattern":
+			out[k] = val
+		case "enum":
+			out["enum"] = val
+		case "const":
+			out["enum"] = []any{val}
+		case "minimum", "x-jolt-min":
+			out["min"] = val
+		case "maximum", "x-jolt-max":
+			out["max"] = val
+		case "exclusiveMinimum", "exclusiveMaximum":
+			n, ok := val.(jolt.Int)
+			if !ok || jt != Int {
+				extra(k)
+				continue
+			}
+			if k == "exclusiveMinimum" {
+				out["min"] = jolt.Int{V: new(big.Int).Add(n.V, big.NewInt(1))}
+			} else {
+				out["max"] = jolt.Int{V: new(big.Int).Sub(n.V, big.NewInt(1))}
+			}
+		case "multipleOf":
+			if scale, ok := decimalScale(val); ok && (jt == Dec || (jt == Int && scale == 0)) {
+				if jt == Dec {
+					if _, set := m["x-jolt-scale"]; !set {
+						out["scale"] = jolt.BigInt(int64(scale))
+					}
+				}
+				continue
+			}
+			extra(k)
+		case "x-jolt-scale", "x-jolt-precision":
+			out[strings.TrimPrefix(k, "x-jolt-")] = val
+		case "minLength", "minItems":
+			out["minLen"] = val
+		case "maxLength", "maxItems":
+			out["maxLen"] = val
+		case "minProperties", "maxProperties":
+			if jt != Map {
+				extra(k)
+				continue
+			}
+			out[map[string]string{"minProperties": "minLen", "maxProperties": "maxLen"}[k]] = val
+		case "uniqueItems":
+			if val == true && jt != Set {
+				c.note(path, "uniqueItems on a %s is not supported", jt)
+				out[k] = val
+			}
+		case "items":
+			sub, err := c.fromJS(val, path+"/items")
+			if err != nil {
+				return nil, err
+			}
+			out["items"] = sub
+		case "properties":
+			props, ok := val.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("joltschema: %s/properties: want an object", path)
+			}
+			required := map[string]bool{}
+			if req, ok := m["required"].([]any); ok {
+				for _, r := range req {
+					if s, ok := r.(string); ok {
+						required[s] = true
+					}
+				}
+			}
+			fields := map[string]any{}
+			for _, name := range sortedKeys(props) {
+				sub, err := c.fromJS(props[name], path+"/properties/"+name)
+				if err != nil {
+					return nil, err
+				}
+				if _, hasDefault := sub["default"]; !required[name] && !hasDefault {
+					sub["optional"] = true
+				}
+				fields[name] = sub
+			}
+			out["fields"] = fields
+		case "required":
+			if m["properties"] == nil {
+				extra(k)
+			}
+		case "additionalProperties":
+			switch x := val.(type) {
+			case bool:
+				if !x && jt != Map {
+					out["additional"] = false
+				}
+			case map[string]any:
+				if jt != Map {
+					extra(k)
+					continue
+				}
+				sub, err := c.fromJS(x, path+"/additionalProperties")
+				if err != nil {
+					return nil, err
+				}
+				out["values"] = sub
+			}
+		case "propertyNames", "x-jolt-keys":
+			if jt != Map {
+				extra(k)
+				continue
+			}
+			sub, err := c.fromJS(val, path+"/"+k)
+			if err != nil {
+				return nil, err
+			}
+			out["keys"] = sub
+		case "$defs", "definitions":
+			defs, ok := val.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("joltschema: %s/%s: want an object", path, k)
+			}
+			out2 := map[string]any{}
+			for _, name := range sortedKeys(defs) {
+				sub, err := c.fromJS(defs[name], path+"/"+k+"/"+name)
+				if err != nil {
+					return nil, err
+				}
+				out2[name] = sub
+			}
+			out["defs"] = out2
+		default:
+			extra(k)
+		}
+	}
+	return out, nil
+}
+
+// mergeNullableUnion folds {"anyOf": [X, {"type": "null"}]} (or oneOf), the
+// usual way to make a $ref nullable, into X with nullable set.
+func mergeNullableUnion(m map[string]any) map[string]any {
+	for _, k := range []string{"anyOf", "oneOf"} {
+		alts, ok := m[k].([]any)
+		if !ok || len(alts) != 2 {
+			continue
+		}
+		for i, alt := range alts {
+			am, ok := alt.(map[string]any)
+			if !ok || len(am) != 1 || am["type"] != "null" {
+				continue
+			}
+			other, ok := alts[1-i].(map[string]any)
+			if !ok {
+				continue
+			}
+			out := make(map[string]any, len(m)+len(other))
+			for mk, mv := range m {
+				if mk != k {
+					out[mk] = mv
+				}
+			}
+			for mk, mv := range other {
+				out[mk] = mv
+			}
+			out["nullable"] = true
+			return out
+		}
+	}
+	return m
+}
+
+func jsTypeOf(t string, m map[string]any) Type {
+	format, _ := m["format"].(string)
+	switch t {
+	case "string":
+		switch format {
+		case "uuid":
+			return UUID
+		case "date-time":
+			return TS
+		case "date":
+			return Date
+		case "time":
+			return Time
+		case "byte":
+			return Bin
+		}
+		if m["contentEncoding"] == "base64" {
+			return Bin
+		}
+		return String
+	case "integer":
+		return Int
+	case "number":
+		return Dec
+	case "boolean":
+		return Bool
+	case "array":
+		if m["uniqueItems"] == true {
+			return Set
+		}
+		return Array
+	case "object":
+		return Object
+	}
+	return Any
+}
+
+func formatImpliedBy(t Type, format string) bool {
+	switch t {
+	case UUID:
+		return format == "uuid"
+	case TS:
+		return format == "date-time"
+	case Date:
+		return format == "date"
+	case Time:
+		return format == "time"
+	case Bin:
+		return format == "byte"
+	}
+	return false
+}
+
+// decimalScale reports whether v is a power of ten no greater than one and
+// returns the number of decimal places it allows (0.01 -> 2).
+func decimalScale(v any) (int, bool) {
+	d, ok := decimalOf(v)
+	if !ok {
+		return 0, false
+	}
+	var r apd.Decimal
+	r.Reduce(d)
+	if r.Negative || r.Coeff.Cmp(apd.NewBigInt(1)) != 0 || r.Exponent > 0 {
+		return 0, false
+	}
+	return int(-r.Exponent), true
+}
+
+// typeValues converts the plain JSON defaults and enum members of an
+// imported schema to the JOLT types the schema declares.
+func typeValues(s *Schema) {
+	if s.Default != nil {
+		s.Default = typedValue(s, s.Default)
+	}
+	for i, e := range s.Enum {
+		s.Enum[i] = typedValue(s, e)
+	}
+	for _, sub := range s.children() {
+		typeValues(sub)
+	}
+}
+
+func typedValue(s *Schema, v any) any {
+	if s == nil {
+		return v
+	}
+	r := s.Resolve()
+	switch x := v.(type) {
+	case string:
+		switch r.Type {
+		case TS:
+			return jolt.Timestamp{RFC3339: x}
+		case Date:
+			return jolt.Date{YYYYMMDD: x}
+		case Time:
+			return jolt.Time{HHMMSS: x}
+		case UUID:
+			if u, err := jolt.UUIDFromString(x); err == nil {
+				return u
+			}
+		case Bin:
+			if b, err := base64.StdEncoding.DecodeString(x); err == nil {
+				return jolt.Binary(b)
+			}
+		}
+	case jolt.Int:
+		if r.Type == Dec {
+			d, _ := jolt.AsDecimal(x)
+			return d
+		}
+	case []any:
+		out := make([]any, len(x))
+		for i, it := range x {
+			out[i] = typedValue(r.Items, it)
+		}
+		if r.Type == Set {
+			return jolt.Set(out)
+		}
+		return out
+	case map[string]any:
+		out := make(map[string]any, len(x))
+		for k, it := range x {
+			if r.Type == Map {
+				out[k] = typedValue(r.Values, it)
+			} else {
+				out[k] = typedValue(r.Fields[k], it)
+			}
+		}
+		return out
+	}
+	return v
+}
+
+// toJS translates s into a JSON Schema object.
+func (c *jsConv) toJS(s *Schema, path string) map[string]any {
+	m := map[string]any{}
+	for k, v := range s.Extra {
+		m[k] = v
+	}
+	if s.Doc != "" {
+		m["description"] = s.Doc
+	}
+	if s.Ref != "" {
+		ref := map[string]any{"$ref": "#/$defs/" + s.Ref}
+		if !s.Nullable {
+			for k, v := range ref {
+				m[k] = v
+			}
+			return m
+		}
+		m["anyOf"] = []any{ref, map[string]any{"type": "null"}}
+		return m
+	}
+
+	jsType, format := "", ""
+	switch s.Type {
+	case Null:
+		jsType = "null"
+	case Bool:
+		jsType = "boolean"
+	case String:
+		jsType = "string"
+	case Int:
+		jsType = "integer"
+	case Dec:
+		jsType = "number"
+	case Bin:
+		jsType, format = "string", "byte"
+		m["contentEncoding"] = "base64"
+	case TS:
+		jsType, format = "string", "date-time"
+	case Date:
+		jsType, format = "string", "date"
+	case Time:
+		jsType, format = "string", "time"
+	case UUID:
+		jsType, format = "string", "uuid"
+	case Link, Annot:
+		jsType = "string"
+	case Array, Set:
+		jsType = "array"
+	case Map, Object:
+		jsType = "object"
+	}
+	switch s.Type {
+	case Dec, Bin, Link, Annot, Set, Map:
+		m["x-jolt-type"] = string(s.Type)
+	}
+	if format != "" {
+		m["format"] = format
+	}
+	switch {
+	case jsType == "" || jsType == "null":
+		if jsType != "" {
+			m["type"] = jsType
+		}
+	case s.Nullable:
+		m["type"] = []any{jsType, "null"}
+	default:
+		m["type"] = jsType
+	}
+
+	if s.Default != nil {
+		m["default"] = s.Default
+	}
+	if len(s.Enum) == 1 {
+		m["const"] = s.Enum[0]
+	} else if s.Enum != nil {
+		m["enum"] = s.Enum
+	}
+	if s.Pattern != "" {
+		m["pattern"] = s.Pattern
+	}
+	switch s.Type {
+	case TS, Date, Time:
+		if s.Min != nil {
+			m["x-jolt-min"] = s.Min
+			c.note(path, "minimum on %s has no JSON Schema keyword; written as x-jolt-min", s.Type)
+		}
+		if s.Max != nil {
+			m["x-jolt-max"] = s.Max
+			c.note(path, "maximum on %s has no JSON Schema keyword; written as x-jolt-max", s.Type)
+		}
+	default:
+		if s.Min != nil {
+			m["minimum"] = s.Min
+		}
+		if s.Max != nil {
+			m["maximum"] = s.Max
+		}
+	}
+	if s.Scale != nil {
+		m["x-jolt-scale"] = jolt.BigInt(int64(*s.Scale))
+		var step apd.Decimal
+		step.SetFinite(1, -int32(*s.Scale))
+		m["multipleOf"] = jolt.Decimal{D: step}
+	}
+	if s.Precision != nil {
+		m["x-jolt-precision"] = jolt.BigInt(int64(*s.Precision))
+	}
+	lenKeys := map[string][2]string{
+		"string": {"minLength", "maxLength"}, "array": {"minItems", "maxItems"}, "object": {"minProperties", "maxProperties"},
+	}
+	if keys, ok := lenKeys[jsType]; ok && s.Type != Bin && s.Type != Object {
+		if s.MinLen != nil {
+			m[keys[0]] = jolt.BigInt(int64(*s.MinLen))
+		}
+		if s.MaxLen != nil {
+			m[keys[1]] = jolt.BigInt(int64(*s.MaxLen))
+		}
+	} else if s.MinLen != nil || s.MaxLen != nil {
+		c.note(path, "minLen/maxLen on %s is not representable", s.Type)
+	}
+
+	switch s.Type {
+	case Array, Set:
+		if s.Type == Set {
+			m["uniqueItems"] = true
+		}
+		if s.Items != nil {
+			m["items"] = c.toJS(s.Items, path+"/items")
+		}
+	case Map:
+		if s.Values != nil {
+			m["additionalProperties"] = c.toJS(s.Values, path+"/additionalProperties")
+		}
+		if s.Keys != nil {
+			if k := s.Keys.Resolve(); k.Type == String || k.Type == Any {
+				m["propertyNames"] = c.toJS(s.Keys, path+"/propertyNames")
+			} else {
+				m["x-jolt-keys"] = c.toJS(s.Keys, path+"/x-jolt-keys")
+				c.note(path, "map keys of type %s cannot be JSON object keys; written as x-jolt-keys", k.Type)
+			}
+		}
+	case Object:
+		if s.Fields != nil {
+			props := map[string]any{}
+			var required []any
+			for _, name := range sortedKeys(s.Fields) {
+				fs := s.Fields[name]
+				props[name] = c.toJS(fs, path+"/properties/"+name)
+				if !fs.Optional && fs.Default == nil {
+					required = append(required, name)
+				}
+			}
+			m["properties"] = props
+			if required != nil {
+				m["required"] = required
+			}
+		}
+		if s.Closed {
+			m["additionalProperties"] = false
+		}
+	}
+	if s.Defs != nil {
+		defs := map[string]any{}
+		for _, name := range sortedKeys(s.Defs) {
+			defs[name] = c.toJS(s.Defs[name], path+"/$defs/"+name)
+		}
+		m["$defs"] = defs
+	}
+	return m
+}
+
+// plainJSON turns JOLT values into what encoding/json writes as plain JSON:
+// numbers stay exact, typed strings lose their wrapper.
+func plainJSON(v any) any {
+	switch x := v.(type) {
+	case jolt.Int:
+		return json.Number(x.V.String())
+	case jolt.Decimal:
+		return json.Number(x.D.Text('f'))
+	case jolt.Timestamp, jolt.Date, jolt.Time:
+		return temporalString(x)
+	case jolt.UUID:
+		return x.String()
+	case jolt.Binary:
+		return base64.StdEncoding.EncodeToString(x)
+	case jolt.Link:
+		return x.Ref
+	case jolt.Annot:
+		return x.Note
+	case jolt.Set:
+		return plainJSON([]any(x))
+	case []any:
+		out := make([]any, len(x))
+		for i, it := range x {
+			out[i] = plainJSON(it)
+		}
+		return out
+	case map[string]any:
+		out := make(map[string]any, len(x))
+		for k, it := range x {
+			out[k] = plainJSON(it)
+		}
+		return out
+	case jolt.Map:
+		out := make(map[string]any, len(x))
+		for _, k := range sortedMapKeys(x) {
+			out[fmt.Sprint(plainJSON(k))] = plainJSON(x[k])
+		}
+		return out
+	}
+	return v
+}
@@ -0,0 +1,103 @@
+package joltschema
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/chandan-cmd-dev/jolt-go/jolt"
+)
+
+var ErrNoSchema = errors.New("joltschema: envelope names no schema")
+
+// Loader resolves a Meta.Schema reference to a schema.
+type Loader interface {
+	Load(ref string) (*Schema, error)
+}
+
+// LoaderFunc adapts a function to Loader.
+type LoaderFunc func(ref string) (*Schema, error)
+
+func (f LoaderFunc) Load(ref string) (*Schema, error) { return f(ref) }
+
+// MapLoader serves schemas registered in memory.
+type MapLoader map[string]*Schema
+
+func (m MapLoader) Load(ref string) (*Schema, error) {
+	s, ok := m[ref]
+	if !ok {
+		return nil, fmt.Errorf("joltschema: schema %q not found", ref)
+	}
+	return s, nil
+}
+
+// DirLoader reads schemas from files in Dir. A reference is mapped to a file
+// name by taking its last path segment ("urn:jolt:schema/Order" -> "Order")
+// and trying the .jsonc, .json and .jb extensions in that order. Parsed
+// schemas are cached.
+type DirLoader struct {
+	Dir string
+
+	mu    sync.Mutex
+	cache map[string]*Schema
+}
+
+func (d *DirLoader) Load(ref string) (*Schema, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if s, ok := d.cache[ref]; ok {
+		return s, nil
+	}
+	name := ref
+	if i := strings.LastIndexAny(name, "/:"); i >= 0 {
+		name = name[i+1:]
+	}
+	if name == "" || name == "." || name == ".." {
+		return nil, fmt.Errorf("joltschema: bad schema reference %q", ref)
+	}
+	for _, ext := range []string{".jsonc", ".json", ".jb"} {
+		data, err := os.ReadFile(filepath.Join(d.Dir, name+ext))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		var s *Schema
+		if ext == ".jb" {
+			s, err = ParseBinary(data)
+		} else {
+			s, err = Parse(data)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name+ext, err)
+		}
+		if d.cache == nil {
+			d.cache = map[string]*Schema{}
+		}
+		d.cache[ref] = s
+		return s, nil
+	}
+	return nil, fmt.Errorf("joltschema: schema %q not found in %s", ref, d.Dir)
+}
+
+// ValidateEnvelope validates env.Body against the schema named by
+// env.Meta.Schema. The error reports loader failures; violations are
+// returned separately.
+func ValidateEnvelope(env jolt.Envelope, l Loader) (Violations, error) {
+	if env.Meta.Schema == "" {
+		return nil, ErrNoSchema
+	}
+	s, err := l.Load(env.Meta.Schema)
+	if err != nil {
+		return nil, err
+	}
+	vs := Validate(s, env.Body)
+	for i := range vs {
+		vs[i].Path = "$body" + strings.TrimPrefix(vs[i].Path, "$")
+	}
+	return vs, nil
+}
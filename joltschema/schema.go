@@ -0,0 +1,499 @@
+// Package joltschema describes and validates JOLT documents. Schemas are
+// themselves JOLT values, usually written as JSONC:
+//
+//	{
+//	  "id": "urn:jolt:schema/Order",
+//	  "type": "object",
+//	  "fields": {
+//	    "number": { "type": "string", "pattern": "^SO-[0-9]+$" },
+//	    "qty":    { "type": "int", "min": 1 },
+//	    "price":  { "type": "dec", "precision": 10, "scale": 2 },
+//	    "tags":   { "type": "set", "items": { "type": "string" }, "optional": true },
+//	    "lines":  { "type": "array", "items": { "ref": "Line" } }
+//	  },
+//	  "defs": { "Line": { "type": "object", "fields": { "sku": { "type": "string" } } } }
+//	}
+package joltschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/chandan-cmd-dev/jolt-go/jolt"
+)
+
+// Type names a JOLT value kind.
+type Type string
+
+const (
+	Any    Type = "any"
+	Null   Type = "null"
+	Bool   Type = "bool"
+	String Type = "string"
+	Int    Type = "int"
+	Dec    Type = "dec"
+	Bin    Type = "bin"
+	TS     Type = "ts"
+	Date   Type = "date"
+	Time   Type = "time"
+	UUID   Type = "uuid"
+	Link   Type = "link"
+	Annot  Type = "annot"
+	Array  Type = "array"
+	Set    Type = "set"
+	Map    Type = "map"
+	Object Type = "object"
+)
+
+var knownTypes = map[Type]bool{
+	Any: true, Null: true, Bool: true, String: true, Int: true, Dec: true, Bin: true,
+	TS: true, Date: true, Time: true, UUID: true, Link: true, Annot: true,
+	Array: true, Set: true, Map: true, Object: true,
+}
+
+// Schema constrains one value. The zero Schema accepts anything.
+type Schema struct {
+	ID   string
+	Doc  string
+	Type Type
+	Ref  string // name of an entry in the root schema's Defs
+
+	Nullable bool
+	Optional bool // as an object field: may be absent (as may fields with a Default)
+	Default  any
+
+	Enum      []any
+	Min, Max  any  // Int, Decimal, Timestamp, Date or Time depending on Type
+	MinLen    *int // characters, bytes or items depending on Type
+	MaxLen    *int
+	Pattern   string // RE2, strings only
+	Precision *int   // dec: total significant digits
+	Scale     *int   // dec: digits after the point
+
+	Items  *Schema // array, set
+	Keys   *Schema // map
+	Values *Schema // map
+	Fields map[string]*Schema
+	Closed bool // object: reject fields not listed in Fields
+
+	Defs map[string]*Schema
+	// Extra keeps keys this package does not interpret (annotations such as
+	// "pii" or "x-go-type") so tools built on schemas can read them.
+	Extra map[string]any
+
+	root    *Schema
+	pattern *regexp.Regexp
+}
+
+// Parse reads a schema from JSONC text.
+func Parse(data []byte) (*Schema, error) {
+	v, err := jolt.UnmarshalJSONTyped(data)
+	if err != nil {
+		return nil, fmt.Errorf("joltschema: %w", err)
+	}
+	return FromValue(v)
+}
+
+// ParseBinary reads a schema stored as JOLT-B.
+func ParseBinary(b []byte) (*Schema, error) {
+	v, err := jolt.DecodeBinary(b)
+	if err != nil {
+		return nil, fmt.Errorf("joltschema: %w", err)
+	}
+	return FromValue(v)
+}
+
+// MustParse is Parse for schemas embedded in code, such as those emitted by
+// cmd/joltgen; it panics on error.
+func MustParse(data []byte) *Schema {
+	s, err := Parse(data)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// FromValue builds a schema from a decoded JOLT value. An Envelope is
+// accepted and its body used.
+func FromValue(v any) (*Schema, error) {
+	if env, ok := v.(jolt.Envelope); ok {
+		v = env.Body
+	}
+	s, err := fromValue(v, "$")
+	if err != nil {
+		return nil, err
+	}
+	if err := s.link(s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func fromValue(v any, path string) (*Schema, error) {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("joltschema: %s: schema must be an object, got %T", path, v)
+	}
+	s := &Schema{}
+	bad := func(k string, want string) error {
+		return fmt.Errorf("joltschema: %s.%s: want %s, got %T", path, k, want, m[k])
+	}
+	for k, val := range m {
+		switch k {
+		case "id", "doc", "ref", "pattern":
+			str, ok := val.(string)
+			if !ok {
+				return nil, bad(k, "a string")
+			}
+			switch k {
+			case "id":
+				s.ID = str
+			case "doc":
+				s.Doc = str
+			case "ref":
+				s.Ref = strings.TrimPrefix(str, "#/defs/")
+			case "pattern":
+				s.Pattern = str
+			}
+		case "type":
+			str, ok := val.(string)
+			if !ok || !knownTypes[Type(str)] {
+				return nil, fmt.Errorf("joltschema: %s.type: unknown type %v", path, val)
+			}
+			s.Type = Type(str)
+		case "nullable", "optional", "closed":
+			b, ok := val.(bool)
+			if !ok {
+				return nil, bad(k, "a bool")
+			}
+			switch k {
+			case "nullable":
+				s.Nullable = b
+			case "optional":
+				s.Optional = b
+			case "closed":
+				s.Closed = b
+			}
+		case "additional":
+			b, ok := val.(bool)
+			if !ok {
+				return nil, bad(k, "a bool")
+			}
+			s.Closed = !b
+		case "default":
+			s.Default = val
+		case "enum":
+			arr, ok := val.([]any)
+			if !ok {
+				return nil, bad(k, "an array")
+			}
+			s.Enum = arr
+		case "min", "max":
+			if k == "min" {
+				s.Min = val
+			} else {
+				s.Max = val
+			}
+		case "minLen", "maxLen", "precision", "scale":
+			n, err := intOf(val)
+			if err != nil {
+				return nil, bad(k, "a non-negative int")
+			}
+			switch k {
+			case "minLen":
+				s.MinLen = &n
+			case "maxLen":
+				s.MaxLen = &n
+			case "precision":
+				s.Precision = &n
+			case "scale":
+				s.Scale = &n
+			}
+		case "items", "keys", "values":
+			sub, err := fromValue(val, path+"."+k)
+			if err != nil {
+				return nil, err
+			}
+			switch k {
+			case "items":
+				s.Items = sub
+			case "keys":
+				s.Keys = sub
+			case "values":
+				s.Values = sub
+			}
+		case "fields", "defs":
+			obj, ok := val.(map[string]any)
+			if !ok {
+				return nil, bad(k, "an object")
+			}
+			out := make(map[string]*Schema, len(obj))
+			for name, fv := range obj {
+				sub, err := fromValue(fv, path+"."+k+"."+name)
+				if err != nil {
+					return nil, err
+				}
+				out[name] = sub
+			}
+			if k == "fields" {
+				s.Fields = out
+			} else {
+				s.Defs = out
+			}
+		case "$schema", "$comment":
+		default:
+			if s.Extra == nil {
+				s.Extra = map[string]any{}
+			}
+			s.Extra[k] = val
+		}
+	}
+	if s.Type == "" {
+		switch {
+		case s.Ref != "":
+		case s.Fields != nil:
+			s.Type = Object
+		case s.Keys != nil || s.Values != nil:
+			s.Type = Map
+		case s.Items != nil:
+			s.Type = Array
+		default:
+			s.Type = Any
+		}
+	}
+	if err := s.normalizeBounds(path); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func intOf(v any) (int, error) {
+	switch x := v.(type) {
+	case jolt.Int:
+		if x.V.IsInt64() && x.V.Sign() >= 0 && x.V.Int64() <= 1<<31 {
+			return int(x.V.Int64()), nil
+		}
+	case float64:
+		if x >= 0 && x == float64(int(x)) {
+			return int(x), nil
+		}
+	case int:
+		if x >= 0 {
+			return x, nil
+		}
+	}
+	return 0, fmt.Errorf("not a non-negative int: %v", v)
+}
+
+// normalizeBounds converts min/max into the value kind of Type so comparisons
+// are exact: numbers become Decimal, temporal bounds their JOLT type.
+func (s *Schema) normalizeBounds(path string) error {
+	conv := func(v any) (any, error) {
+		if v == nil {
+			return nil, nil
+		}
+		switch s.Type {
+		case Int, Dec, Any, "":
+			switch x := v.(type) {
+			case jolt.Int, jolt.Decimal:
+				return x, nil
+			case string:
+				if d, err := jolt.DecFromString(x); err == nil {
+					return d, nil
+				}
+			default:
+				if d, ok := decimalOf(v); ok {
+					return jolt.Decimal{D: *d}, nil
+				}
+			}
+			return nil, fmt.Errorf("joltschema: %s: bound %v is not a number", path, v)
+		case TS, Date, Time:
+			str := temporalString(v)
+			if str == "" {
+				return nil, fmt.Errorf("joltschema: %s: bound %v is not a %s", path, v, s.Type)
+			}
+			if _, err := parseTemporal(s.Type, str); err != nil {
+				return nil, fmt.Errorf("joltschema: %s: bound %q: %v", path, str, err)
+			}
+			switch s.Type {
+			case TS:
+				return jolt.Timestamp{RFC3339: str}, nil
+			case Date:
+				return jolt.Date{YYYYMMDD: str}, nil
+			}
+			return jolt.Time{HHMMSS: str}, nil
+		}
+		return nil, fmt.Errorf("joltschema: %s: min/max do not apply to %s (use minLen/maxLen)", path, s.Type)
+	}
+	var err error
+	if s.Min, err = conv(s.Min); err != nil {
+		return err
+	}
+	s.Max, err = conv(s.Max)
+	return err
+}
+
+// link resolves refs against root and compiles patterns.
+func (s *Schema) link(root *Schema) error {
+	s.root = root
+	if s.Ref != "" {
+		if _, ok := root.Defs[s.Ref]; !ok {
+			return fmt.Errorf("joltschema: unresolved ref %q", s.Ref)
+		}
+	}
+	if s.Pattern != "" {
+		re, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return fmt.Errorf("joltschema: bad pattern %q: %w", s.Pattern, err)
+		}
+		s.pattern = re
+	}
+	for _, sub := range s.children() {
+		if err := sub.link(root); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Schema) children() []*Schema {
+	var out []*Schema
+	for _, sub := range []*Schema{s.Items, s.Keys, s.Values} {
+		if sub != nil {
+			out = append(out, sub)
+		}
+	}
+	for _, name := range sortedKeys(s.Fields) {
+		out = append(out, s.Fields[name])
+	}
+	for _, name := range sortedKeys(s.Defs) {
+		out = append(out, s.Defs[name])
+	}
+	return out
+}
+
+// Resolve follows Ref to the definition it names; schemas without a ref
+// resolve to themselves.
+func (s *Schema) Resolve() *Schema {
+	for i := 0; s.Ref != "" && s.root != nil && i < 64; i++ {
+		def, ok := s.root.Defs[s.Ref]
+		if !ok {
+			break
+		}
+		s = def
+	}
+	return s
+}
+
+// defFor returns the definition ref names in root, if any.
+func (root *Schema) defFor(ref string) (*Schema, bool) {
+	if ref == "" || root == nil {
+		return nil, false
+	}
+	def, ok := root.Defs[ref]
+	return def, ok
+}
+
+// Link prepares a schema assembled in Go (rather than parsed) for use:
+// refs are checked against s.Defs and patterns compiled.
+func (s *Schema) Link() error { return s.link(s) }
+
+// ToValue returns the schema as a JOLT value; EncodeBinary on it gives the
+// schema's JOLT-B form and Parse(MarshalJSON) gives it back.
+func (s *Schema) ToValue() map[string]any {
+	m := map[string]any{}
+	for k, v := range s.Extra {
+		m[k] = v
+	}
+	put := func(k string, v string) {
+		if v != "" {
+			m[k] = v
+		}
+	}
+	put("id", s.ID)
+	put("doc", s.Doc)
+	put("ref", s.Ref)
+	put("pattern", s.Pattern)
+	if s.Type != "" && !(s.Ref != "" && s.Type == Any) {
+		m["type"] = string(s.Type)
+	}
+	if s.Nullable {
+		m["nullable"] = true
+	}
+	if s.Optional {
+		m["optional"] = true
+	}
+	if s.Closed {
+		m["additional"] = false
+	}
+	if s.Default != nil {
+		m["default"] = s.Default
+	}
+	if s.Enum != nil {
+		m["enum"] = s.Enum
+	}
+	if s.Min != nil {
+		m["min"] = s.Min
+	}
+	if s.Max != nil {
+		m["max"] = s.Max
+	}
+	for k, p := range map[string]*int{"minLen": s.MinLen, "maxLen": s.MaxLen, "precision": s.Precision, "scale": s.Scale} {
+		if p != nil {
+			m[k] = jolt.Int{V: big.NewInt(int64(*p))}
+		}
+	}
+	if s.Items != nil {
+		m["items"] = s.Items.ToValue()
+	}
+	if s.Keys != nil {
+		m["keys"] = s.Keys.ToValue()
+	}
+	if s.Values != nil {
+		m["values"] = s.Values.ToValue()
+	}
+	if s.Fields != nil {
+		m["fields"] = toValues(s.Fields)
+	}
+	if s.Defs != nil {
+		m["defs"] = toValues(s.Defs)
+	}
+	return m
+}
+
+func toValues(in map[string]*Schema) map[string]any {
+	out := make(map[string]any, len(in))
+	for k, v := range in {
+		out[k] = v.ToValue()
+	}
+	return out
+}
+
+// MarshalJSON writes the schema in its JSON document form.
+func (s *Schema) MarshalJSON() ([]byte, error) {
+	return jolt.MarshalJSONCompat(s.ToValue(), false)
+}
+
+func (s *Schema) UnmarshalJSON(b []byte) error {
+	p, err := Parse(b)
+	if err != nil {
+		return err
+	}
+	*s = *p
+	return s.Link()
+}
+
+var _ json.Marshaler = (*Schema)(nil)
+
+func sortedKeys[V any](m map[string]V) []string {
+	ks := make([]string, 0, len(m))
+	for k := range m {
+		ks = append(ks, k)
+	}
+	sort.Strings(ks)
+	return ks
+}
@@ -0,0 +1,428 @@
+package joltschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/cockroachdb/apd/v3"
+
+	"github.com/chandan-cmd-dev/jolt-go/jolt"
+)
+
+// Violation is one way a value fails its schema. Path uses the same
+// "$.field[0]" form as jolt.DecodeInto errors.
+type Violation struct {
+	Path    string
+	Message string
+}
+
+func (v Violation) String() string { return v.Path + ": " + v.Message }
+
+// Violations lists every problem found by Validate; it is nil for a valid
+// value.
+type Violations []Violation
+
+func (vs Violations) Error() string {
+	parts := make([]string, len(vs))
+	for i, v := range vs {
+		parts[i] = v.String()
+	}
+	return "joltschema: " + strings.Join(parts, "; ")
+}
+
+// Err returns vs as an error, or nil when there are no violations.
+func (vs Violations) Err() error {
+	if len(vs) == 0 {
+		return nil
+	}
+	return vs
+}
+
+// Validate checks v against s and reports all violations.
+func Validate(s *Schema, v any) Violations {
+	var vs Violations
+	validate(s, v, "$", &vs, 0)
+	return vs
+}
+
+func validate(s *Schema, v any, path string, vs *Violations, depth int) {
+	if s == nil {
+		return
+	}
+	if depth > jolt.DefaultLimits.MaxDepth {
+		*vs = append(*vs, Violation{path, "nesting too deep"})
+		return
+	}
+	if def, ok := s.root.defFor(s.Ref); ok {
+		// A referring schema's own constraints (nullable, enum, bounds…)
+		// apply on top of the definition's.
+		if v == nil && s.Nullable {
+			return
+		}
+		before := len(*vs)
+		validate(def, v, path, vs, depth+1)
+		if v != nil && len(*vs) == before {
+			own := *s
+			own.Ref, own.Type = "", def.Resolve().Type
+			validate(&own, v, path, vs, depth+1)
+		}
+		return
+	}
+	add := func(format string, a ...any) {
+		*vs = append(*vs, Violation{path, fmt.Sprintf(format, a...)})
+	}
+	if v == nil {
+		if !s.Nullable && s.Type != Null && s.Type != Any {
+			add("null is not allowed for %s", s.Type)
+		}
+		return
+	}
+	if !matchesType(s.Type, v) {
+		add("want %s, got %s", s.Type, KindOf(v))
+		return
+	}
+	if len(s.Enum) > 0 && !inEnum(s.Enum, v) {
+		add("value %s is not one of the allowed values", short(v))
+	}
+
+	switch s.Type {
+	case String:
+		str := v.(string)
+		checkLen(s, utf8.RuneCountInString(str), "characters", add)
+		if s.pattern != nil && !s.pattern.MatchString(str) {
+			add("%q does not match pattern %q", str, s.Pattern)
+		}
+	case Bin:
+		checkLen(s, len(v.(jolt.Binary)), "bytes", add)
+	case Int, Dec:
+		d, _ := decimalOf(v)
+		checkNumber(s, d, add)
+	case TS, Date, Time:
+		str := temporalString(v)
+		t, err := parseTemporal(s.Type, str)
+		if err != nil {
+			add("%q is not a valid %s", str, s.Type)
+			return
+		}
+		if s.Min != nil {
+			if lo, _ := parseTemporal(s.Type, temporalString(s.Min)); t.Before(lo) {
+				add("%s is before the minimum %s", str, temporalString(s.Min))
+			}
+		}
+		if s.Max != nil {
+			if hi, _ := parseTemporal(s.Type, temporalString(s.Max)); t.After(hi) {
+				add("%s is after the maximum %s", str, temporalString(s.Max))
+			}
+		}
+	case Array:
+		items := v.([]any)
+		checkLen(s, len(items), "items", add)
+		for i, it := range items {
+			validate(s.Items, it, path+"["+strconv.Itoa(i)+"]", vs, depth+1)
+		}
+	case Set:
+		items := v.(jolt.Set)
+		checkLen(s, len(items), "members", add)
+		seen := map[string]bool{}
+		for i, it := range items {
+			if b, err := jolt.EncodeBinary(it); err == nil {
+				if seen[string(b)] {
+					add("duplicate set member %s", short(it))
+				}
+				seen[string(b)] = true
+			}
+			validate(s.Items, it, path+"{"+strconv.Itoa(i)+"}", vs, depth+1)
+		}
+	case Map:
+		switch m := v.(type) {
+		case jolt.Map:
+			checkLen(s, len(m), "entries", add)
+			for _, k := range sortedMapKeys(m) {
+				kp := path + "[" + short(k) + "]"
+				validate(s.Keys, k, kp, vs, depth+1)
+				validate(s.Values, m[k], kp, vs, depth+1)
+			}
+		case map[string]any:
+			checkLen(s, len(m), "entries", add)
+			for _, k := range sortedKeys(m) {
+				kp := path + "[" + strconv.Quote(k) + "]"
+				validate(s.Keys, k, kp, vs, depth+1)
+				validate(s.Values, m[k], kp, vs, depth+1)
+			}
+		}
+	case Object:
+		obj := v.(map[string]any)
+		for _, name := range sortedKeys(s.Fields) {
+			fs := s.Fields[name]
+			fv, ok := obj[name]
+			if !ok {
+				if !fs.Optional && fs.Resolve().Default == nil {
+					*vs = append(*vs, Violation{path + "." + name, "required field is missing"})
+				}
+				continue
+			}
+			validate(fs, fv, path+"."+name, vs, depth+1)
+		}
+		if s.Closed {
+			for _, name := range sortedKeys(obj) {
+				if _, ok := s.Fields[name]; !ok && !strings.HasPrefix(name, "$comment") {
+					*vs = append(*vs, Violation{path + "." + name, "field is not allowed"})
+				}
+			}
+		}
+	}
+}
+
+func checkLen(s *Schema, n int, unit string, add func(string, ...any)) {
+	if s.MinLen != nil && n < *s.MinLen {
+		add("has %d %s, want at least %d", n, unit, *s.MinLen)
+	}
+	if s.MaxLen != nil && n > *s.MaxLen {
+		add("has %d %s, want at most %d", n, unit, *s.MaxLen)
+	}
+}
+
+func checkNumber(s *Schema, d *apd.Decimal, add func(string, ...any)) {
+	if lo, ok := decimalOf(s.Min); ok && d.Cmp(lo) < 0 {
+		add("%s is below the minimum %s", d, lo)
+	}
+	if hi, ok := decimalOf(s.Max); ok && d.Cmp(hi) > 0 {
+		add("%s is above the maximum %s", d, hi)
+	}
+	if s.Type == Int {
+		var r apd.Decimal
+		r.Reduce(d)
+		if r.Exponent < 0 {
+			add("%s is not an integer", d)
+		}
+	}
+	if s.Precision == nil && s.Scale == nil {
+		return
+	}
+	intDigits, fracDigits := digits(d)
+	if s.Scale != nil && fracDigits > *s.Scale {
+		add("%s has %d fractional digits, scale allows %d", d, fracDigits, *s.Scale)
+	}
+	if s.Precision != nil {
+		scale := 0
+		if s.Scale != nil {
+			scale = *s.Scale
+		}
+		if intDigits > *s.Precision-scale {
+			add("%s has %d integer digits, precision %d allows %d", d, intDigits, *s.Precision, *s.Precision-scale)
+		}
+	}
+}
+
+// digits counts the significant integer and fractional digits of d.
+func digits(d *apd.Decimal) (intDigits, fracDigits int) {
+	var r apd.Decimal
+	r.Reduce(d)
+	n := int(r.NumDigits())
+	if r.IsZero() {
+		return 0, 0
+	}
+	if r.Exponent >= 0 {
+		return n + int(r.Exponent), 0
+	}
+	frac := int(-r.Exponent)
+	if n > frac {
+		return n - frac, frac
+	}
+	return 0, frac
+}
+
+// KindOf names the JOLT type of a decoded value, using the same names as
+// schema types.
+func KindOf(v any) Type {
+	switch x := v.(type) {
+	case nil:
+		return Null
+	case bool:
+		return Bool
+	case string:
+		return String
+	case jolt.Int:
+		return Int
+	case jolt.Decimal:
+		return Dec
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return Int
+	case float64:
+		if x == math.Trunc(x) {
+			return Int
+		}
+		return Dec
+	case float32:
+		if float64(x) == math.Trunc(float64(x)) {
+			return Int
+		}
+		return Dec
+	case json.Number:
+		if strings.ContainsAny(string(x), ".eE") {
+			return Dec
+		}
+		return Int
+	case jolt.Binary:
+		return Bin
+	case jolt.Timestamp:
+		return TS
+	case jolt.Date:
+		return Date
+	case jolt.Time:
+		return Time
+	case jolt.UUID:
+		return UUID
+	case jolt.Link:
+		return Link
+	case jolt.Annot:
+		return Annot
+	case []any:
+		return Array
+	case jolt.Set:
+		return Set
+	case jolt.Map:
+		return Map
+	case map[string]any:
+		return Object
+	}
+	return Type(fmt.Sprintf("%T", v))
+}
+
+func matchesType(t Type, v any) bool {
+	k := KindOf(v)
+	switch t {
+	case Any, "":
+		return true
+	case Dec:
+		return k == Dec || k == Int
+	case Map:
+		return k == Map || k == Object
+	}
+	return k == t
+}
+
+// decimalOf returns the exact numeric value of any number-like JOLT value.
+func decimalOf(v any) (*apd.Decimal, bool) {
+	d := new(apd.Decimal)
+	switch x := v.(type) {
+	case jolt.Decimal:
+		d.Set(&x.D)
+	case jolt.Int:
+		d.Coeff.SetMathBigInt(new(big.Int).Abs(x.V))
+		d.Negative = x.V.Sign() < 0
+	case float64:
+		if _, _, err := d.SetString(strconv.FormatFloat(x, 'f', -1, 64)); err != nil {
+			return nil, false
+		}
+	case float32:
+		if _, _, err := d.SetString(strconv.FormatFloat(float64(x), 'f', -1, 32)); err != nil {
+			return nil, false
+		}
+	case json.Number:
+		if _, _, err := d.SetString(string(x)); err != nil {
+			return nil, false
+		}
+	default:
+		rv := reflect.ValueOf(v)
+		switch rv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			d.SetInt64(rv.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			d.Coeff.SetUint64(rv.Uint())
+		default:
+			return nil, false
+		}
+	}
+	return d, true
+}
+
+func temporalString(v any) string {
+	switch x := v.(type) {
+	case jolt.Timestamp:
+		return x.RFC3339
+	case jolt.Date:
+		return x.YYYYMMDD
+	case jolt.Time:
+		return x.HHMMSS
+	case string:
+		return x
+	}
+	return ""
+}
+
+func parseTemporal(t Type, s string) (time.Time, error) {
+	switch t {
+	case TS:
+		return time.Parse(time.RFC3339Nano, s)
+	case Date:
+		return time.Parse("2006-01-02", s)
+	}
+	return time.Parse("15:04:05.999999999", s)
+}
+
+func inEnum(enum []any, v any) bool {
+	b, err := jolt.EncodeBinary(v)
+	if err != nil {
+		return false
+	}
+	for _, e := range enum {
+		if eb, err := jolt.EncodeBinary(e); err == nil && bytes.Equal(b, eb) {
+			return true
+		}
+		if d, ok := decimalOf(v); ok {
+			if ed, ok := decimalOf(e); ok && d.Cmp(ed) == 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func sortedMapKeys(m jolt.Map) []any {
+	type kb struct {
+		k any
+		b []byte
+	}
+	ks := make([]kb, 0, len(m))
+	for k := range m {
+		b, _ := jolt.EncodeBinary(k)
+		ks = append(ks, kb{k, b})
+	}
+	sort.Slice(ks, func(i, j int) bool { return bytes.Compare(ks[i].b, ks[j].b) < 0 })
+	out := make([]any, len(ks))
+	for i, k := range ks {
+		out[i] = k.k
+	}
+	return out
+}
+
+func short(v any) string {
+	switch x := v.(type) {
+	case string:
+		return strconv.Quote(x)
+	case jolt.Int:
+		return x.V.String()
+	case jolt.Decimal:
+		return x.String()
+	case jolt.UUID:
+		return x.String()
+	}
+	if s := temporalString(v); s != "" {
+		return s
+	}
+	b, err := jolt.MarshalJSONCompat(v, false)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}